@@ -225,6 +225,61 @@ func (x *ListKernelsResponse) GetKernels() []*DistributedJupyterKernel {
 	return nil
 }
 
+type KernelEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type   string                    `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`     // One of "added", "updated", "removed", or "synced".
+	Kernel *DistributedJupyterKernel `protobuf:"bytes,2,opt,name=kernel,proto3" json:"kernel,omitempty"` // Not set for "synced" events. For "removed" events, the kernel as it was last seen.
+}
+
+func (x *KernelEvent) Reset() {
+	*x = KernelEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_gateway_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KernelEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KernelEvent) ProtoMessage() {}
+
+func (x *KernelEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_gateway_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KernelEvent.ProtoReflect.Descriptor instead.
+func (*KernelEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *KernelEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *KernelEvent) GetKernel() *DistributedJupyterKernel {
+	if x != nil {
+		return x.Kernel
+	}
+	return nil
+}
+
 type ProvisionerId struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -236,7 +291,7 @@ type ProvisionerId struct {
 func (x *ProvisionerId) Reset() {
 	*x = ProvisionerId{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[3]
+		mi := &file_api_proto_gateway_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -249,7 +304,7 @@ func (x *ProvisionerId) String() string {
 func (*ProvisionerId) ProtoMessage() {}
 
 func (x *ProvisionerId) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[3]
+	mi := &file_api_proto_gateway_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -262,7 +317,7 @@ func (x *ProvisionerId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProvisionerId.ProtoReflect.Descriptor instead.
 func (*ProvisionerId) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{3}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ProvisionerId) GetId() string {
@@ -284,7 +339,7 @@ type HostSpec struct {
 func (x *HostSpec) Reset() {
 	*x = HostSpec{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[4]
+		mi := &file_api_proto_gateway_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -297,7 +352,7 @@ func (x *HostSpec) String() string {
 func (*HostSpec) ProtoMessage() {}
 
 func (x *HostSpec) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[4]
+	mi := &file_api_proto_gateway_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -310,7 +365,7 @@ func (x *HostSpec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostSpec.ProtoReflect.Descriptor instead.
 func (*HostSpec) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{4}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *HostSpec) GetIp() string {
@@ -338,7 +393,7 @@ type HostId struct {
 func (x *HostId) Reset() {
 	*x = HostId{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[5]
+		mi := &file_api_proto_gateway_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -351,7 +406,7 @@ func (x *HostId) String() string {
 func (*HostId) ProtoMessage() {}
 
 func (x *HostId) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[5]
+	mi := &file_api_proto_gateway_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -364,7 +419,7 @@ func (x *HostId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostId.ProtoReflect.Descriptor instead.
 func (*HostId) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{5}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *HostId) GetId() string {
@@ -391,7 +446,7 @@ type KernelReplicaSpec struct {
 func (x *KernelReplicaSpec) Reset() {
 	*x = KernelReplicaSpec{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[6]
+		mi := &file_api_proto_gateway_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -404,7 +459,7 @@ func (x *KernelReplicaSpec) String() string {
 func (*KernelReplicaSpec) ProtoMessage() {}
 
 func (x *KernelReplicaSpec) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[6]
+	mi := &file_api_proto_gateway_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -417,7 +472,7 @@ func (x *KernelReplicaSpec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KernelReplicaSpec.ProtoReflect.Descriptor instead.
 func (*KernelReplicaSpec) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{6}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *KernelReplicaSpec) GetKernel() *KernelSpec {
@@ -475,7 +530,7 @@ type ResourceSpec struct {
 func (x *ResourceSpec) Reset() {
 	*x = ResourceSpec{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[7]
+		mi := &file_api_proto_gateway_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -488,7 +543,7 @@ func (x *ResourceSpec) String() string {
 func (*ResourceSpec) ProtoMessage() {}
 
 func (x *ResourceSpec) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[7]
+	mi := &file_api_proto_gateway_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -501,7 +556,7 @@ func (x *ResourceSpec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResourceSpec.ProtoReflect.Descriptor instead.
 func (*ResourceSpec) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ResourceSpec) GetCpu() int32 {
@@ -539,7 +594,7 @@ type KernelId struct {
 func (x *KernelId) Reset() {
 	*x = KernelId{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[8]
+		mi := &file_api_proto_gateway_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -552,7 +607,7 @@ func (x *KernelId) String() string {
 func (*KernelId) ProtoMessage() {}
 
 func (x *KernelId) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[8]
+	mi := &file_api_proto_gateway_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -565,7 +620,7 @@ func (x *KernelId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KernelId.ProtoReflect.Descriptor instead.
 func (*KernelId) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{8}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *KernelId) GetId() string {
@@ -603,7 +658,7 @@ type ReplicaInfo struct {
 func (x *ReplicaInfo) Reset() {
 	*x = ReplicaInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[9]
+		mi := &file_api_proto_gateway_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -616,7 +671,7 @@ func (x *ReplicaInfo) String() string {
 func (*ReplicaInfo) ProtoMessage() {}
 
 func (x *ReplicaInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[9]
+	mi := &file_api_proto_gateway_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -629,7 +684,7 @@ func (x *ReplicaInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplicaInfo.ProtoReflect.Descriptor instead.
 func (*ReplicaInfo) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{9}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ReplicaInfo) GetKernelId() string {
@@ -666,7 +721,7 @@ type MigrationRequest struct {
 func (x *MigrationRequest) Reset() {
 	*x = MigrationRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[10]
+		mi := &file_api_proto_gateway_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -679,7 +734,7 @@ func (x *MigrationRequest) String() string {
 func (*MigrationRequest) ProtoMessage() {}
 
 func (x *MigrationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[10]
+	mi := &file_api_proto_gateway_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -692,7 +747,7 @@ func (x *MigrationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MigrationRequest.ProtoReflect.Descriptor instead.
 func (*MigrationRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{10}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *MigrationRequest) GetTargetReplica() *ReplicaInfo {
@@ -730,7 +785,7 @@ type SmrReadyNotification struct {
 func (x *SmrReadyNotification) Reset() {
 	*x = SmrReadyNotification{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[11]
+		mi := &file_api_proto_gateway_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -743,7 +798,7 @@ func (x *SmrReadyNotification) String() string {
 func (*SmrReadyNotification) ProtoMessage() {}
 
 func (x *SmrReadyNotification) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[11]
+	mi := &file_api_proto_gateway_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -756,7 +811,7 @@ func (x *SmrReadyNotification) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SmrReadyNotification.ProtoReflect.Descriptor instead.
 func (*SmrReadyNotification) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{11}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *SmrReadyNotification) GetKernelId() string {
@@ -799,7 +854,7 @@ type ReplicaId struct {
 func (x *ReplicaId) Reset() {
 	*x = ReplicaId{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[12]
+		mi := &file_api_proto_gateway_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -812,7 +867,7 @@ func (x *ReplicaId) String() string {
 func (*ReplicaId) ProtoMessage() {}
 
 func (x *ReplicaId) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[12]
+	mi := &file_api_proto_gateway_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -825,7 +880,7 @@ func (x *ReplicaId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplicaId.ProtoReflect.Descriptor instead.
 func (*ReplicaId) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{12}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ReplicaId) GetId() int32 {
@@ -848,7 +903,7 @@ type PrepareToMigrateResponse struct {
 func (x *PrepareToMigrateResponse) Reset() {
 	*x = PrepareToMigrateResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[13]
+		mi := &file_api_proto_gateway_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -861,7 +916,7 @@ func (x *PrepareToMigrateResponse) String() string {
 func (*PrepareToMigrateResponse) ProtoMessage() {}
 
 func (x *PrepareToMigrateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[13]
+	mi := &file_api_proto_gateway_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -874,7 +929,7 @@ func (x *PrepareToMigrateResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PrepareToMigrateResponse.ProtoReflect.Descriptor instead.
 func (*PrepareToMigrateResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{13}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *PrepareToMigrateResponse) GetId() int32 {
@@ -910,7 +965,7 @@ type MigrateKernelResponse struct {
 func (x *MigrateKernelResponse) Reset() {
 	*x = MigrateKernelResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[14]
+		mi := &file_api_proto_gateway_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -923,7 +978,7 @@ func (x *MigrateKernelResponse) String() string {
 func (*MigrateKernelResponse) ProtoMessage() {}
 
 func (x *MigrateKernelResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[14]
+	mi := &file_api_proto_gateway_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -936,7 +991,7 @@ func (x *MigrateKernelResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MigrateKernelResponse.ProtoReflect.Descriptor instead.
 func (*MigrateKernelResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{14}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *MigrateKernelResponse) GetId() int32 {
@@ -967,7 +1022,7 @@ type ReplicaInfoWithAddr struct {
 func (x *ReplicaInfoWithAddr) Reset() {
 	*x = ReplicaInfoWithAddr{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[15]
+		mi := &file_api_proto_gateway_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -980,7 +1035,7 @@ func (x *ReplicaInfoWithAddr) String() string {
 func (*ReplicaInfoWithAddr) ProtoMessage() {}
 
 func (x *ReplicaInfoWithAddr) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[15]
+	mi := &file_api_proto_gateway_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -993,7 +1048,7 @@ func (x *ReplicaInfoWithAddr) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplicaInfoWithAddr.ProtoReflect.Descriptor instead.
 func (*ReplicaInfoWithAddr) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{15}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *ReplicaInfoWithAddr) GetId() int32 {
@@ -1034,7 +1089,7 @@ type KernelSpec struct {
 func (x *KernelSpec) Reset() {
 	*x = KernelSpec{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[16]
+		mi := &file_api_proto_gateway_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1047,7 +1102,7 @@ func (x *KernelSpec) String() string {
 func (*KernelSpec) ProtoMessage() {}
 
 func (x *KernelSpec) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[16]
+	mi := &file_api_proto_gateway_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1060,7 +1115,7 @@ func (x *KernelSpec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KernelSpec.ProtoReflect.Descriptor instead.
 func (*KernelSpec) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{16}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *KernelSpec) GetId() string {
@@ -1126,7 +1181,7 @@ type KernelConnectionInfo struct {
 func (x *KernelConnectionInfo) Reset() {
 	*x = KernelConnectionInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[17]
+		mi := &file_api_proto_gateway_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1139,7 +1194,7 @@ func (x *KernelConnectionInfo) String() string {
 func (*KernelConnectionInfo) ProtoMessage() {}
 
 func (x *KernelConnectionInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[17]
+	mi := &file_api_proto_gateway_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1152,7 +1207,7 @@ func (x *KernelConnectionInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KernelConnectionInfo.ProtoReflect.Descriptor instead.
 func (*KernelConnectionInfo) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{17}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *KernelConnectionInfo) GetIp() string {
@@ -1243,7 +1298,7 @@ type KernelRegistrationNotification struct {
 func (x *KernelRegistrationNotification) Reset() {
 	*x = KernelRegistrationNotification{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[18]
+		mi := &file_api_proto_gateway_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1256,7 +1311,7 @@ func (x *KernelRegistrationNotification) String() string {
 func (*KernelRegistrationNotification) ProtoMessage() {}
 
 func (x *KernelRegistrationNotification) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[18]
+	mi := &file_api_proto_gateway_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1269,7 +1324,7 @@ func (x *KernelRegistrationNotification) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KernelRegistrationNotification.ProtoReflect.Descriptor instead.
 func (*KernelRegistrationNotification) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{18}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *KernelRegistrationNotification) GetConnectionInfo() *KernelConnectionInfo {
@@ -1337,7 +1392,7 @@ type KernelRegistrationNotificationResponse struct {
 func (x *KernelRegistrationNotificationResponse) Reset() {
 	*x = KernelRegistrationNotificationResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[19]
+		mi := &file_api_proto_gateway_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1350,7 +1405,7 @@ func (x *KernelRegistrationNotificationResponse) String() string {
 func (*KernelRegistrationNotificationResponse) ProtoMessage() {}
 
 func (x *KernelRegistrationNotificationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[19]
+	mi := &file_api_proto_gateway_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1363,7 +1418,7 @@ func (x *KernelRegistrationNotificationResponse) ProtoReflect() protoreflect.Mes
 
 // Deprecated: Use KernelRegistrationNotificationResponse.ProtoReflect.Descriptor instead.
 func (*KernelRegistrationNotificationResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{19}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *KernelRegistrationNotificationResponse) GetId() int32 {
@@ -1413,7 +1468,7 @@ type KernelStatus struct {
 func (x *KernelStatus) Reset() {
 	*x = KernelStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[20]
+		mi := &file_api_proto_gateway_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1426,7 +1481,7 @@ func (x *KernelStatus) String() string {
 func (*KernelStatus) ProtoMessage() {}
 
 func (x *KernelStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[20]
+	mi := &file_api_proto_gateway_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1439,7 +1494,7 @@ func (x *KernelStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KernelStatus.ProtoReflect.Descriptor instead.
 func (*KernelStatus) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{20}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *KernelStatus) GetStatus() int32 {
@@ -1458,7 +1513,7 @@ type Void struct {
 func (x *Void) Reset() {
 	*x = Void{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_gateway_proto_msgTypes[21]
+		mi := &file_api_proto_gateway_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1471,7 +1526,7 @@ func (x *Void) String() string {
 func (*Void) ProtoMessage() {}
 
 func (x *Void) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_gateway_proto_msgTypes[21]
+	mi := &file_api_proto_gateway_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1484,7 +1539,7 @@ func (x *Void) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Void.ProtoReflect.Descriptor instead.
 func (*Void) Descriptor() ([]byte, []int) {
-	return file_api_proto_gateway_proto_rawDescGZIP(), []int{21}
+	return file_api_proto_gateway_proto_rawDescGZIP(), []int{22}
 }
 
 var File_api_proto_gateway_proto protoreflect.FileDescriptor
@@ -1521,242 +1576,262 @@ var file_api_proto_gateway_proto_rawDesc = []byte{
 	0x6e, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x61, 0x74,
 	0x65, 0x77, 0x61, 0x79, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64,
 	0x4a, 0x75, 0x70, 0x79, 0x74, 0x65, 0x72, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x07, 0x6b,
-	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x73, 0x22, 0x1f, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x73,
-	0x69, 0x6f, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2e, 0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x53,
-	0x70, 0x65, 0x63, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x69, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x18, 0x0a, 0x06, 0x48, 0x6f, 0x73, 0x74, 0x49,
-	0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
-	0x64, 0x22, 0xea, 0x01, 0x0a, 0x11, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c,
-	0x69, 0x63, 0x61, 0x53, 0x70, 0x65, 0x63, 0x12, 0x2b, 0x0a, 0x06, 0x6b, 0x65, 0x72, 0x6e, 0x65,
-	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
-	0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x70, 0x65, 0x63, 0x52, 0x06, 0x6b, 0x65,
-	0x72, 0x6e, 0x65, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49,
+	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x73, 0x22, 0x5c, 0x0a, 0x0b, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x39, 0x0a, 0x06, 0x6b, 0x65, 0x72,
+	0x6e, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x4a,
+	0x75, 0x70, 0x79, 0x74, 0x65, 0x72, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x06, 0x6b, 0x65,
+	0x72, 0x6e, 0x65, 0x6c, 0x22, 0x1f, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2e, 0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x53, 0x70, 0x65,
+	0x63, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x70, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x18, 0x0a, 0x06, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0xea, 0x01, 0x0a, 0x11, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63,
+	0x61, 0x53, 0x70, 0x65, 0x63, 0x12, 0x2b, 0x0a, 0x06, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e,
+	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x70, 0x65, 0x63, 0x52, 0x06, 0x6b, 0x65, 0x72, 0x6e,
+	0x65, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x64,
+	0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x75, 0x6d, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6e, 0x75, 0x6d, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63,
+	0x61, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x6a, 0x6f, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6a, 0x6f,
+	0x69, 0x6e, 0x12, 0x27, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x65, 0x72, 0x73,
+	0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x4a, 0x0a, 0x0c,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x70, 0x65, 0x63, 0x12, 0x10, 0x0a, 0x03,
+	0x63, 0x70, 0x75, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x63, 0x70, 0x75, 0x12, 0x16,
+	0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x67, 0x70, 0x75, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x03, 0x67, 0x70, 0x75, 0x22, 0x7f, 0x0a, 0x08, 0x4b, 0x65, 0x72, 0x6e,
+	0x65, 0x6c, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0c, 0x70, 0x65, 0x72,
+	0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x70, 0x65, 0x72,
+	0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x6b, 0x0a, 0x0b, 0x52, 0x65, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e,
+	0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e,
+	0x65, 0x6c, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49,
 	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
-	0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x75, 0x6d, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
-	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6e, 0x75, 0x6d, 0x52, 0x65, 0x70, 0x6c,
-	0x69, 0x63, 0x61, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73,
-	0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73,
-	0x12, 0x12, 0x0a, 0x04, 0x6a, 0x6f, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04,
-	0x6a, 0x6f, 0x69, 0x6e, 0x12, 0x27, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65,
-	0x6e, 0x74, 0x49, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x65,
-	0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a,
-	0x0d, 0x5f, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x4a,
-	0x0a, 0x0c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x70, 0x65, 0x63, 0x12, 0x10,
-	0x0a, 0x03, 0x63, 0x70, 0x75, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x63, 0x70, 0x75,
-	0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x67, 0x70, 0x75, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x67, 0x70, 0x75, 0x22, 0x7f, 0x0a, 0x08, 0x4b, 0x65,
-	0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x72, 0x65, 0x73, 0x74, 0x61,
-	0x72, 0x74, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74,
-	0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0c, 0x70,
-	0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0a,
-	0x0a, 0x08, 0x5f, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x70,
-	0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x6b, 0x0a, 0x0b, 0x52,
-	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65,
-	0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65,
-	0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63,
-	0x61, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x69,
-	0x63, 0x61, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65,
-	0x6e, 0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x65, 0x72, 0x73,
-	0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xac, 0x01, 0x0a, 0x10, 0x4d, 0x69, 0x67,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a,
-	0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x52,
-	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0d, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x12, 0x27, 0x0a, 0x0c, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48,
-	0x00, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x88,
-	0x01, 0x01, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74,
 	0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73,
-	0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65,
-	0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x8e, 0x01, 0x0a, 0x14, 0x53, 0x6d, 0x72, 0x52,
-	0x65, 0x61, 0x64, 0x79, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09,
-	0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x09, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x65,
-	0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x18,
-	0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x1b, 0x0a, 0x09, 0x52, 0x65, 0x70, 0x6c,
-	0x69, 0x63, 0x61, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x60, 0x0a, 0x18, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65,
-	0x54, 0x6f, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a,
-	0x07, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x22, 0x43, 0x0a, 0x15, 0x4d, 0x69, 0x67, 0x72, 0x61,
-	0x74, 0x65, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
-	0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x5d, 0x0a, 0x13,
-	0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x57, 0x69, 0x74, 0x68, 0x41,
-	0x64, 0x64, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x22, 0xb9, 0x01, 0x0a, 0x0a,
-	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x70, 0x65, 0x63, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x76, 0x18, 0x03, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x76, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x69, 0x67, 0x6e,
-	0x61, 0x74, 0x75, 0x72, 0x65, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x53, 0x63, 0x68, 0x65,
-	0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
-	0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x70, 0x65, 0x63, 0x52, 0x08, 0x72,
-	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xb2, 0x02, 0x0a, 0x14, 0x4b, 0x65, 0x72, 0x6e,
-	0x65, 0x6c, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70,
-	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x20,
-	0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x50, 0x6f, 0x72, 0x74,
-	0x12, 0x1c, 0x0a, 0x09, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1c,
-	0x0a, 0x09, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x09, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06,
-	0x68, 0x62, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x62,
-	0x50, 0x6f, 0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6f, 0x70, 0x75, 0x62, 0x50, 0x6f, 0x72,
-	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x69, 0x6f, 0x70, 0x75, 0x62, 0x50, 0x6f,
-	0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6f, 0x73, 0x75, 0x62, 0x50, 0x6f, 0x72, 0x74, 0x18,
-	0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x69, 0x6f, 0x73, 0x75, 0x62, 0x50, 0x6f, 0x72, 0x74,
-	0x12, 0x28, 0x0a, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x53, 0x63, 0x68,
-	0x65, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61,
-	0x74, 0x75, 0x72, 0x65, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x8d, 0x02, 0x0a,
-	0x1e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x45, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66,
-	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
-	0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
-	0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
-	0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
-	0x12, 0x16, 0x0a, 0x06, 0x68, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x68, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x70, 0x6c,
-	0x69, 0x63, 0x61, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x65, 0x70,
-	0x6c, 0x69, 0x63, 0x61, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
-	0x49, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
-	0x49, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x6f, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6f, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xe1, 0x02, 0x0a,
-	0x26, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x59, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69,
-	0x63, 0x61, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x67, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69,
-	0x63, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63,
-	0x61, 0x73, 0x12, 0x27, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74,
-	0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x65, 0x72, 0x73,
-	0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07, 0x73,
-	0x6d, 0x72, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x6d,
-	0x72, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x29, 0x0a, 0x0d, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0d,
-	0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x88, 0x01, 0x01,
-	0x1a, 0x3b, 0x0a, 0x0d, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03,
-	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0f, 0x0a,
-	0x0d, 0x5f, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x42, 0x10,
-	0x0a, 0x0e, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
-	0x22, 0x26, 0x0a, 0x0c, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x06, 0x0a, 0x04, 0x56, 0x6f, 0x69, 0x64,
-	0x32, 0xeb, 0x03, 0x0a, 0x0e, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x47, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x12, 0x2d, 0x0a, 0x02, 0x49, 0x44, 0x12, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x16, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x49, 0x64,
-	0x22, 0x00, 0x12, 0x2e, 0x0a, 0x0a, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x48, 0x6f, 0x73, 0x74,
-	0x12, 0x0f, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x49,
-	0x64, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64,
-	0x22, 0x00, 0x12, 0x53, 0x0a, 0x14, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x72,
-	0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x12, 0x19, 0x2e, 0x67, 0x61, 0x74,
-	0x65, 0x77, 0x61, 0x79, 0x2e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e,
-	0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x74, 0x0a, 0x16, 0x4e, 0x6f, 0x74, 0x69, 0x66,
-	0x79, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65,
-	0x64, 0x12, 0x27, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e,
-	0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f,
-	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x2f, 0x2e, 0x67, 0x61, 0x74,
-	0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3a, 0x0a,
-	0x08, 0x53, 0x6d, 0x72, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x1d, 0x2e, 0x67, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x2e, 0x53, 0x6d, 0x72, 0x52, 0x65, 0x61, 0x64, 0x79, 0x4e, 0x6f, 0x74, 0x69,
-	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x0c, 0x53, 0x6d, 0x72,
-	0x4e, 0x6f, 0x64, 0x65, 0x41, 0x64, 0x64, 0x65, 0x64, 0x12, 0x14, 0x2e, 0x67, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x1a,
+	0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xac, 0x01, 0x0a, 0x10, 0x4d, 0x69, 0x67, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x0d, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x12, 0x27, 0x0a, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x22, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65,
+	0x6e, 0x74, 0x49, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4e,
+	0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x8e, 0x01, 0x0a, 0x14, 0x53, 0x6d, 0x72, 0x52, 0x65, 0x61,
+	0x64, 0x79, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72,
+	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73,
+	0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x1b, 0x0a, 0x09, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63,
+	0x61, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x02, 0x69, 0x64, 0x22, 0x60, 0x0a, 0x18, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x54, 0x6f,
+	0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x64,
+	0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61,
+	0x74, 0x61, 0x44, 0x69, 0x72, 0x22, 0x43, 0x0a, 0x15, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65,
+	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x5d, 0x0a, 0x13, 0x52, 0x65,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x57, 0x69, 0x74, 0x68, 0x41, 0x64, 0x64,
+	0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x22, 0xb9, 0x01, 0x0a, 0x0a, 0x4b, 0x65,
+	0x72, 0x6e, 0x65, 0x6c, 0x53, 0x70, 0x65, 0x63, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x76, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x61, 0x72, 0x67, 0x76, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x31, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x52,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x70, 0x65, 0x63, 0x52, 0x08, 0x72, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xb2, 0x02, 0x0a, 0x14, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1c,
+	0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x20, 0x0a, 0x0b,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x73, 0x74, 0x64, 0x69, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x62,
+	0x50, 0x6f, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x62, 0x50, 0x6f,
+	0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6f, 0x70, 0x75, 0x62, 0x50, 0x6f, 0x72, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x69, 0x6f, 0x70, 0x75, 0x62, 0x50, 0x6f, 0x72, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6f, 0x73, 0x75, 0x62, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x69, 0x6f, 0x73, 0x75, 0x62, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x28,
+	0x0a, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x53, 0x63, 0x68, 0x65, 0x6d,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x8d, 0x02, 0x0a, 0x1e, 0x4b,
+	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x45, 0x0a,
+	0x0e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e,
+	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x68, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63,
+	0x61, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x70,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x70,
+	0x12, 0x18, 0x0a, 0x07, 0x70, 0x6f, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x70, 0x6f, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xe1, 0x02, 0x0a, 0x26, 0x4b,
+	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x59, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73,
+	0x12, 0x27, 0x0a, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73,
+	0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x6d, 0x72,
+	0x50, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x6d, 0x72, 0x50,
+	0x6f, 0x72, 0x74, 0x12, 0x29, 0x0a, 0x0d, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0d, 0x64, 0x61,
+	0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x88, 0x01, 0x01, 0x1a, 0x3b,
+	0x0a, 0x0d, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x42, 0x10, 0x0a, 0x0e,
+	0x5f, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x26,
+	0x0a, 0x0c, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x06, 0x0a, 0x04, 0x56, 0x6f, 0x69, 0x64, 0x32, 0xd2,
+	0x05, 0x0a, 0x0e, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x12, 0x2d, 0x0a, 0x02, 0x49, 0x44, 0x12, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x16, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
+	0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x22, 0x00,
+	0x12, 0x2e, 0x0a, 0x0a, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x0f,
+	0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x1a,
 	0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00,
-	0x12, 0x3c, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x73, 0x12,
-	0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x1c,
-	0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x72,
-	0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xac,
-	0x05, 0x0a, 0x0c, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12,
-	0x2b, 0x0a, 0x05, 0x53, 0x65, 0x74, 0x49, 0x44, 0x12, 0x0f, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x1a, 0x0f, 0x2e, 0x67, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b,
-	0x53, 0x74, 0x61, 0x72, 0x74, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x13, 0x2e, 0x67, 0x61,
-	0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x70, 0x65, 0x63,
-	0x1a, 0x1d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65,
-	0x6c, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22,
-	0x00, 0x12, 0x51, 0x0a, 0x12, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
-	0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x12, 0x1a, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
-	0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x53,
-	0x70, 0x65, 0x63, 0x1a, 0x1d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65,
-	0x72, 0x6e, 0x65, 0x6c, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e,
-	0x66, 0x6f, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4b, 0x65, 0x72, 0x6e, 0x65,
-	0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x11, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
-	0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x1a, 0x15, 0x2e, 0x67, 0x61, 0x74,
-	0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0a, 0x4b, 0x69, 0x6c, 0x6c, 0x4b, 0x65, 0x72, 0x6e, 0x65,
-	0x6c, 0x12, 0x11, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e,
-	0x65, 0x6c, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56,
-	0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x70, 0x4b, 0x65, 0x72,
-	0x6e, 0x65, 0x6c, 0x12, 0x11, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65,
-	0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
-	0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x38, 0x0a, 0x0a, 0x57, 0x61, 0x69, 0x74, 0x4b,
-	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x11, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e,
-	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x1a, 0x15, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22,
-	0x00, 0x12, 0x2a, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x0d, 0x2e,
-	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x0d, 0x2e, 0x67,
-	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x3b, 0x0a,
-	0x0a, 0x41, 0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x12, 0x1c, 0x2e, 0x67, 0x61,
-	0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66,
-	0x6f, 0x57, 0x69, 0x74, 0x68, 0x41, 0x64, 0x64, 0x72, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x11, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x41, 0x64, 0x64, 0x72, 0x12,
-	0x1c, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63,
-	0x61, 0x49, 0x6e, 0x66, 0x6f, 0x57, 0x69, 0x74, 0x68, 0x41, 0x64, 0x64, 0x72, 0x1a, 0x0d, 0x2e,
-	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x4d,
-	0x0a, 0x10, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x54, 0x6f, 0x4d, 0x69, 0x67, 0x72, 0x61,
-	0x74, 0x65, 0x12, 0x14, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x70,
-	0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x21, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x54, 0x6f, 0x4d, 0x69, 0x67, 0x72,
-	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x82, 0x01,
-	0x0a, 0x37, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x7a, 0x68, 0x61,
-	0x6e, 0x67, 0x6a, 0x79, 0x72, 0x2e, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
-	0x64, 0x2d, 0x6e, 0x6f, 0x74, 0x65, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
-	0x6e, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x42, 0x0c, 0x47, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x68, 0x61, 0x6e, 0x67, 0x6a, 0x79, 0x72, 0x2f, 0x64,
-	0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x2d, 0x6e, 0x6f, 0x74, 0x65, 0x62,
-	0x6f, 0x6f, 0x6b, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x67, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x53, 0x0a, 0x14, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x72, 0x6e, 0x65,
+	0x6c, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x12, 0x19, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
+	0x61, 0x79, 0x2e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4d, 0x69,
+	0x67, 0x72, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x74, 0x0a, 0x16, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4b,
+	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x12,
+	0x27, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
+	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x2f, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
+	0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x08, 0x53,
+	0x6d, 0x72, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x1d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x2e, 0x53, 0x6d, 0x72, 0x52, 0x65, 0x61, 0x64, 0x79, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
+	0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x0c, 0x53, 0x6d, 0x72, 0x4e, 0x6f,
+	0x64, 0x65, 0x41, 0x64, 0x64, 0x65, 0x64, 0x12, 0x14, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x0d, 0x2e,
+	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x3c,
+	0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x0d, 0x2e,
+	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x1c, 0x2e, 0x67,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x72, 0x6e, 0x65,
+	0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0c,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x0d, 0x2e, 0x67,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x14, 0x2e, 0x67, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x43, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4b, 0x65,
+	0x72, 0x6e, 0x65, 0x6c, 0x12, 0x13, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b,
+	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x70, 0x65, 0x63, 0x1a, 0x1d, 0x2e, 0x67, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0a, 0x4b, 0x69,
+	0x6c, 0x6c, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x11, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
+	0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x67, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x0f,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x72, 0x75, 0x70, 0x74, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12,
+	0x11, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
+	0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69,
+	0x64, 0x22, 0x00, 0x32, 0xac, 0x05, 0x0a, 0x0c, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x47, 0x61, 0x74,
+	0x65, 0x77, 0x61, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x53, 0x65, 0x74, 0x49, 0x44, 0x12, 0x0f, 0x2e,
+	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x1a, 0x0f,
+	0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x22,
+	0x00, 0x12, 0x43, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
+	0x12, 0x13, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65,
+	0x6c, 0x53, 0x70, 0x65, 0x63, 0x1a, 0x1d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e,
+	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x12, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4b,
+	0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x12, 0x1a, 0x2e, 0x67,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x53, 0x70, 0x65, 0x63, 0x1a, 0x1d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
+	0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x11, 0x2e, 0x67,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x1a,
+	0x15, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0a, 0x4b, 0x69, 0x6c, 0x6c,
+	0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x11, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79,
+	0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0a, 0x53, 0x74,
+	0x6f, 0x70, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x11, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77,
+	0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x67, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12, 0x38, 0x0a, 0x0a,
+	0x57, 0x61, 0x69, 0x74, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x11, 0x2e, 0x67, 0x61, 0x74,
+	0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x1a, 0x15, 0x2e,
+	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12, 0x2a, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x43, 0x6c, 0x6f,
+	0x73, 0x65, 0x12, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69,
+	0x64, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64,
+	0x22, 0x00, 0x12, 0x3b, 0x0a, 0x0a, 0x41, 0x64, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x12, 0x1c, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x57, 0x69, 0x74, 0x68, 0x41, 0x64, 0x64, 0x72, 0x1a, 0x0d,
+	0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x22, 0x00, 0x12,
+	0x42, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61,
+	0x41, 0x64, 0x64, 0x72, 0x12, 0x1c, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x52,
+	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x57, 0x69, 0x74, 0x68, 0x41, 0x64,
+	0x64, 0x72, 0x1a, 0x0d, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x56, 0x6f, 0x69,
+	0x64, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x10, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x54, 0x6f,
+	0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x12, 0x14, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x21, 0x2e,
+	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x54,
+	0x6f, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x42, 0x82, 0x01, 0x0a, 0x37, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x7a, 0x68, 0x61, 0x6e, 0x67, 0x6a, 0x79, 0x72, 0x2e, 0x64, 0x69, 0x73, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x2d, 0x6e, 0x6f, 0x74, 0x65, 0x62, 0x6f, 0x6f, 0x6b, 0x2e,
+	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x42, 0x0c,
+	0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x37,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x68, 0x61, 0x6e, 0x67,
+	0x6a, 0x79, 0x72, 0x2f, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x2d,
+	0x6e, 0x6f, 0x74, 0x65, 0x62, 0x6f, 0x6f, 0x6b, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f,
+	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1771,81 +1846,91 @@ func file_api_proto_gateway_proto_rawDescGZIP() []byte {
 	return file_api_proto_gateway_proto_rawDescData
 }
 
-var file_api_proto_gateway_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_api_proto_gateway_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_api_proto_gateway_proto_goTypes = []interface{}{
 	(*JupyterKernelReplica)(nil),                   // 0: gateway.JupyterKernelReplica
 	(*DistributedJupyterKernel)(nil),               // 1: gateway.DistributedJupyterKernel
 	(*ListKernelsResponse)(nil),                    // 2: gateway.ListKernelsResponse
-	(*ProvisionerId)(nil),                          // 3: gateway.ProvisionerId
-	(*HostSpec)(nil),                               // 4: gateway.HostSpec
-	(*HostId)(nil),                                 // 5: gateway.HostId
-	(*KernelReplicaSpec)(nil),                      // 6: gateway.KernelReplicaSpec
-	(*ResourceSpec)(nil),                           // 7: gateway.ResourceSpec
-	(*KernelId)(nil),                               // 8: gateway.KernelId
-	(*ReplicaInfo)(nil),                            // 9: gateway.ReplicaInfo
-	(*MigrationRequest)(nil),                       // 10: gateway.MigrationRequest
-	(*SmrReadyNotification)(nil),                   // 11: gateway.SmrReadyNotification
-	(*ReplicaId)(nil),                              // 12: gateway.ReplicaId
-	(*PrepareToMigrateResponse)(nil),               // 13: gateway.PrepareToMigrateResponse
-	(*MigrateKernelResponse)(nil),                  // 14: gateway.MigrateKernelResponse
-	(*ReplicaInfoWithAddr)(nil),                    // 15: gateway.ReplicaInfoWithAddr
-	(*KernelSpec)(nil),                             // 16: gateway.KernelSpec
-	(*KernelConnectionInfo)(nil),                   // 17: gateway.KernelConnectionInfo
-	(*KernelRegistrationNotification)(nil),         // 18: gateway.KernelRegistrationNotification
-	(*KernelRegistrationNotificationResponse)(nil), // 19: gateway.KernelRegistrationNotificationResponse
-	(*KernelStatus)(nil),                           // 20: gateway.KernelStatus
-	(*Void)(nil),                                   // 21: gateway.Void
-	nil,                                            // 22: gateway.KernelRegistrationNotificationResponse.ReplicasEntry
+	(*KernelEvent)(nil),                            // 3: gateway.KernelEvent
+	(*ProvisionerId)(nil),                          // 4: gateway.ProvisionerId
+	(*HostSpec)(nil),                               // 5: gateway.HostSpec
+	(*HostId)(nil),                                 // 6: gateway.HostId
+	(*KernelReplicaSpec)(nil),                      // 7: gateway.KernelReplicaSpec
+	(*ResourceSpec)(nil),                           // 8: gateway.ResourceSpec
+	(*KernelId)(nil),                               // 9: gateway.KernelId
+	(*ReplicaInfo)(nil),                            // 10: gateway.ReplicaInfo
+	(*MigrationRequest)(nil),                       // 11: gateway.MigrationRequest
+	(*SmrReadyNotification)(nil),                   // 12: gateway.SmrReadyNotification
+	(*ReplicaId)(nil),                              // 13: gateway.ReplicaId
+	(*PrepareToMigrateResponse)(nil),               // 14: gateway.PrepareToMigrateResponse
+	(*MigrateKernelResponse)(nil),                  // 15: gateway.MigrateKernelResponse
+	(*ReplicaInfoWithAddr)(nil),                    // 16: gateway.ReplicaInfoWithAddr
+	(*KernelSpec)(nil),                             // 17: gateway.KernelSpec
+	(*KernelConnectionInfo)(nil),                   // 18: gateway.KernelConnectionInfo
+	(*KernelRegistrationNotification)(nil),         // 19: gateway.KernelRegistrationNotification
+	(*KernelRegistrationNotificationResponse)(nil), // 20: gateway.KernelRegistrationNotificationResponse
+	(*KernelStatus)(nil),                           // 21: gateway.KernelStatus
+	(*Void)(nil),                                   // 22: gateway.Void
+	nil,                                            // 23: gateway.KernelRegistrationNotificationResponse.ReplicasEntry
 }
 var file_api_proto_gateway_proto_depIdxs = []int32{
 	0,  // 0: gateway.DistributedJupyterKernel.replicas:type_name -> gateway.JupyterKernelReplica
 	1,  // 1: gateway.ListKernelsResponse.kernels:type_name -> gateway.DistributedJupyterKernel
-	16, // 2: gateway.KernelReplicaSpec.kernel:type_name -> gateway.KernelSpec
-	9,  // 3: gateway.MigrationRequest.targetReplica:type_name -> gateway.ReplicaInfo
-	7,  // 4: gateway.KernelSpec.resource:type_name -> gateway.ResourceSpec
-	17, // 5: gateway.KernelRegistrationNotification.connectionInfo:type_name -> gateway.KernelConnectionInfo
-	22, // 6: gateway.KernelRegistrationNotificationResponse.replicas:type_name -> gateway.KernelRegistrationNotificationResponse.ReplicasEntry
-	21, // 7: gateway.ClusterGateway.ID:input_type -> gateway.Void
-	5,  // 8: gateway.ClusterGateway.RemoveHost:input_type -> gateway.HostId
-	10, // 9: gateway.ClusterGateway.MigrateKernelReplica:input_type -> gateway.MigrationRequest
-	18, // 10: gateway.ClusterGateway.NotifyKernelRegistered:input_type -> gateway.KernelRegistrationNotification
-	11, // 11: gateway.ClusterGateway.SmrReady:input_type -> gateway.SmrReadyNotification
-	9,  // 12: gateway.ClusterGateway.SmrNodeAdded:input_type -> gateway.ReplicaInfo
-	21, // 13: gateway.ClusterGateway.ListKernels:input_type -> gateway.Void
-	5,  // 14: gateway.LocalGateway.SetID:input_type -> gateway.HostId
-	16, // 15: gateway.LocalGateway.StartKernel:input_type -> gateway.KernelSpec
-	6,  // 16: gateway.LocalGateway.StartKernelReplica:input_type -> gateway.KernelReplicaSpec
-	8,  // 17: gateway.LocalGateway.GetKernelStatus:input_type -> gateway.KernelId
-	8,  // 18: gateway.LocalGateway.KillKernel:input_type -> gateway.KernelId
-	8,  // 19: gateway.LocalGateway.StopKernel:input_type -> gateway.KernelId
-	8,  // 20: gateway.LocalGateway.WaitKernel:input_type -> gateway.KernelId
-	21, // 21: gateway.LocalGateway.SetClose:input_type -> gateway.Void
-	15, // 22: gateway.LocalGateway.AddReplica:input_type -> gateway.ReplicaInfoWithAddr
-	15, // 23: gateway.LocalGateway.UpdateReplicaAddr:input_type -> gateway.ReplicaInfoWithAddr
-	9,  // 24: gateway.LocalGateway.PrepareToMigrate:input_type -> gateway.ReplicaInfo
-	3,  // 25: gateway.ClusterGateway.ID:output_type -> gateway.ProvisionerId
-	21, // 26: gateway.ClusterGateway.RemoveHost:output_type -> gateway.Void
-	14, // 27: gateway.ClusterGateway.MigrateKernelReplica:output_type -> gateway.MigrateKernelResponse
-	19, // 28: gateway.ClusterGateway.NotifyKernelRegistered:output_type -> gateway.KernelRegistrationNotificationResponse
-	21, // 29: gateway.ClusterGateway.SmrReady:output_type -> gateway.Void
-	21, // 30: gateway.ClusterGateway.SmrNodeAdded:output_type -> gateway.Void
-	2,  // 31: gateway.ClusterGateway.ListKernels:output_type -> gateway.ListKernelsResponse
-	5,  // 32: gateway.LocalGateway.SetID:output_type -> gateway.HostId
-	17, // 33: gateway.LocalGateway.StartKernel:output_type -> gateway.KernelConnectionInfo
-	17, // 34: gateway.LocalGateway.StartKernelReplica:output_type -> gateway.KernelConnectionInfo
-	20, // 35: gateway.LocalGateway.GetKernelStatus:output_type -> gateway.KernelStatus
-	21, // 36: gateway.LocalGateway.KillKernel:output_type -> gateway.Void
-	21, // 37: gateway.LocalGateway.StopKernel:output_type -> gateway.Void
-	20, // 38: gateway.LocalGateway.WaitKernel:output_type -> gateway.KernelStatus
-	21, // 39: gateway.LocalGateway.SetClose:output_type -> gateway.Void
-	21, // 40: gateway.LocalGateway.AddReplica:output_type -> gateway.Void
-	21, // 41: gateway.LocalGateway.UpdateReplicaAddr:output_type -> gateway.Void
-	13, // 42: gateway.LocalGateway.PrepareToMigrate:output_type -> gateway.PrepareToMigrateResponse
-	25, // [25:43] is the sub-list for method output_type
-	7,  // [7:25] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	1,  // 2: gateway.KernelEvent.kernel:type_name -> gateway.DistributedJupyterKernel
+	17, // 3: gateway.KernelReplicaSpec.kernel:type_name -> gateway.KernelSpec
+	10, // 4: gateway.MigrationRequest.targetReplica:type_name -> gateway.ReplicaInfo
+	8,  // 5: gateway.KernelSpec.resource:type_name -> gateway.ResourceSpec
+	18, // 6: gateway.KernelRegistrationNotification.connectionInfo:type_name -> gateway.KernelConnectionInfo
+	23, // 7: gateway.KernelRegistrationNotificationResponse.replicas:type_name -> gateway.KernelRegistrationNotificationResponse.ReplicasEntry
+	22, // 8: gateway.ClusterGateway.ID:input_type -> gateway.Void
+	6,  // 9: gateway.ClusterGateway.RemoveHost:input_type -> gateway.HostId
+	11, // 10: gateway.ClusterGateway.MigrateKernelReplica:input_type -> gateway.MigrationRequest
+	19, // 11: gateway.ClusterGateway.NotifyKernelRegistered:input_type -> gateway.KernelRegistrationNotification
+	12, // 12: gateway.ClusterGateway.SmrReady:input_type -> gateway.SmrReadyNotification
+	10, // 13: gateway.ClusterGateway.SmrNodeAdded:input_type -> gateway.ReplicaInfo
+	22, // 14: gateway.ClusterGateway.ListKernels:input_type -> gateway.Void
+	22, // 15: gateway.ClusterGateway.WatchKernels:input_type -> gateway.Void
+	17, // 16: gateway.ClusterGateway.StartKernel:input_type -> gateway.KernelSpec
+	9,  // 17: gateway.ClusterGateway.KillKernel:input_type -> gateway.KernelId
+	9,  // 18: gateway.ClusterGateway.InterruptKernel:input_type -> gateway.KernelId
+	6,  // 19: gateway.LocalGateway.SetID:input_type -> gateway.HostId
+	17, // 20: gateway.LocalGateway.StartKernel:input_type -> gateway.KernelSpec
+	7,  // 21: gateway.LocalGateway.StartKernelReplica:input_type -> gateway.KernelReplicaSpec
+	9,  // 22: gateway.LocalGateway.GetKernelStatus:input_type -> gateway.KernelId
+	9,  // 23: gateway.LocalGateway.KillKernel:input_type -> gateway.KernelId
+	9,  // 24: gateway.LocalGateway.StopKernel:input_type -> gateway.KernelId
+	9,  // 25: gateway.LocalGateway.WaitKernel:input_type -> gateway.KernelId
+	22, // 26: gateway.LocalGateway.SetClose:input_type -> gateway.Void
+	16, // 27: gateway.LocalGateway.AddReplica:input_type -> gateway.ReplicaInfoWithAddr
+	16, // 28: gateway.LocalGateway.UpdateReplicaAddr:input_type -> gateway.ReplicaInfoWithAddr
+	10, // 29: gateway.LocalGateway.PrepareToMigrate:input_type -> gateway.ReplicaInfo
+	4,  // 30: gateway.ClusterGateway.ID:output_type -> gateway.ProvisionerId
+	22, // 31: gateway.ClusterGateway.RemoveHost:output_type -> gateway.Void
+	15, // 32: gateway.ClusterGateway.MigrateKernelReplica:output_type -> gateway.MigrateKernelResponse
+	20, // 33: gateway.ClusterGateway.NotifyKernelRegistered:output_type -> gateway.KernelRegistrationNotificationResponse
+	22, // 34: gateway.ClusterGateway.SmrReady:output_type -> gateway.Void
+	22, // 35: gateway.ClusterGateway.SmrNodeAdded:output_type -> gateway.Void
+	2,  // 36: gateway.ClusterGateway.ListKernels:output_type -> gateway.ListKernelsResponse
+	3,  // 37: gateway.ClusterGateway.WatchKernels:output_type -> gateway.KernelEvent
+	18, // 38: gateway.ClusterGateway.StartKernel:output_type -> gateway.KernelConnectionInfo
+	22, // 39: gateway.ClusterGateway.KillKernel:output_type -> gateway.Void
+	22, // 40: gateway.ClusterGateway.InterruptKernel:output_type -> gateway.Void
+	6,  // 41: gateway.LocalGateway.SetID:output_type -> gateway.HostId
+	18, // 42: gateway.LocalGateway.StartKernel:output_type -> gateway.KernelConnectionInfo
+	18, // 43: gateway.LocalGateway.StartKernelReplica:output_type -> gateway.KernelConnectionInfo
+	21, // 44: gateway.LocalGateway.GetKernelStatus:output_type -> gateway.KernelStatus
+	22, // 45: gateway.LocalGateway.KillKernel:output_type -> gateway.Void
+	22, // 46: gateway.LocalGateway.StopKernel:output_type -> gateway.Void
+	21, // 47: gateway.LocalGateway.WaitKernel:output_type -> gateway.KernelStatus
+	22, // 48: gateway.LocalGateway.SetClose:output_type -> gateway.Void
+	22, // 49: gateway.LocalGateway.AddReplica:output_type -> gateway.Void
+	22, // 50: gateway.LocalGateway.UpdateReplicaAddr:output_type -> gateway.Void
+	14, // 51: gateway.LocalGateway.PrepareToMigrate:output_type -> gateway.PrepareToMigrateResponse
+	30, // [30:52] is the sub-list for method output_type
+	8,  // [8:30] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_gateway_proto_init() }
@@ -1891,7 +1976,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProvisionerId); i {
+			switch v := v.(*KernelEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1903,7 +1988,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HostSpec); i {
+			switch v := v.(*ProvisionerId); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1915,7 +2000,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HostId); i {
+			switch v := v.(*HostSpec); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1927,7 +2012,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KernelReplicaSpec); i {
+			switch v := v.(*HostId); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1939,7 +2024,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResourceSpec); i {
+			switch v := v.(*KernelReplicaSpec); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1951,7 +2036,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KernelId); i {
+			switch v := v.(*ResourceSpec); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1963,7 +2048,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReplicaInfo); i {
+			switch v := v.(*KernelId); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1975,7 +2060,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MigrationRequest); i {
+			switch v := v.(*ReplicaInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1987,7 +2072,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SmrReadyNotification); i {
+			switch v := v.(*MigrationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1999,7 +2084,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReplicaId); i {
+			switch v := v.(*SmrReadyNotification); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2011,7 +2096,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrepareToMigrateResponse); i {
+			switch v := v.(*ReplicaId); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2023,7 +2108,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MigrateKernelResponse); i {
+			switch v := v.(*PrepareToMigrateResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2035,7 +2120,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReplicaInfoWithAddr); i {
+			switch v := v.(*MigrateKernelResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2047,7 +2132,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KernelSpec); i {
+			switch v := v.(*ReplicaInfoWithAddr); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2059,7 +2144,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KernelConnectionInfo); i {
+			switch v := v.(*KernelSpec); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2071,7 +2156,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KernelRegistrationNotification); i {
+			switch v := v.(*KernelConnectionInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2083,7 +2168,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KernelRegistrationNotificationResponse); i {
+			switch v := v.(*KernelRegistrationNotification); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2095,7 +2180,7 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KernelStatus); i {
+			switch v := v.(*KernelRegistrationNotificationResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2107,6 +2192,18 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 		file_api_proto_gateway_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KernelStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_gateway_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Void); i {
 			case 0:
 				return &v.state
@@ -2119,17 +2216,17 @@ func file_api_proto_gateway_proto_init() {
 			}
 		}
 	}
-	file_api_proto_gateway_proto_msgTypes[6].OneofWrappers = []interface{}{}
-	file_api_proto_gateway_proto_msgTypes[8].OneofWrappers = []interface{}{}
-	file_api_proto_gateway_proto_msgTypes[10].OneofWrappers = []interface{}{}
-	file_api_proto_gateway_proto_msgTypes[19].OneofWrappers = []interface{}{}
+	file_api_proto_gateway_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_api_proto_gateway_proto_msgTypes[9].OneofWrappers = []interface{}{}
+	file_api_proto_gateway_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_api_proto_gateway_proto_msgTypes[20].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_proto_gateway_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   23,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -26,6 +26,10 @@ const (
 	ClusterGateway_SmrReady_FullMethodName               = "/gateway.ClusterGateway/SmrReady"
 	ClusterGateway_SmrNodeAdded_FullMethodName           = "/gateway.ClusterGateway/SmrNodeAdded"
 	ClusterGateway_ListKernels_FullMethodName            = "/gateway.ClusterGateway/ListKernels"
+	ClusterGateway_WatchKernels_FullMethodName           = "/gateway.ClusterGateway/WatchKernels"
+	ClusterGateway_StartKernel_FullMethodName            = "/gateway.ClusterGateway/StartKernel"
+	ClusterGateway_KillKernel_FullMethodName             = "/gateway.ClusterGateway/KillKernel"
+	ClusterGateway_InterruptKernel_FullMethodName        = "/gateway.ClusterGateway/InterruptKernel"
 )
 
 // ClusterGatewayClient is the client API for ClusterGateway service.
@@ -49,6 +53,15 @@ type ClusterGatewayClient interface {
 	SmrNodeAdded(ctx context.Context, in *ReplicaInfo, opts ...grpc.CallOption) (*Void, error)
 	// Return a list of all of the current kernel IDs.
 	ListKernels(ctx context.Context, in *Void, opts ...grpc.CallOption) (*ListKernelsResponse, error)
+	// Stream changes to the set of kernels. The stream begins with an "added" event for every current kernel,
+	// followed by a "synced" event. Thereafter, an event is sent whenever a kernel is added, updated, or removed.
+	WatchKernels(ctx context.Context, in *Void, opts ...grpc.CallOption) (ClusterGateway_WatchKernelsClient, error)
+	// Create a distributed kernel with the given ID, and with its replicas placed by the Gateway.
+	StartKernel(ctx context.Context, in *KernelSpec, opts ...grpc.CallOption) (*KernelConnectionInfo, error)
+	// Terminate a distributed kernel, along with all of its replicas.
+	KillKernel(ctx context.Context, in *KernelId, opts ...grpc.CallOption) (*Void, error)
+	// Interrupt the cell, if any, that a distributed kernel is executing.
+	InterruptKernel(ctx context.Context, in *KernelId, opts ...grpc.CallOption) (*Void, error)
 }
 
 type clusterGatewayClient struct {
@@ -122,6 +135,65 @@ func (c *clusterGatewayClient) ListKernels(ctx context.Context, in *Void, opts .
 	return out, nil
 }
 
+func (c *clusterGatewayClient) WatchKernels(ctx context.Context, in *Void, opts ...grpc.CallOption) (ClusterGateway_WatchKernelsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ClusterGateway_ServiceDesc.Streams[0], ClusterGateway_WatchKernels_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &clusterGatewayWatchKernelsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ClusterGateway_WatchKernelsClient interface {
+	Recv() (*KernelEvent, error)
+	grpc.ClientStream
+}
+
+type clusterGatewayWatchKernelsClient struct {
+	grpc.ClientStream
+}
+
+func (x *clusterGatewayWatchKernelsClient) Recv() (*KernelEvent, error) {
+	m := new(KernelEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *clusterGatewayClient) StartKernel(ctx context.Context, in *KernelSpec, opts ...grpc.CallOption) (*KernelConnectionInfo, error) {
+	out := new(KernelConnectionInfo)
+	err := c.cc.Invoke(ctx, ClusterGateway_StartKernel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterGatewayClient) KillKernel(ctx context.Context, in *KernelId, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, ClusterGateway_KillKernel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterGatewayClient) InterruptKernel(ctx context.Context, in *KernelId, opts ...grpc.CallOption) (*Void, error) {
+	out := new(Void)
+	err := c.cc.Invoke(ctx, ClusterGateway_InterruptKernel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ClusterGatewayServer is the server API for ClusterGateway service.
 // All implementations must embed UnimplementedClusterGatewayServer
 // for forward compatibility
@@ -143,6 +215,15 @@ type ClusterGatewayServer interface {
 	SmrNodeAdded(context.Context, *ReplicaInfo) (*Void, error)
 	// Return a list of all of the current kernel IDs.
 	ListKernels(context.Context, *Void) (*ListKernelsResponse, error)
+	// Stream changes to the set of kernels. The stream begins with an "added" event for every current kernel,
+	// followed by a "synced" event. Thereafter, an event is sent whenever a kernel is added, updated, or removed.
+	WatchKernels(*Void, ClusterGateway_WatchKernelsServer) error
+	// Create a distributed kernel with the given ID, and with its replicas placed by the Gateway.
+	StartKernel(context.Context, *KernelSpec) (*KernelConnectionInfo, error)
+	// Terminate a distributed kernel, along with all of its replicas.
+	KillKernel(context.Context, *KernelId) (*Void, error)
+	// Interrupt the cell, if any, that a distributed kernel is executing.
+	InterruptKernel(context.Context, *KernelId) (*Void, error)
 	mustEmbedUnimplementedClusterGatewayServer()
 }
 
@@ -171,6 +252,18 @@ func (UnimplementedClusterGatewayServer) SmrNodeAdded(context.Context, *ReplicaI
 func (UnimplementedClusterGatewayServer) ListKernels(context.Context, *Void) (*ListKernelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListKernels not implemented")
 }
+func (UnimplementedClusterGatewayServer) WatchKernels(*Void, ClusterGateway_WatchKernelsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchKernels not implemented")
+}
+func (UnimplementedClusterGatewayServer) StartKernel(context.Context, *KernelSpec) (*KernelConnectionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartKernel not implemented")
+}
+func (UnimplementedClusterGatewayServer) KillKernel(context.Context, *KernelId) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KillKernel not implemented")
+}
+func (UnimplementedClusterGatewayServer) InterruptKernel(context.Context, *KernelId) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InterruptKernel not implemented")
+}
 func (UnimplementedClusterGatewayServer) mustEmbedUnimplementedClusterGatewayServer() {}
 
 // UnsafeClusterGatewayServer may be embedded to opt out of forward compatibility for this service.
@@ -310,6 +403,81 @@ func _ClusterGateway_ListKernels_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClusterGateway_WatchKernels_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Void)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClusterGatewayServer).WatchKernels(m, &clusterGatewayWatchKernelsServer{stream})
+}
+
+type ClusterGateway_WatchKernelsServer interface {
+	Send(*KernelEvent) error
+	grpc.ServerStream
+}
+
+type clusterGatewayWatchKernelsServer struct {
+	grpc.ServerStream
+}
+
+func (x *clusterGatewayWatchKernelsServer) Send(m *KernelEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ClusterGateway_StartKernel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KernelSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterGatewayServer).StartKernel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterGateway_StartKernel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterGatewayServer).StartKernel(ctx, req.(*KernelSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClusterGateway_KillKernel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KernelId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterGatewayServer).KillKernel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterGateway_KillKernel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterGatewayServer).KillKernel(ctx, req.(*KernelId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClusterGateway_InterruptKernel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KernelId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterGatewayServer).InterruptKernel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterGateway_InterruptKernel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterGatewayServer).InterruptKernel(ctx, req.(*KernelId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ClusterGateway_ServiceDesc is the grpc.ServiceDesc for ClusterGateway service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -345,8 +513,26 @@ var ClusterGateway_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListKernels",
 			Handler:    _ClusterGateway_ListKernels_Handler,
 		},
+		{
+			MethodName: "StartKernel",
+			Handler:    _ClusterGateway_StartKernel_Handler,
+		},
+		{
+			MethodName: "KillKernel",
+			Handler:    _ClusterGateway_KillKernel_Handler,
+		},
+		{
+			MethodName: "InterruptKernel",
+			Handler:    _ClusterGateway_InterruptKernel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchKernels",
+			Handler:       _ClusterGateway_WatchKernels_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/gateway.proto",
 }
 
@@ -1,19 +1,35 @@
 package main
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/cluster"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/components"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/proxy"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/security"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/server"
 )
 
 func main() {
+	// "driver run ..." runs a workload to completion without serving the web UI.
+	if app.IsServer && len(os.Args) > 1 && os.Args[1] == "run" {
+		os.Exit(runHeadless(os.Args[2:]))
+	}
+
+	// "driver simulate ..." runs a workload against a simulated cluster in virtual time.
+	if app.IsServer && len(os.Args) > 1 && os.Args[1] == "simulate" {
+		os.Exit(runSimulation(os.Args[2:]))
+	}
+
 	conf := config.GetConfiguration()
 
 	app.RouteFunc("/", func() app.Composer {
@@ -59,8 +75,22 @@ func main() {
 		},
 	})
 
+	tlsConfig, err := security.ServerTLSConfig(conf.TLSCert, conf.TLSKey)
+	if err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
+
+	// When spoofing the cluster, serve an in-process fake Cluster Gateway at the configured address, which the frontend dials as usual.
+	var fakeCluster *cluster.FakeCluster
+	if conf.SpoofCluster {
+		fakeCluster, err = startFakeCluster(conf, conf.GatewayAddress)
+		if err != nil {
+			log.Fatalf("Failed to start fake cluster at %s: %v", conf.GatewayAddress, err)
+		}
+	}
+
 	// Used internally (by the frontend) to get the current kubernetes nodes from the backend  (i.e., the backend).
-	http.Handle(domain.KUBERNETES_NODES_ENDPOINT, server.NewKubeNodeHttpHandler(conf))
+	http.Handle(domain.KUBERNETES_NODES_ENDPOINT, server.NewKubeNodeHttpHandler(conf, fakeCluster))
 
 	// Used internally (by the frontend) to get the system config from the backend  (i.e., the backend).
 	http.Handle(domain.SYSTEM_CONFIG_ENDPOINT, server.NewConfigHttpHandler(conf))
@@ -68,10 +98,80 @@ func main() {
 	// Used internally (by the frontend) to get the current set of Jupyter kernel specs from us (i.e., the backend).
 	http.Handle(domain.KERNEL_SPEC_ENDPOINT, server.NewKernelSpecHttpHandler(conf))
 
-	fmt.Printf("WorkloadDriver HTTP server is starting now.")
+	// Used internally (by the frontend) to start, pause, resume, stop, and monitor the workload run.
+	http.Handle(domain.WORKLOAD_ENDPOINT, server.NewWorkloadHttpHandler(conf))
+
+	// Used internally (by the frontend) to execute code on kernels and their replicas.
+	http.Handle(domain.EXECUTE_ENDPOINT, server.NewExecuteHttpHandler(conf))
 
-	// TODO(Ben): Make this port configurable.
-	if err := http.ListenAndServe(":8000", nil); err != nil {
+	fmt.Printf("WorkloadDriver HTTP server is starting now. Listening at %s.\n", conf.ListenAddress)
+
+	if tlsConfig == nil && (conf.AuthToken != "" || conf.AuthHmacSecret != "") {
+		log.Printf("[WARNING] Authentication is enabled, but TLS isn't, so the token is sent in plaintext. Pass --tls-cert and --tls-key to serve TLS.")
+	}
+
+	httpServer := &http.Server{Addr: conf.ListenAddress, TLSConfig: tlsConfig}
+	if tlsConfig != nil {
+		err = httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+
+	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// Create a fake cluster and start serving it at the given address, via TLS if --gateway-tls was passed, and with the
+// configured limits on its websocket connections.
+func startFakeCluster(conf *config.Configuration, addr string) (*cluster.FakeCluster, error) {
+	idleTimeout, err := time.ParseDuration(conf.ProxyIdleTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var tlsConfig *tls.Config
+	if conf.GatewayTLS {
+		if tlsConfig, err = security.ServerTLSConfig(conf.TLSCert, conf.TLSKey); err != nil {
+			return nil, err
+		}
+	}
+
+	fakeCluster := newFakeCluster(conf)
+	fakeCluster.SetProxyOptions(proxy.WebSocketProxyListenerOptions{
+		MaxConnections: conf.ProxyMaxConnections,
+		IdleTimeout:    idleTimeout,
+		OriginPatterns: append(backendOrigins(conf.ListenAddress), conf.AllowedOrigins...),
+	})
+
+	if err := fakeCluster.StartTLS(addr, tlsConfig); err != nil {
+		return nil, err
+	}
+
+	return fakeCluster, nil
+}
+
+// Return the hosts at which the dashboard is served locally, from which the frontend connects to the fake Cluster
+// Gateway. Dashboards served from anywhere else, e.g., via a reverse proxy, must be allowed via --allowed-origins.
+func backendOrigins(listenAddress string) []string {
+	host, port, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return nil
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		return []string{net.JoinHostPort("localhost", port), net.JoinHostPort("127.0.0.1", port)}
+	}
+
+	return []string{net.JoinHostPort(host, port)}
+}
+
+// Create a fake cluster whose kernels churn as often as the frontend queries them.
+func newFakeCluster(conf *config.Configuration) *cluster.FakeCluster {
+	churnInterval, err := time.ParseDuration(conf.KernelQueryInterval)
+	if err != nil {
+		log.Fatalf("Invalid kernel query interval \"%s\": %v", conf.KernelQueryInterval, err)
+	}
+
+	return cluster.NewFakeCluster(churnInterval, conf.Seed)
+}
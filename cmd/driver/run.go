@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/cluster"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/driver"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/export"
+	"go.uber.org/zap"
+)
+
+const (
+	// Exit codes of the headless "run" command.
+	exitSuccess       = 0
+	exitWorkloadError = 1 // The workload ran, but failed.
+	exitUsageError    = 2 // The command was invoked incorrectly, or the workload couldn't be started.
+
+	// How long to wait to connect to the Cluster Gateway before giving up.
+	gatewayDialTimeout = time.Second * 30
+
+	// How long to wait between attempts to connect to the Cluster Gateway.
+	gatewayDialRetryInterval = time.Second
+
+	// How often the fake cluster's simulated hosts are sampled.
+	nodeSampleInterval = time.Second * 5
+
+	// Name of the migration policy's decisions, if any, in the output directory.
+	migrationsName = "migrations"
+)
+
+// Run a workload to completion without the web UI and write its results to the output directory.
+// Usage: driver run --workload w.yaml [--gateway host:port] [--out results/] [other configuration flags]
+func runHeadless(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	build := config.RegisterFlags(flags)
+	gatewayFlag := flags.String("gateway", "", "Address of the Cluster Gateway's gRPC server. Implies --spoof-cluster=false unless that is set explicitly.")
+	outFlag := flags.String("out", "results", "Directory to which the results of the workload are written.")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsageError
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		panic(err)
+	}
+	defer logger.Sync()
+
+	conf, err := build()
+	if err != nil {
+		logger.Error("Invalid configuration.", zap.Error(err))
+		return exitUsageError
+	}
+
+	if conf.Workload == nil {
+		logger.Error("A workload specification is required. Pass one with --workload.")
+		return exitUsageError
+	}
+
+	if *gatewayFlag != "" {
+		conf.GatewayAddress = *gatewayFlag
+
+		spoofSet := false
+		flags.Visit(func(f *flag.Flag) {
+			spoofSet = spoofSet || f.Name == "spoof-cluster"
+		})
+
+		if !spoofSet {
+			conf.SpoofCluster = false
+		}
+	}
+
+	if err := os.MkdirAll(*outFlag, 0o755); err != nil {
+		logger.Error("Failed to create output directory.", zap.String("out", *outFlag), zap.Error(err))
+		return exitUsageError
+	}
+
+	// When spoofing the cluster, run a fake Cluster Gateway in-process on an unused port and connect to it as usual.
+	var fakeCluster *cluster.FakeCluster
+	if conf.SpoofCluster {
+		fakeCluster, err = startFakeCluster(conf, "127.0.0.1:0")
+		if err != nil {
+			logger.Error("Failed to start fake cluster.", zap.Error(err))
+			return exitUsageError
+		}
+		defer fakeCluster.Stop()
+
+		conf.GatewayAddress = fakeCluster.Addr()
+	}
+
+	logger.Info("Running workload headless.", zap.String("workload", conf.Workload.Name), zap.String("gateway", conf.GatewayAddress), zap.Bool("spoof-cluster", conf.SpoofCluster), zap.String("out", *outFlag))
+
+	workloadDriver := driver.NewWorkloadDriver(driver.NewLoggerErrorHandler(logger), conf)
+
+	// The nodes are normally fetched via the backend's node endpoint, but there's no backend when running headless.
+	workloadDriver.DisableNodeWatch()
+
+	if err := dialGateway(workloadDriver, conf.GatewayAddress); err != nil {
+		logger.Error("Failed to connect to the Cluster Gateway.", zap.String("gateway", conf.GatewayAddress), zap.Error(err))
+		return exitUsageError
+	}
+
+	// Without a backend, only the fake cluster's simulated hosts can be sampled. Otherwise, the nodes aren't exported.
+	var nodeSampler *export.NodeSampler
+	if fakeCluster != nil {
+		nodeSampler = export.NewPollingNodeSampler(fakeClusterNodes(fakeCluster), nodeSampleInterval)
+		nodeSampler.Start()
+	} else {
+		logger.Warn("Not exporting the nodes, as they can only be sampled when spoofing the cluster.")
+	}
+
+	if err := workloadDriver.StartWorkload(conf.Workload); err != nil {
+		logger.Error("Failed to start workload.", zap.Error(err))
+		return exitUsageError
+	}
+
+	// Abort the workload (tearing down its sessions) if we're interrupted.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		if err := workloadDriver.StopWorkload(); err != nil && !errors.Is(err, domain.ErrNoActiveWorkload) {
+			logger.Error("Failed to stop workload.", zap.Error(err))
+		}
+	}()
+
+	workloadErr := workloadDriver.WaitWorkload()
+	run := workloadDriver.WorkloadRun()
+
+	results := &export.Results{
+		Run:       run,
+		Events:    workloadDriver.WorkloadEvents(),
+		Samples:   workloadDriver.Metrics().Samples(),
+		Latencies: workloadDriver.Metrics().Report(),
+	}
+
+	if nodeSampler != nil {
+		nodeSampler.Stop()
+		results.Nodes = nodeSampler.Samples()
+	}
+
+	if conf.MigrationPolicy != domain.MigrationPolicyNone {
+		results.Extra = map[string]interface{}{migrationsName: workloadDriver.MigrationDecisions()}
+	}
+
+	if _, err := export.Write(*outFlag, conf, results); err != nil {
+		logger.Error("Failed to write results.", zap.String("out", *outFlag), zap.Error(err))
+		return exitWorkloadError
+	}
+
+	if workloadErr != nil || run.State != domain.WorkloadRunFinished {
+		logger.Error("Workload failed.", zap.String("state", string(run.State)), zap.Int("num-errors", run.NumErrors), zap.Error(workloadErr))
+		return exitWorkloadError
+	}
+
+	logger.Info("Workload completed successfully.", zap.Int("sessions", run.SessionsCompleted), zap.Int("cells", run.CellsCompleted), zap.String("out", *outFlag))
+	return exitSuccess
+}
+
+// Connect to the Cluster Gateway, giving up after gatewayDialTimeout. DialGatewayGRPC fails if the Cluster Gateway is
+// unreachable, so we keep trying until then, in case it is still starting.
+func dialGateway(workloadDriver domain.WorkloadDriver, address string) error {
+	deadline := time.Now().Add(gatewayDialTimeout)
+
+	for {
+		err := workloadDriver.DialGatewayGRPC(address)
+		if err == nil || errors.Is(err, domain.ErrEmptyGatewayAddr) {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %v: %w", gatewayDialTimeout, err)
+		}
+
+		time.Sleep(gatewayDialRetryInterval)
+	}
+}
+
+// Return the fake cluster's simulated hosts, ordered by ID.
+func fakeClusterNodes(fakeCluster *cluster.FakeCluster) func() []*domain.KubernetesNode {
+	return func() []*domain.KubernetesNode {
+		hosts := fakeCluster.Nodes()
+
+		nodes := make([]*domain.KubernetesNode, 0, len(hosts))
+		for _, node := range hosts {
+			nodes = append(nodes, node)
+		}
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].NodeId < nodes[j].NodeId })
+
+		return nodes
+	}
+}
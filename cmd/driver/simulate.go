@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/cluster"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/export"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/simulator"
+	"go.uber.org/zap"
+)
+
+const (
+	// Name of the simulation's report in the output directory.
+	simulationReportName = "simulation"
+)
+
+// Run a workload against a simulated cluster in virtual time and write its results to the output directory.
+// Usage: driver simulate --workload w.yaml [--out results/] [--hosts 16] [--replicas 3] [--policy least-loaded] [--seed 0]
+func runSimulation(args []string) int {
+	defaults := simulator.DefaultOptions()
+
+	policies := make([]string, 0, len(cluster.PlacementPolicies))
+	for _, policy := range cluster.PlacementPolicies {
+		policies = append(policies, string(policy))
+	}
+
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	build := config.RegisterFlags(flags)
+	outFlag := flags.String("out", "results", "Directory to which the results of the simulation are written.")
+	hostsFlag := flags.Int("hosts", defaults.Hosts.NumHosts, "Number of simulated hosts.")
+	replicasFlag := flags.Int("replicas", defaults.NumReplicas, "Number of replicas of each kernel.")
+	policyFlag := flags.String("policy", string(defaults.Policy), fmt.Sprintf("Placement policy. One of: %s.", strings.Join(policies, ", ")))
+	schedulingDelayFlag := flags.Duration("scheduling-delay", defaults.SchedulingDelay, "Mean time taken to start a kernel's replicas once they have been placed.")
+	migrationDelayFlag := flags.Duration("migration-delay", defaults.MigrationDelay, "Mean time taken to migrate a replica.")
+	sampleIntervalFlag := flags.Duration("sample-interval", defaults.SampleInterval, "How often the utilization of the hosts is sampled, in simulated time.")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsageError
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		panic(err)
+	}
+	defer logger.Sync()
+
+	conf, err := build()
+	if err != nil {
+		logger.Error("Invalid configuration.", zap.Error(err))
+		return exitUsageError
+	}
+
+	if conf.Workload == nil {
+		logger.Error("A workload specification is required. Pass one with --workload.")
+		return exitUsageError
+	}
+
+	opts := defaults
+	opts.Hosts.NumHosts = *hostsFlag
+	opts.NumReplicas = *replicasFlag
+	opts.Policy = cluster.PlacementPolicy(*policyFlag)
+	opts.SchedulingDelay = *schedulingDelayFlag
+	opts.MigrationDelay = *migrationDelayFlag
+	opts.SampleInterval = *sampleIntervalFlag
+	opts.Seed = conf.Seed
+
+	sim, err := simulator.New(conf.Workload, opts)
+	if err != nil {
+		logger.Error("Invalid simulation.", zap.Error(err))
+		return exitUsageError
+	}
+
+	if err := os.MkdirAll(*outFlag, 0o755); err != nil {
+		logger.Error("Failed to create output directory.", zap.String("out", *outFlag), zap.Error(err))
+		return exitUsageError
+	}
+
+	logger.Info("Simulating workload.", zap.String("workload", conf.Workload.Name), zap.Int("hosts", opts.Hosts.NumHosts), zap.Int("replicas", opts.NumReplicas), zap.String("policy", string(opts.Policy)), zap.String("out", *outFlag))
+
+	results, report, workloadErr := sim.Run()
+	results.Extra = map[string]interface{}{simulationReportName: report}
+
+	if _, err := export.Write(*outFlag, conf, results); err != nil {
+		logger.Error("Failed to write results.", zap.String("out", *outFlag), zap.Error(err))
+		return exitWorkloadError
+	}
+
+	fmt.Fprintf(os.Stdout, "Simulation of workload \"%s\":\n%s\nLatencies:\n%s", conf.Workload.Name, report, results.Latencies)
+
+	if workloadErr != nil {
+		logger.Error("Workload failed.", zap.Int("num-errors", results.Run.NumErrors), zap.Error(workloadErr))
+		return exitWorkloadError
+	}
+
+	logger.Info("Simulation completed successfully.", zap.Int("sessions", results.Run.SessionsCompleted), zap.Int("cells", results.Run.CellsCompleted), zap.String("out", *outFlag))
+	return exitSuccess
+}
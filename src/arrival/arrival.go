@@ -0,0 +1,131 @@
+package arrival
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+const (
+	// Returned by Process.Next when no further arrivals will occur, e.g., because the rate of the process has dropped to zero for good.
+	Never = time.Duration(math.MaxInt64)
+)
+
+// An arrival process, e.g., of sessions or of cell submissions.
+// Processes are seeded and therefore generate the same sequence of arrivals every time. They are NOT safe for concurrent use.
+type Process interface {
+	// Return the time between the previous arrival (or the start of the process) and the next arrival.
+	// Returns Never if there will be no further arrivals.
+	Next() time.Duration
+}
+
+// Create the arrival process described by the given specification.
+// The specification is assumed to have been validated already.
+func New(spec *domain.WorkloadArrival, seed int64) (Process, error) {
+	switch spec.Process {
+	case domain.ArrivalProcessConstant:
+		return NewConstant(spec.Rate), nil
+	case domain.ArrivalProcessPoisson:
+		return NewPoisson(spec.Rate, seed), nil
+	case domain.ArrivalProcessBursty:
+		return NewBursty(spec.Rate, spec.BurstRate, spec.MeanIdle, spec.MeanBurst, seed), nil
+	case domain.ArrivalProcessDiurnal:
+		return NewDiurnal(spec.Rate, spec.Amplitude, spec.Period, spec.Phase, seed), nil
+	case domain.ArrivalProcessStep:
+		return NewStep(spec.Rate, spec.Steps, seed), nil
+	case domain.ArrivalProcessRamp:
+		return NewRamp(spec.Rate, spec.EndRate, spec.RampDuration, seed), nil
+	default:
+		return nil, fmt.Errorf("unknown arrival process \"%s\"", spec.Process)
+	}
+}
+
+// Derive a seed from a parent seed and a set of labels, e.g., the name of a tenant.
+// This lets independent processes (one per tenant, one per session, etc.) be seeded from a single workload seed
+// without the sequence of one process depending upon how many arrivals were drawn from another.
+func DeriveSeed(seed int64, labels ...string) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d", seed)
+	for _, label := range labels {
+		h.Write([]byte{0})
+		h.Write([]byte(label))
+	}
+
+	return int64(h.Sum64())
+}
+
+// Return the offsets, relative to the start of the process, of the process' first n arrivals.
+// If the process stops generating arrivals, then the remaining offsets are Never.
+func Offsets(p Process, n int) []time.Duration {
+	offsets := make([]time.Duration, n)
+
+	var elapsed time.Duration
+	for i := range offsets {
+		if elapsed == Never {
+			offsets[i] = Never
+			continue
+		}
+
+		offsets[i] = elapsed
+
+		gap := p.Next()
+		if gap == Never || gap > Never-elapsed {
+			elapsed = Never
+		} else {
+			elapsed += gap
+		}
+	}
+
+	return offsets
+}
+
+// Counts the arrivals of a process that occur within successive windows of time, e.g., between two refreshes.
+type Counter struct {
+	process Process
+	elapsed time.Duration // End of the last window.
+	next    time.Duration // Time of the next arrival.
+}
+
+func NewCounter(p Process) *Counter {
+	return &Counter{process: p, next: p.Next()}
+}
+
+// Advance the counter by the given window and return the number of arrivals that occurred within it.
+func (c *Counter) Count(window time.Duration) int {
+	if window > Never-c.elapsed {
+		c.elapsed = Never
+	} else {
+		c.elapsed += window
+	}
+
+	count := 0
+	for c.next != Never && c.next <= c.elapsed {
+		count++
+
+		gap := c.process.Next()
+		if gap == Never || gap > Never-c.next {
+			c.next = Never
+		} else {
+			c.next += gap
+		}
+	}
+
+	return count
+}
+
+// Convert a number of seconds to a duration, saturating at Never.
+func seconds(s float64) time.Duration {
+	if math.IsInf(s, 1) || s*float64(time.Second) >= float64(Never) {
+		return Never
+	}
+
+	return time.Duration(s * float64(time.Second))
+}
+
+func newRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
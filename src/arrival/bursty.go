@@ -0,0 +1,58 @@
+package arrival
+
+import (
+	"math/rand"
+	"time"
+)
+
+// A two-state Markov-modulated Poisson process (MMPP). The process alternates between an idle state and a bursty state,
+// spending an exponentially-distributed amount of time in each. While in a state, arrivals follow a Poisson process with that state's rate.
+type burstyProcess struct {
+	rng *rand.Rand
+
+	rates       [2]float64 // Arrivals per second while idle and bursting.
+	meanSojourn [2]float64 // Mean time spent in each state, in seconds.
+	state       int        // 0 if idle, 1 if bursting.
+	remaining   float64    // Time left in the current state, in seconds.
+}
+
+const (
+	burstyStateIdle  = 0
+	burstyStateBurst = 1
+)
+
+// Create a bursty (MMPP) process. The process starts in the idle state.
+func NewBursty(idleRate float64, burstRate float64, meanIdle time.Duration, meanBurst time.Duration, seed int64) Process {
+	p := &burstyProcess{
+		rng:         newRand(seed),
+		rates:       [2]float64{idleRate, burstRate},
+		meanSojourn: [2]float64{meanIdle.Seconds(), meanBurst.Seconds()},
+		state:       burstyStateIdle,
+	}
+	p.remaining = p.rng.ExpFloat64() * p.meanSojourn[p.state]
+
+	return p
+}
+
+func (p *burstyProcess) Next() time.Duration {
+	if p.rates[burstyStateIdle] <= 0 && p.rates[burstyStateBurst] <= 0 {
+		return Never
+	}
+
+	var wait float64
+	for {
+		rate := p.rates[p.state]
+		if rate > 0 {
+			// The exponential distribution is memoryless, so we can simply redraw the candidate after every state change.
+			candidate := p.rng.ExpFloat64() / rate
+			if candidate < p.remaining {
+				p.remaining -= candidate
+				return seconds(wait + candidate)
+			}
+		}
+
+		wait += p.remaining
+		p.state = 1 - p.state
+		p.remaining = p.rng.ExpFloat64() * p.meanSojourn[p.state]
+	}
+}
@@ -0,0 +1,49 @@
+package arrival
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Arrivals occur at fixed intervals. The first arrival occurs immediately.
+type constantProcess struct {
+	interval time.Duration
+	started  bool
+}
+
+// Create a process whose arrivals occur exactly 1/rate seconds apart. Returns a process that never generates any arrivals if rate is not positive.
+func NewConstant(rate float64) Process {
+	if rate <= 0 {
+		return &constantProcess{interval: Never, started: true}
+	}
+
+	return &constantProcess{interval: seconds(1.0 / rate)}
+}
+
+func (p *constantProcess) Next() time.Duration {
+	if !p.started {
+		p.started = true
+		return 0
+	}
+
+	return p.interval
+}
+
+// A homogeneous Poisson process, i.e., exponentially-distributed inter-arrival times.
+type poissonProcess struct {
+	rate float64
+	rng  *rand.Rand
+}
+
+// Create a Poisson process with the given mean number of arrivals per second.
+func NewPoisson(rate float64, seed int64) Process {
+	return &poissonProcess{rate: rate, rng: newRand(seed)}
+}
+
+func (p *poissonProcess) Next() time.Duration {
+	if p.rate <= 0 {
+		return Never
+	}
+
+	return seconds(p.rng.ExpFloat64() / p.rate)
+}
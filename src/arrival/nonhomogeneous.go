@@ -0,0 +1,116 @@
+package arrival
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+// A non-homogeneous Poisson process, i.e., a Poisson process whose rate varies over time.
+// Arrivals are generated by thinning (Lewis & Shedler): candidates are drawn from a homogeneous process
+// whose rate bounds the actual rate, and each candidate is accepted with probability rate(t)/bound.
+type nonHomogeneousProcess struct {
+	rng *rand.Rand
+	now float64 // Time of the last arrival, in seconds since the start of the process.
+
+	rate  func(t float64) float64 // The rate at time t, in arrivals per second.
+	bound func(t float64) float64 // An upper bound on the rate at every time from t onwards. Must be non-increasing.
+}
+
+func (p *nonHomogeneousProcess) Next() time.Duration {
+	start := p.now
+	for {
+		bound := p.bound(p.now)
+		if bound <= 0 {
+			p.now = math.Inf(1)
+			return Never
+		}
+
+		p.now += p.rng.ExpFloat64() / bound
+		if p.rng.Float64()*bound <= p.rate(p.now) {
+			return seconds(p.now - start)
+		}
+	}
+}
+
+// Create a process whose rate oscillates sinusoidally around the given mean rate, e.g., to model daily usage patterns.
+// The rate at time t is rate + amplitude*sin(2π(t+phase)/period). The amplitude is clamped to the mean rate.
+func NewDiurnal(rate float64, amplitude float64, period time.Duration, phase time.Duration, seed int64) Process {
+	amplitude = math.Min(math.Abs(amplitude), rate)
+
+	return &nonHomogeneousProcess{
+		rng: newRand(seed),
+		rate: func(t float64) float64 {
+			if period <= 0 {
+				return rate
+			}
+
+			return rate + amplitude*math.Sin(2*math.Pi*(t+phase.Seconds())/period.Seconds())
+		},
+		bound: func(t float64) float64 {
+			return rate + amplitude
+		},
+	}
+}
+
+// Create a process whose rate is piecewise constant: it starts at the initial rate and changes at each of the steps.
+func NewStep(initialRate float64, steps []*domain.RateStep, seed int64) Process {
+	sorted := make([]*domain.RateStep, len(steps))
+	copy(sorted, steps)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].At < sorted[j].At
+	})
+
+	// suffixMax[i] is the highest rate from step i onwards.
+	suffixMax := make([]float64, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		suffixMax[i] = math.Max(sorted[i].Rate, suffixMax[i+1])
+	}
+
+	// Return the index of the first step that has not yet occurred at time t.
+	nextStep := func(t float64) int {
+		return sort.Search(len(sorted), func(i int) bool {
+			return sorted[i].At.Seconds() > t
+		})
+	}
+
+	rate := func(t float64) float64 {
+		i := nextStep(t)
+		if i == 0 {
+			return initialRate
+		}
+
+		return sorted[i-1].Rate
+	}
+
+	return &nonHomogeneousProcess{
+		rng:  newRand(seed),
+		rate: rate,
+		bound: func(t float64) float64 {
+			return math.Max(rate(t), suffixMax[nextStep(t)])
+		},
+	}
+}
+
+// Create a process whose rate changes linearly from the initial rate to the end rate over the given duration,
+// after which it remains at the end rate.
+func NewRamp(initialRate float64, endRate float64, duration time.Duration, seed int64) Process {
+	rate := func(t float64) float64 {
+		if duration <= 0 || t >= duration.Seconds() {
+			return endRate
+		}
+
+		return initialRate + (endRate-initialRate)*t/duration.Seconds()
+	}
+
+	return &nonHomogeneousProcess{
+		rng:  newRand(seed),
+		rate: rate,
+		bound: func(t float64) float64 {
+			return math.Max(rate(t), endRate)
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package cluster
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	// Mean number of kernels created during each round of churn.
+	kernelsPerChurn = 1.5
+)
+
+// Spoof the resources used by each replica of a kernel. Must be called with the mutex held.
+func (c *FakeCluster) spoofResources() *gateway.ResourceSpec {
+	return &gateway.ResourceSpec{
+		Cpu:    int32(c.rng.Intn(4)+1) * 100,
+		Memory: int32(c.rng.Intn(8)+1) * 1024,
+		Gpu:    int32(c.rng.Intn(2)+1) * 100,
+	}
+}
+
+// Create an individual spoofed/fake kernel, with its replicas placed by the placement policy.
+// Returns nil if the replicas don't fit. Must be called with the mutex held.
+func (c *FakeCluster) spoofKernel() *fakeKernel {
+	status := domain.KernelStatuses[c.rng.Intn(len(domain.KernelStatuses))]
+	numReplicas := c.rng.Intn(5-2) + 2
+
+	kernel, err := c.createKernel(c.spoofId(), numReplicas, c.spoofResources())
+	if err != nil {
+		c.logger.Debug("Could not spoof kernel.", zap.Error(err))
+		return nil
+	}
+
+	// Busy kernels are executing, so one of their replicas must have GPUs committed to it.
+	if status == "busy" {
+		if _, err := c.bindExecutor(kernel, kernel.resources.Gpu); err != nil {
+			status = "idle"
+		}
+	}
+
+	kernel.kernel.Status = status
+	kernel.kernel.AggregateBusyStatus = status
+	c.notifyChanged()
+
+	return kernel
+}
+
+// Called when spoofing kernels for the first time.
+func (c *FakeCluster) spoofInitialKernels() {
+	numKernels := c.rng.Intn(8-2) + 2
+
+	for i := 0; i < numKernels; i++ {
+		c.spoofKernel()
+	}
+}
+
+// Randomly remove a few kernels and add a few new ones. Must be called with the mutex held.
+func (c *FakeCluster) spoofChurn() {
+	maxDelete := int(math.Ceil((0.50 * float64(len(c.kernels)))))     // Remove up to 50% of the existing number of the spoofed kernels.
+	numToDelete := c.rng.Intn(int(math.Max(2, float64(maxDelete+1)))) // Delete UP TO this many.
+	numToAdd := c.arrivals.Count(c.churnInterval)                     // The number of kernels that "arrived" since the last round.
+
+	numDeleted := 0
+	if numToDelete > 0 && len(c.kernels) > 0 {
+		// Sort the kernels so that the victims depend only upon the seed.
+		kernelIds := make([]string, 0, len(c.kernels))
+		for id := range c.kernels {
+			kernelIds = append(kernelIds, id)
+		}
+		sort.Strings(kernelIds)
+
+		for i := 0; i < numToDelete; i++ {
+			// We may select the same victim multiple times. It will only be deleted once, of course.
+			victim := kernelIds[c.rng.Intn(len(kernelIds))]
+			if kernel, ok := c.kernels[victim]; ok {
+				c.destroyKernel(kernel)
+				numDeleted++
+			}
+		}
+	}
+
+	for i := 0; i < numToAdd; i++ {
+		c.spoofKernel()
+	}
+
+	c.logger.Debug("Spoofed kernel churn.", zap.Int("added", numToAdd), zap.Int("removed", numDeleted), zap.Int("num-kernels", len(c.kernels)))
+}
+
+// Periodically create and destroy kernels until the quit channel is closed.
+func (c *FakeCluster) churn(quit chan struct{}) {
+	ticker := time.NewTicker(c.churnInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.mutex.Lock()
+			c.spoofChurn()
+			c.mutex.Unlock()
+		case <-quit:
+			return
+		}
+	}
+}
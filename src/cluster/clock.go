@@ -0,0 +1,44 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+)
+
+// Tells the time. The live fake cluster uses the wall clock, while the simulator uses a virtual one.
+type Clock interface {
+	Now() time.Time
+}
+
+type wallClock struct{}
+
+func (wallClock) Now() time.Time {
+	return time.Now()
+}
+
+// A clock that only moves when it is told to. Safe for concurrent use.
+type VirtualClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{now: start}
+}
+
+func (c *VirtualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+// Move the clock to the given time. The clock never moves backwards, so earlier times are ignored.
+func (c *VirtualClock) Set(t time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if t.After(c.now) {
+		c.now = t
+	}
+}
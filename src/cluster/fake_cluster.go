@@ -1,24 +1,121 @@
 package cluster
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
 	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
-	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/arrival"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/proxy"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	fakeClusterNumHosts = 4
+
+	// Capacity of each simulated host, unless otherwise specified.
+	fakeHostCPU    = 64  // In cores.
+	fakeHostMemory = 256 // In GB.
+	fakeHostGPUs   = 8
+
+	// Port reported to kernel replicas for their SMR (Raft) traffic.
+	fakeSmrPort = 8080
+
+	// Number of replicas of each kernel started via StartKernel.
+	fakeKernelNumReplicas = 3
+
+	// First of the consecutive ports reported in the connection info of a kernel started via StartKernel.
+	fakeKernelBasePort = 9001
+
+	// Upper bounds on the simulated latency of listing kernels and of migrating a replica.
+	maxListKernelsDelay = time.Millisecond * 500
+	maxMigrationDelay   = time.Millisecond * 1500
+	maxStartKernelDelay = time.Millisecond * 1000
+)
+
+var (
+	ErrAlreadyStarted = errors.New("the fake cluster has already been started")
 )
 
+// Return the default capacity of a simulated host, in the units used by gateway.ResourceSpec.
+func DefaultHostCapacity() *gateway.ResourceSpec {
+	return &gateway.ResourceSpec{
+		Cpu:    fakeHostCPU * 100,
+		Memory: fakeHostMemory * 1024,
+		Gpu:    fakeHostGPUs * 100,
+	}
+}
+
 // Spoof a Gateway Cluster for testing.
+// Implements the Cluster Gateway's gRPC interface in-process, with simulated hosts, kernels, replicas, and migrations,
+// and serves it over the same websocket-wrapped gRPC transport as the real Cluster Gateway.
 type FakeCluster struct {
-	Nodes   []*domain.KubernetesNode
-	Kernels []*gateway.DistributedJupyterKernel
+	gateway.UnimplementedClusterGatewayServer
+
+	id            string
+	churnInterval time.Duration   // How often kernels are created and destroyed in the background. Zero if there is no churn.
+	policy        PlacementPolicy // Decides where replicas are placed.
+	clock         Clock
+
+	mutex    sync.Mutex // Synchronizes access to the fields below.
+	hosts    map[string]*fakeHost
+	kernels  map[string]*fakeKernel
+	rng      *rand.Rand       // All of the simulated randomness is drawn from this, so that the cluster is repeatable for a given seed.
+	arrivals *arrival.Counter // Determines how many kernels are created during each round of churn.
+
+	subscribers map[chan struct{}]struct{} // Notified whenever the kernels or hosts change. See Changes.
+
+	addr         net.Addr
+	grpcServer   *grpc.Server
+	httpServer   *http.Server
+	quit         chan struct{}
+	proxyOptions proxy.WebSocketProxyListenerOptions // Limits on the websocket connections over which the gRPC interface is served.
 
 	logger *zap.Logger
 }
 
-func NewFakeCluster() *FakeCluster {
+// Create a fake cluster for the live driver. Kernels are created and destroyed every churnInterval once it is started.
+func NewFakeCluster(churnInterval time.Duration, seed int64) *FakeCluster {
+	cluster := newFakeCluster(HostSpec{NumHosts: fakeClusterNumHosts, Capacity: DefaultHostCapacity()}, PlacementLeastLoaded, wallClock{}, seed)
+	cluster.churnInterval = churnInterval
+	cluster.arrivals = arrival.NewCounter(arrival.NewPoisson(kernelsPerChurn/churnInterval.Seconds(), arrival.DeriveSeed(seed, "kernels")))
+	cluster.spoofInitialKernels()
+
+	return cluster
+}
+
+// Create an empty fake cluster for the simulator. Its kernels are created and destroyed only when told to, and it
+// tells the time using the given clock, so that it can be driven in virtual time.
+func NewSimulatedCluster(hosts HostSpec, policy PlacementPolicy, clock Clock, seed int64) *FakeCluster {
+	cluster := newFakeCluster(hosts, policy, clock, seed)
+	cluster.logger = zap.NewNop() // The simulator reports on the run itself; per-replica logs would only slow it down.
+
+	return cluster
+}
+
+func newFakeCluster(hosts HostSpec, policy PlacementPolicy, clock Clock, seed int64) *FakeCluster {
 	cluster := &FakeCluster{
-		Nodes:   make([]*domain.KubernetesNode, 0),
-		Kernels: make([]*gateway.DistributedJupyterKernel, 0),
+		policy:      policy,
+		clock:       clock,
+		hosts:       make(map[string]*fakeHost, hosts.NumHosts),
+		kernels:     make(map[string]*fakeKernel),
+		rng:         rand.New(rand.NewSource(seed)),
+		subscribers: make(map[chan struct{}]struct{}),
 	}
+	cluster.id = cluster.spoofId()
 
 	var err error
 	cluster.logger, err = zap.NewDevelopment()
@@ -26,10 +123,394 @@ func NewFakeCluster() *FakeCluster {
 		panic(err)
 	}
 
+	for i := 1; i <= hosts.NumHosts; i++ {
+		host := &fakeHost{
+			id:        fmt.Sprintf("Node-%d", i),
+			ip:        fmt.Sprintf("10.0.%d.%d", i/256, i%256),
+			createdAt: clock.Now(),
+			capacity:  proto.Clone(hosts.Capacity).(*gateway.ResourceSpec),
+		}
+		cluster.hosts[host.id] = host
+	}
+
 	return cluster
 }
 
-// Start the FakeCluster.
-func (c *FakeCluster) Start() {
+// Limit the websocket connections over which the gRPC interface is served. Takes effect the next time the cluster is started.
+func (c *FakeCluster) SetProxyOptions(opts proxy.WebSocketProxyListenerOptions) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.proxyOptions = opts
+}
+
+// Start the FakeCluster, serving its gRPC interface at the given address. Use ":0" to pick an unused port.
+func (c *FakeCluster) Start(addr string) error {
+	return c.StartTLS(addr, nil)
+}
+
+// Start the FakeCluster, serving its gRPC interface via wss:// (rather than ws://) at the given address.
+// If tlsConfig is nil, then TLS isn't used.
+func (c *FakeCluster) StartTLS(addr string, tlsConfig *tls.Config) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.grpcServer != nil {
+		return ErrAlreadyStarted
+	}
+
+	tcpListener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	wsListener := proxy.NewWebSocketProxyListenerWithOptions(tcpListener.Addr(), c.proxyOptions)
+	if tlsConfig != nil {
+		tcpListener = tls.NewListener(tcpListener, tlsConfig)
+	}
+
+	c.addr = tcpListener.Addr()
+	c.httpServer = &http.Server{Handler: wsListener}
+	c.grpcServer = grpc.NewServer()
+	c.quit = make(chan struct{})
+	gateway.RegisterClusterGatewayServer(c.grpcServer, c)
+
+	go func() {
+		if err := c.httpServer.Serve(tcpListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.logger.Error("Fake cluster's HTTP server failed.", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		if err := c.grpcServer.Serve(wsListener); err != nil {
+			c.logger.Error("Fake cluster's gRPC server failed.", zap.Error(err))
+		}
+	}()
+
+	if c.arrivals != nil {
+		go c.churn(c.quit)
+	}
+
+	c.logger.Info("Fake cluster is serving.", zap.String("address", c.addr.String()), zap.Int("num-hosts", len(c.hosts)), zap.Int("num-kernels", len(c.kernels)))
+
+	return nil
+}
+
+// Stop serving, and stop creating and destroying kernels.
+func (c *FakeCluster) Stop() {
+	c.mutex.Lock()
+	grpcServer, httpServer, quit := c.grpcServer, c.httpServer, c.quit
+	c.grpcServer, c.httpServer, c.quit = nil, nil, nil
+	c.mutex.Unlock()
+
+	if grpcServer == nil {
+		return
+	}
+
+	close(quit)
+	grpcServer.Stop()
+	httpServer.Close()
+}
+
+// Return the address at which the cluster is serving, or the empty string if it hasn't been started.
+func (c *FakeCluster) Addr() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.addr == nil {
+		return ""
+	}
+
+	return c.addr.String()
+}
+
+// Sleep for a random duration of up to bound, or until the context is done. Must be called WITHOUT the mutex held.
+func (c *FakeCluster) simulateDelay(ctx context.Context, bound time.Duration) error {
+	c.mutex.Lock()
+	delay := time.Duration(c.rng.Int63n(int64(bound)))
+	c.mutex.Unlock()
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// ID returns the cluster gateway id and can be used to test connectivity.
+func (c *FakeCluster) ID(ctx context.Context, in *gateway.Void) (*gateway.ProvisionerId, error) {
+	return &gateway.ProvisionerId{Id: c.id}, nil
+}
+
+// RemoveHost removes a host from the cluster. Its replicas are migrated to the remaining hosts.
+func (c *FakeCluster) RemoveHost(ctx context.Context, in *gateway.HostId) (*gateway.Void, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.removeHost(in.Id); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &gateway.Void{}, nil
+}
+
+// MigrateKernelReplica moves a replica to another host: the one specified by the request, if any, or else one chosen by the placement policy.
+func (c *FakeCluster) MigrateKernelReplica(ctx context.Context, in *gateway.MigrationRequest) (*gateway.MigrateKernelResponse, error) {
+	if in.TargetReplica == nil {
+		return nil, status.Error(codes.InvalidArgument, "no target replica specified")
+	}
+
+	kernelId, replicaId := in.TargetReplica.KernelId, in.TargetReplica.ReplicaId
+
+	c.mutex.Lock()
+	kernel, replica, err := c.getReplica(kernelId, replicaId)
+	if err != nil {
+		c.mutex.Unlock()
+		return nil, toStatus(err)
+	}
+
+	host, err := c.migrationTarget(kernel, replica, in.GetTargetNodeId(), 0)
+	c.mutex.Unlock()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	// Simulate the time taken to start the new replica and transfer the old one's state.
+	if err := c.simulateDelay(ctx, maxMigrationDelay); err != nil {
+		return nil, err
+	}
+
+	// The kernel or the target host may have changed in the meantime, in which case this fails.
+	target, err := c.MigrateReplica(kernelId, replicaId, host.id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &gateway.MigrateKernelResponse{Id: replicaId, Hostname: target}, nil
+}
+
+// Notify the Gateway that a distributed kernel replica has started somewhere.
+func (c *FakeCluster) NotifyKernelRegistered(ctx context.Context, in *gateway.KernelRegistrationNotification) (*gateway.KernelRegistrationNotificationResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	kernel, ok := c.kernels[in.KernelId]
+	if !ok {
+		kernel = &fakeKernel{
+			kernel: &gateway.DistributedJupyterKernel{
+				KernelId:            in.KernelId,
+				Status:              "starting",
+				AggregateBusyStatus: "starting",
+				Replicas:            make([]*gateway.JupyterKernelReplica, 0),
+			},
+			resources:    c.spoofResources(),
+			persistentId: c.spoofId(),
+			createdAt:    c.clock.Now(),
+		}
+		c.kernels[in.KernelId] = kernel
+	}
+
+	// Replace the replica if it has registered before, e.g., because it was restarted.
+	replica, err := kernel.getReplica(in.ReplicaId)
+	if err == nil {
+		c.unplaceReplica(kernel, replica)
+	} else {
+		replica = &gateway.JupyterKernelReplica{
+			KernelId:  in.KernelId,
+			ReplicaId: in.ReplicaId,
+		}
+		kernel.kernel.Replicas = append(kernel.kernel.Replicas, replica)
+		kernel.kernel.NumReplicas++
+	}
+
+	// The replica is already running, so it's placed even if its host is over-committed.
+	host, ok := c.hosts[in.HostId]
+	if !ok {
+		if host = c.selectHost(kernel, 0); host == nil {
+			host = c.sortedHosts()[0]
+		}
+	}
+
+	c.placeReplica(kernel, replica, host)
+	if in.PodName != "" {
+		replica.PodId = in.PodName
+	}
+	c.notifyChanged()
+
+	replicas := make(map[int32]string, len(kernel.kernel.Replicas))
+	for _, r := range kernel.kernel.Replicas {
+		if h, ok := c.hosts[r.NodeId]; ok {
+			replicas[r.ReplicaId] = fmt.Sprintf("%s:%d", h.ip, fakeSmrPort)
+		}
+	}
+
+	c.logger.Info("Kernel replica registered.", zap.String("kernel-id", in.KernelId), zap.Int32("replica-id", in.ReplicaId), zap.String("host", host.id))
+
+	return &gateway.KernelRegistrationNotificationResponse{
+		Id:           in.ReplicaId,
+		Replicas:     replicas,
+		PersistentId: proto.String(kernel.persistentId),
+		SmrPort:      fakeSmrPort,
+	}, nil
+}
+
+func (c *FakeCluster) SmrReady(ctx context.Context, in *gateway.SmrReadyNotification) (*gateway.Void, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
+	kernel, _, err := c.getReplica(in.KernelId, in.ReplicaId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	// The kernel becomes usable once its replicas have joined the SMR cluster.
+	kernel.kernel.Status = "idle"
+	kernel.kernel.AggregateBusyStatus = "idle"
+	c.notifyChanged()
+
+	return &gateway.Void{}, nil
+}
+
+func (c *FakeCluster) SmrNodeAdded(ctx context.Context, in *gateway.ReplicaInfo) (*gateway.Void, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, _, err := c.getReplica(in.KernelId, in.ReplicaId); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &gateway.Void{}, nil
+}
+
+// Return a list of all of the current kernels.
+func (c *FakeCluster) ListKernels(ctx context.Context, in *gateway.Void) (*gateway.ListKernelsResponse, error) {
+	if err := c.simulateDelay(ctx, maxListKernelsDelay); err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resp := &gateway.ListKernelsResponse{
+		NumKernels: int32(len(c.kernels)),
+		Kernels:    make([]*gateway.DistributedJupyterKernel, 0, len(c.kernels)),
+	}
+
+	for _, kernel := range c.kernels {
+		resp.Kernels = append(resp.Kernels, proto.Clone(kernel.kernel).(*gateway.DistributedJupyterKernel))
+	}
+
+	sort.Slice(resp.Kernels, func(i, j int) bool {
+		return resp.Kernels[i].KernelId < resp.Kernels[j].KernelId
+	})
+
+	return resp, nil
+}
+
+// StartKernel creates a kernel with the ID given by the spec, with its replicas placed by the placement policy.
+func (c *FakeCluster) StartKernel(ctx context.Context, in *gateway.KernelSpec) (*gateway.KernelConnectionInfo, error) {
+	if in.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "no kernel ID specified")
+	}
+
+	var resources *gateway.ResourceSpec
+	if in.Resource != nil {
+		resources = proto.Clone(in.Resource).(*gateway.ResourceSpec)
+	}
+
+	// Simulate the time taken to schedule the replicas and start their containers.
+	if err := c.simulateDelay(ctx, maxStartKernelDelay); err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.kernels[in.Id]; ok {
+		return nil, toStatus(fmt.Errorf("%w: %s", ErrKernelExists, in.Id))
+	}
+
+	kernel, err := c.createKernel(in.Id, fakeKernelNumReplicas, resources)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	c.logger.Info("Kernel started.", zap.String("kernel-id", in.Id), zap.String("session-id", in.Session), zap.Int("num-replicas", len(kernel.kernel.Replicas)))
+
+	// Clients connect to the kernel via its first replica.
+	host := c.hosts[kernel.kernel.Replicas[0].NodeId]
+
+	return &gateway.KernelConnectionInfo{
+		Ip:              host.ip,
+		Transport:       "tcp",
+		ControlPort:     fakeKernelBasePort,
+		ShellPort:       fakeKernelBasePort + 1,
+		StdinPort:       fakeKernelBasePort + 2,
+		HbPort:          fakeKernelBasePort + 3,
+		IopubPort:       fakeKernelBasePort + 4,
+		IosubPort:       fakeKernelBasePort + 5,
+		SignatureScheme: in.SignatureScheme,
+		Key:             in.Key,
+	}, nil
+}
+
+// KillKernel destroys a kernel, releasing the resources of its replicas. A cell that it is executing is abandoned.
+func (c *FakeCluster) KillKernel(ctx context.Context, in *gateway.KernelId) (*gateway.Void, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	kernel, err := c.getKernel(in.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	c.destroyKernel(kernel)
+	c.logger.Info("Kernel killed.", zap.String("kernel-id", in.Id))
+
+	return &gateway.Void{}, nil
+}
+
+// InterruptKernel abandons the cell that a kernel is executing, if any, releasing the GPUs committed to it.
+func (c *FakeCluster) InterruptKernel(ctx context.Context, in *gateway.KernelId) (*gateway.Void, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	kernel, err := c.getKernel(in.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if kernel.executor != nil {
+		c.releaseExecutor(kernel)
+		c.logger.Info("Kernel interrupted.", zap.String("kernel-id", in.Id))
+	}
+
+	return &gateway.Void{}, nil
+}
+
+// Generate a random UUID from the cluster's seeded source. Must be called with the mutex held (or before the cluster is started).
+func (c *FakeCluster) spoofId() string {
+	id, err := uuid.NewRandomFromReader(c.rng)
+	if err != nil {
+		panic(err)
+	}
+
+	return id.String()
+}
+
+// Convert an error returned by one of the cluster's methods to a gRPC status error.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, ErrKernelNotFound), errors.Is(err, ErrReplicaNotFound), errors.Is(err, ErrHostNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrKernelExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, ErrInsufficientResources), errors.Is(err, ErrNoMigrationTarget):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, ErrLastHost), errors.Is(err, ErrAlreadyOnHost), errors.Is(err, ErrKernelExecuting), errors.Is(err, ErrReplicaExecuting):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
 }
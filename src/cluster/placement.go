@@ -0,0 +1,54 @@
+package cluster
+
+import (
+	"math/rand"
+)
+
+// Decides which host a kernel replica is placed on, both when the kernel is created and when a replica is migrated.
+type PlacementPolicy string
+
+const (
+	PlacementLeastLoaded PlacementPolicy = "least-loaded" // Spread replicas out: pick the host with the fewest replicas.
+	PlacementBinPacking  PlacementPolicy = "bin-packing"  // Pack replicas together: pick the host with the most CPU already allocated.
+	PlacementRandom      PlacementPolicy = "random"       // Pick a host uniformly at random.
+)
+
+var PlacementPolicies = []PlacementPolicy{PlacementLeastLoaded, PlacementBinPacking, PlacementRandom}
+
+func (p PlacementPolicy) Valid() bool {
+	for _, policy := range PlacementPolicies {
+		if p == policy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Select one of the candidate hosts, all of which can accommodate the replica.
+// The candidates are sorted by ID, so that the choice depends only upon the state of the cluster (and the seed).
+func (p PlacementPolicy) selectHost(candidates []*fakeHost, rng *rand.Rand) *fakeHost {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if p == PlacementRandom {
+		return candidates[rng.Intn(len(candidates))]
+	}
+
+	best := candidates[0]
+	for _, host := range candidates[1:] {
+		switch p {
+		case PlacementBinPacking:
+			if host.allocatedCpu > best.allocatedCpu {
+				best = host
+			}
+		default:
+			if host.numReplicas < best.numReplicas || (host.numReplicas == best.numReplicas && host.allocatedCpu < best.allocatedCpu) {
+				best = host
+			}
+		}
+	}
+
+	return best
+}
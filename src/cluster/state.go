@@ -0,0 +1,579 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrKernelNotFound        = errors.New("kernel does not exist")
+	ErrKernelExists          = errors.New("kernel already exists")
+	ErrReplicaNotFound       = errors.New("replica does not exist")
+	ErrHostNotFound          = errors.New("host does not exist")
+	ErrLastHost              = errors.New("cannot remove the last remaining host")
+	ErrInsufficientResources = errors.New("insufficient resources")
+	ErrNoMigrationTarget     = errors.New("there is no host to which the replica can be migrated")
+	ErrAlreadyOnHost         = errors.New("replica is already on the target host")
+	ErrKernelExecuting       = errors.New("kernel is already executing")
+	ErrReplicaExecuting      = errors.New("replica is executing and cannot be migrated")
+	ErrHostCordoned          = errors.New("host is cordoned")
+)
+
+// The simulated hosts of a FakeCluster.
+type HostSpec struct {
+	NumHosts int
+	Capacity *gateway.ResourceSpec // Capacity of each host, in the same units as a kernel's resources.
+}
+
+// A simulated host, i.e., a Kubernetes node on which kernel replicas are scheduled.
+// Every replica reserves its kernel's CPU and memory on its host. GPUs, on the other hand, are only
+// committed to a replica while it executes a cell, so the GPUs requested by a host's replicas may exceed its capacity.
+type fakeHost struct {
+	id        string
+	ip        string
+	createdAt time.Time
+	capacity  *gateway.ResourceSpec
+
+	allocatedCpu    int32 // Reserved by the host's replicas.
+	allocatedMemory int32 // Reserved by the host's replicas.
+	committedGpus   int32 // Committed to the host's executing replicas.
+	numReplicas     int
+	cordoned        bool // If true, then no further replicas are placed on the host.
+}
+
+// Return true if the host has enough CPU and memory left for a replica that uses the given resources.
+func (h *fakeHost) fits(resources *gateway.ResourceSpec) bool {
+	return h.allocatedCpu+resources.Cpu <= h.capacity.Cpu && h.allocatedMemory+resources.Memory <= h.capacity.Memory
+}
+
+// Number of GPUs (in 1/100 GPU) that aren't committed to an executing replica.
+func (h *fakeHost) idleGpus() int32 {
+	return h.capacity.Gpu - h.committedGpus
+}
+
+// A simulated distributed kernel. Each of its replicas is hosted in its own pod on one of the hosts.
+type fakeKernel struct {
+	kernel       *gateway.DistributedJupyterKernel
+	resources    *gateway.ResourceSpec // Resources used by each replica.
+	persistentId string
+	createdAt    time.Time
+
+	executor      *gateway.JupyterKernelReplica // The replica that is currently executing a cell, if any.
+	committedGpus int32                         // GPUs committed to the executor.
+}
+
+func (k *fakeKernel) getReplica(replicaId int32) (*gateway.JupyterKernelReplica, error) {
+	for _, replica := range k.kernel.Replicas {
+		if replica.ReplicaId == replicaId {
+			return replica, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: kernel %s has no replica %d", ErrReplicaNotFound, k.kernel.KernelId, replicaId)
+}
+
+// Return true if one of the kernel's replicas is on the given host.
+func (k *fakeKernel) hostedOn(hostId string) bool {
+	for _, replica := range k.kernel.Replicas {
+		if replica.NodeId == hostId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return the kernel. Must be called with the mutex held.
+func (c *FakeCluster) getKernel(kernelId string) (*fakeKernel, error) {
+	kernel, ok := c.kernels[kernelId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKernelNotFound, kernelId)
+	}
+
+	return kernel, nil
+}
+
+// Return the specified kernel and replica. Must be called with the mutex held.
+func (c *FakeCluster) getReplica(kernelId string, replicaId int32) (*fakeKernel, *gateway.JupyterKernelReplica, error) {
+	kernel, err := c.getKernel(kernelId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replica, err := kernel.getReplica(replicaId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return kernel, replica, nil
+}
+
+// Return the hosts sorted by ID. Must be called with the mutex held.
+func (c *FakeCluster) sortedHosts() []*fakeHost {
+	hosts := make([]*fakeHost, 0, len(c.hosts))
+	for _, host := range c.hosts {
+		hosts = append(hosts, host)
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].id < hosts[j].id
+	})
+
+	return hosts
+}
+
+// Select a host for a new replica of the kernel using the placement policy. The host must not be cordoned, must have
+// room for the replica, must not already host one of the kernel's replicas, and must have at least the given number
+// of idle GPUs. Returns nil if there is no such host. Must be called with the mutex held.
+func (c *FakeCluster) selectHost(kernel *fakeKernel, gpus int32) *fakeHost {
+	candidates := make([]*fakeHost, 0, len(c.hosts))
+	for _, host := range c.sortedHosts() {
+		if !host.cordoned && host.fits(kernel.resources) && !kernel.hostedOn(host.id) && host.idleGpus() >= gpus {
+			candidates = append(candidates, host)
+		}
+	}
+
+	return c.policy.selectHost(candidates, c.rng)
+}
+
+// Place the replica on the host. Must be called with the mutex held.
+func (c *FakeCluster) placeReplica(kernel *fakeKernel, replica *gateway.JupyterKernelReplica, host *fakeHost) {
+	replica.NodeId = host.id
+	replica.PodId = fmt.Sprintf("kernel-%s-%s", kernel.kernel.KernelId, c.spoofId()[0:5])
+
+	host.allocatedCpu += kernel.resources.Cpu
+	host.allocatedMemory += kernel.resources.Memory
+	host.numReplicas++
+
+	c.notifyChanged()
+}
+
+// Release the resources of the replica on its host, if the host still exists. Must be called with the mutex held.
+func (c *FakeCluster) unplaceReplica(kernel *fakeKernel, replica *gateway.JupyterKernelReplica) {
+	if kernel.executor == replica {
+		c.releaseExecutor(kernel)
+	}
+
+	host, ok := c.hosts[replica.NodeId]
+	if !ok {
+		return
+	}
+
+	host.allocatedCpu -= kernel.resources.Cpu
+	host.allocatedMemory -= kernel.resources.Memory
+	host.numReplicas--
+
+	c.notifyChanged()
+}
+
+// Create a kernel with the given number of replicas, each on a different host chosen by the placement policy.
+// Either every replica is placed, or the kernel isn't created and ErrInsufficientResources is returned.
+func (c *FakeCluster) CreateKernel(kernelId string, numReplicas int, resources *gateway.ResourceSpec) (*gateway.DistributedJupyterKernel, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.kernels[kernelId]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrKernelExists, kernelId)
+	}
+
+	kernel, err := c.createKernel(kernelId, numReplicas, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Clone(kernel.kernel).(*gateway.DistributedJupyterKernel), nil
+}
+
+// Must be called with the mutex held.
+func (c *FakeCluster) createKernel(kernelId string, numReplicas int, resources *gateway.ResourceSpec) (*fakeKernel, error) {
+	if resources == nil {
+		resources = &gateway.ResourceSpec{}
+	}
+
+	kernel := &fakeKernel{
+		kernel: &gateway.DistributedJupyterKernel{
+			KernelId:            kernelId,
+			NumReplicas:         int32(numReplicas),
+			Status:              "idle",
+			AggregateBusyStatus: "idle",
+			Replicas:            make([]*gateway.JupyterKernelReplica, 0, numReplicas),
+		},
+		resources:    resources,
+		persistentId: c.spoofId(),
+		createdAt:    c.clock.Now(),
+	}
+
+	for i := 0; i < numReplicas; i++ {
+		host := c.selectHost(kernel, 0)
+		if host == nil {
+			// Roll back the replicas that have already been placed.
+			for _, replica := range kernel.kernel.Replicas {
+				c.unplaceReplica(kernel, replica)
+			}
+
+			return nil, fmt.Errorf("%w: cannot place replica %d of kernel %s", ErrInsufficientResources, i, kernelId)
+		}
+
+		replica := &gateway.JupyterKernelReplica{
+			ReplicaId: int32(i),
+			KernelId:  kernelId,
+		}
+		c.placeReplica(kernel, replica, host)
+		kernel.kernel.Replicas = append(kernel.kernel.Replicas, replica)
+	}
+
+	c.kernels[kernelId] = kernel
+	c.notifyChanged()
+
+	return kernel, nil
+}
+
+// Destroy the kernel, releasing the resources of its replicas.
+func (c *FakeCluster) DestroyKernel(kernelId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	kernel, err := c.getKernel(kernelId)
+	if err != nil {
+		return err
+	}
+
+	c.destroyKernel(kernel)
+
+	return nil
+}
+
+// Must be called with the mutex held.
+func (c *FakeCluster) destroyKernel(kernel *fakeKernel) {
+	for _, replica := range kernel.kernel.Replicas {
+		c.unplaceReplica(kernel, replica)
+	}
+
+	delete(c.kernels, kernel.kernel.KernelId)
+	c.notifyChanged()
+}
+
+// Move the replica to the target host, or, if the target is empty, to a host chosen by the placement policy.
+// Returns the ID of the host to which the replica was moved.
+func (c *FakeCluster) MigrateReplica(kernelId string, replicaId int32, target string) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	kernel, replica, err := c.getReplica(kernelId, replicaId)
+	if err != nil {
+		return "", err
+	}
+
+	host, err := c.migrationTarget(kernel, replica, target, 0)
+	if err != nil {
+		return "", err
+	}
+
+	source := replica.NodeId
+	c.unplaceReplica(kernel, replica)
+	c.placeReplica(kernel, replica, host)
+
+	c.logger.Debug("Migrated kernel replica.", zap.String("kernel-id", kernelId), zap.Int32("replica-id", replicaId), zap.String("source", source), zap.String("target", host.id))
+
+	return host.id, nil
+}
+
+// Validate or select the host to which the replica should be migrated. Must be called with the mutex held.
+func (c *FakeCluster) migrationTarget(kernel *fakeKernel, replica *gateway.JupyterKernelReplica, target string, gpus int32) (*fakeHost, error) {
+	if kernel.executor == replica {
+		return nil, fmt.Errorf("%w: replica %d of kernel %s", ErrReplicaExecuting, replica.ReplicaId, kernel.kernel.KernelId)
+	}
+
+	if target == "" {
+		host := c.selectHost(kernel, gpus)
+		if host == nil {
+			return nil, fmt.Errorf("%w: replica %d of kernel %s", ErrNoMigrationTarget, replica.ReplicaId, kernel.kernel.KernelId)
+		}
+
+		return host, nil
+	}
+
+	host, ok := c.hosts[target]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrHostNotFound, target)
+	}
+
+	if target == replica.NodeId {
+		return nil, fmt.Errorf("%w: replica %d of kernel %s is already on host %s", ErrAlreadyOnHost, replica.ReplicaId, kernel.kernel.KernelId, target)
+	}
+
+	if host.cordoned {
+		return nil, fmt.Errorf("%w: %s", ErrHostCordoned, target)
+	}
+
+	if kernel.hostedOn(target) || !host.fits(kernel.resources) {
+		return nil, fmt.Errorf("%w: host %s cannot accommodate replica %d of kernel %s", ErrInsufficientResources, target, replica.ReplicaId, kernel.kernel.KernelId)
+	}
+
+	return host, nil
+}
+
+// Mark the host as cordoned (or not). Replicas already on a cordoned host remain there, but no further replicas are
+// placed on it, whether new or migrated.
+func (c *FakeCluster) CordonHost(hostId string, cordoned bool) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	host, ok := c.hosts[hostId]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrHostNotFound, hostId)
+	}
+
+	if host.cordoned != cordoned {
+		host.cordoned = cordoned
+		c.notifyChanged()
+	}
+
+	c.logger.Info("Set whether host is cordoned.", zap.String("host", hostId), zap.Bool("cordoned", cordoned))
+
+	return nil
+}
+
+// Remove the host. Its replicas are moved to other hosts chosen by the placement policy, or, if they fit nowhere
+// else, removed from their kernels. Must be called with the mutex held.
+func (c *FakeCluster) removeHost(hostId string) error {
+	host, ok := c.hosts[hostId]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrHostNotFound, hostId)
+	}
+
+	if len(c.hosts) == 1 {
+		return fmt.Errorf("%w: %s", ErrLastHost, hostId)
+	}
+
+	delete(c.hosts, host.id)
+	c.notifyChanged()
+
+	kernelIds := make([]string, 0, len(c.kernels))
+	for id := range c.kernels {
+		kernelIds = append(kernelIds, id)
+	}
+	sort.Strings(kernelIds)
+
+	numMigrated, numDropped := 0, 0
+	for _, id := range kernelIds {
+		kernel := c.kernels[id]
+
+		remaining := kernel.kernel.Replicas[:0]
+		for _, replica := range kernel.kernel.Replicas {
+			if replica.NodeId != host.id {
+				remaining = append(remaining, replica)
+				continue
+			}
+
+			c.unplaceReplica(kernel, replica)
+			if target := c.selectHost(kernel, 0); target != nil {
+				c.placeReplica(kernel, replica, target)
+				remaining = append(remaining, replica)
+				numMigrated++
+			} else {
+				numDropped++
+			}
+		}
+
+		kernel.kernel.Replicas = remaining
+		kernel.kernel.NumReplicas = int32(len(remaining))
+	}
+
+	c.logger.Info("Removed host.", zap.String("host", host.id), zap.Int("replicas-migrated", numMigrated), zap.Int("replicas-dropped", numDropped))
+
+	return nil
+}
+
+// Commit the given number of GPUs (in 1/100 GPU) to one of the kernel's replicas so that it can execute a cell.
+// The replica whose host has the most idle GPUs is chosen. Returns ErrInsufficientResources if no replica's host has
+// enough idle GPUs, in which case a replica must first be migrated (see PlanMigration).
+func (c *FakeCluster) BindExecutor(kernelId string, gpus int32) (int32, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	kernel, err := c.getKernel(kernelId)
+	if err != nil {
+		return -1, err
+	}
+
+	replica, err := c.bindExecutor(kernel, gpus)
+	if err != nil {
+		return -1, err
+	}
+
+	return replica.ReplicaId, nil
+}
+
+// Must be called with the mutex held.
+func (c *FakeCluster) bindExecutor(kernel *fakeKernel, gpus int32) (*gateway.JupyterKernelReplica, error) {
+	if kernel.executor != nil {
+		return nil, fmt.Errorf("%w: %s", ErrKernelExecuting, kernel.kernel.KernelId)
+	}
+
+	var executor *gateway.JupyterKernelReplica
+	var executorHost *fakeHost
+	for _, replica := range kernel.kernel.Replicas {
+		host, ok := c.hosts[replica.NodeId]
+		if !ok || host.idleGpus() < gpus {
+			continue
+		}
+
+		if executorHost == nil || host.idleGpus() > executorHost.idleGpus() {
+			executor, executorHost = replica, host
+		}
+	}
+
+	if executor == nil {
+		return nil, fmt.Errorf("%w: no replica of kernel %s has %d idle GPU(s)", ErrInsufficientResources, kernel.kernel.KernelId, gpus)
+	}
+
+	executorHost.committedGpus += gpus
+	kernel.executor = executor
+	kernel.committedGpus = gpus
+	kernel.kernel.Status = "busy"
+	kernel.kernel.AggregateBusyStatus = "busy"
+	c.notifyChanged()
+
+	return executor, nil
+}
+
+// Release the GPUs committed to the kernel's executor, once it has finished executing.
+func (c *FakeCluster) ReleaseExecutor(kernelId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	kernel, err := c.getKernel(kernelId)
+	if err != nil {
+		return err
+	}
+
+	c.releaseExecutor(kernel)
+
+	return nil
+}
+
+// Must be called with the mutex held.
+func (c *FakeCluster) releaseExecutor(kernel *fakeKernel) {
+	if kernel.executor == nil {
+		return
+	}
+
+	if host, ok := c.hosts[kernel.executor.NodeId]; ok {
+		host.committedGpus -= kernel.committedGpus
+	}
+
+	kernel.executor = nil
+	kernel.committedGpus = 0
+	kernel.kernel.Status = "idle"
+	kernel.kernel.AggregateBusyStatus = "idle"
+	c.notifyChanged()
+}
+
+// Find a migration that would let the kernel execute a cell requiring the given number of GPUs: a replica, and a
+// host (chosen by the placement policy) with room for it and enough idle GPUs. The replica that is moved is the one
+// whose host has the fewest idle GPUs. Returns ErrNoMigrationTarget if there is no such host.
+func (c *FakeCluster) PlanMigration(kernelId string, gpus int32) (int32, string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	kernel, err := c.getKernel(kernelId)
+	if err != nil {
+		return -1, "", err
+	}
+
+	if len(kernel.kernel.Replicas) == 0 {
+		return -1, "", fmt.Errorf("%w: kernel %s has no replicas", ErrReplicaNotFound, kernelId)
+	}
+
+	var victim *gateway.JupyterKernelReplica
+	for _, replica := range kernel.kernel.Replicas {
+		if victim == nil || c.idleGpusOf(replica) < c.idleGpusOf(victim) {
+			victim = replica
+		}
+	}
+
+	host, err := c.migrationTarget(kernel, victim, "", gpus)
+	if err != nil {
+		return -1, "", err
+	}
+
+	return victim.ReplicaId, host.id, nil
+}
+
+// Return the number of idle GPUs on the replica's host. Must be called with the mutex held.
+func (c *FakeCluster) idleGpusOf(replica *gateway.JupyterKernelReplica) int32 {
+	host, ok := c.hosts[replica.NodeId]
+	if !ok {
+		return 0
+	}
+
+	return host.idleGpus()
+}
+
+// Return the simulated hosts, in the same form as the nodes of a real Kubernetes cluster.
+// AllocatedCPU and AllocatedMemory are reserved by the hosts' replicas, while AllocatedGPUs are committed to executing replicas.
+func (c *FakeCluster) Nodes() map[string]*domain.KubernetesNode {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock.Now()
+
+	nodes := make(map[string]*domain.KubernetesNode, len(c.hosts))
+	for _, host := range c.hosts {
+		nodes[host.id] = &domain.KubernetesNode{
+			NodeId:          host.id,
+			Pods:            make([]*domain.KubernetesPod, 0, host.numReplicas),
+			Age:             now.Sub(host.createdAt).Round(time.Second),
+			IP:              host.ip,
+			CapacityCPU:     float64(host.capacity.Cpu) / 100,
+			CapacityMemory:  float64(host.capacity.Memory) / 1024,
+			CapacityGPUs:    float64(host.capacity.Gpu) / 100,
+			AllocatedCPU:    float64(host.allocatedCpu) / 100,
+			AllocatedMemory: float64(host.allocatedMemory) / 1024,
+			AllocatedGPUs:   float64(host.committedGpus) / 100,
+			Unschedulable:   host.cordoned,
+			Valid:           true,
+		}
+	}
+
+	for _, kernel := range c.kernels {
+		for _, replica := range kernel.kernel.Replicas {
+			node, ok := nodes[replica.NodeId]
+			if !ok {
+				continue
+			}
+
+			node.Pods = append(node.Pods, &domain.KubernetesPod{
+				PodName:  replica.PodId,
+				PodPhase: "Running",
+				PodAge:   now.Sub(kernel.createdAt).Round(time.Second),
+				PodIP:    node.IP,
+				Valid:    true,
+			})
+		}
+	}
+
+	for _, node := range nodes {
+		sort.Slice(node.Pods, func(i, j int) bool {
+			return node.Pods[i].PodName < node.Pods[j].PodName
+		})
+	}
+
+	return nodes
+}
+
+// Return the number of kernels.
+func (c *FakeCluster) NumKernels() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.kernels)
+}
@@ -0,0 +1,129 @@
+package cluster
+
+import (
+	"sort"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Return a channel that receives a value whenever the cluster's kernels or hosts change, and a function that
+// unsubscribes. Notifications are coalesced: a burst of changes may produce a single notification.
+func (c *FakeCluster) Changes() (<-chan struct{}, func()) {
+	changes := make(chan struct{}, 1)
+
+	c.mutex.Lock()
+	c.subscribers[changes] = struct{}{}
+	c.mutex.Unlock()
+
+	return changes, func() {
+		c.mutex.Lock()
+		delete(c.subscribers, changes)
+		c.mutex.Unlock()
+	}
+}
+
+// Notify the subscribers that the cluster has changed. Must be called with the mutex held.
+func (c *FakeCluster) notifyChanged() {
+	for changes := range c.subscribers {
+		select {
+		case changes <- struct{}{}:
+		default:
+			// The subscriber already has a pending notification.
+		}
+	}
+}
+
+// Return a copy of every kernel, keyed by ID.
+func (c *FakeCluster) kernelSnapshot() map[string]*gateway.DistributedJupyterKernel {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	kernels := make(map[string]*gateway.DistributedJupyterKernel, len(c.kernels))
+	for id, kernel := range c.kernels {
+		kernels[id] = proto.Clone(kernel.kernel).(*gateway.DistributedJupyterKernel)
+	}
+
+	return kernels
+}
+
+// WatchKernels streams changes to the set of kernels. Changes are found by comparing the kernels each time the
+// cluster changes with those that were last sent, so a burst of changes to a kernel may produce a single event.
+func (c *FakeCluster) WatchKernels(in *gateway.Void, stream gateway.ClusterGateway_WatchKernelsServer) error {
+	changes, unsubscribe := c.Changes()
+	defer unsubscribe()
+
+	sent := make(map[string]*gateway.DistributedJupyterKernel)
+
+	send := func(eventType domain.ResourceEventType, kernel *gateway.DistributedJupyterKernel) error {
+		return stream.Send(&gateway.KernelEvent{Type: string(eventType), Kernel: kernel})
+	}
+
+	// Send the events for the kernels that have changed since they were last sent, in order of ID.
+	sync := func() error {
+		kernels := c.kernelSnapshot()
+
+		ids := make([]string, 0, len(kernels)+len(sent))
+		for id := range kernels {
+			ids = append(ids, id)
+		}
+		for id := range sent {
+			if _, ok := kernels[id]; !ok {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			kernel, exists := kernels[id]
+			previous, wasSent := sent[id]
+
+			var err error
+			switch {
+			case exists && !wasSent:
+				err = send(domain.ResourceAdded, kernel)
+			case exists && !proto.Equal(kernel, previous):
+				err = send(domain.ResourceUpdated, kernel)
+			case !exists:
+				err = send(domain.ResourceRemoved, previous)
+			default:
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				sent[id] = kernel
+			} else {
+				delete(sent, id)
+			}
+		}
+
+		return nil
+	}
+
+	if err := sync(); err != nil {
+		return err
+	}
+
+	if err := send(domain.ResourceSynced, nil); err != nil {
+		return err
+	}
+
+	c.logger.Debug("Kernel watcher subscribed.", zap.Int("num-kernels", len(sent)))
+
+	for {
+		select {
+		case <-changes:
+			if err := sync(); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
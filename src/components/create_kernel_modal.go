@@ -0,0 +1,171 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+const (
+	// Resources requested by each replica of a new kernel, unless the user enters something else.
+	defaultKernelCpus   = 1.0    // In cores.
+	defaultKernelMemory = 1024.0 // In MB.
+	defaultKernelGpus   = 1.0
+)
+
+// Modal for creating a kernel from one of the available kernel specs.
+type CreateKernelModal struct {
+	app.Compo
+
+	ID string // HTML ID of the modal; must be unique across the page
+
+	OnCancel func(dirty bool, clear chan struct{})           // Handler for when we cancel the create operation.
+	OnSubmit func(*domain.KernelSpec, *gateway.ResourceSpec) // Handler to call with the chosen kernel spec and resources.
+
+	KernelSpecs []*domain.KernelSpec
+
+	selectedSpec string  // Name of the selected kernel spec.
+	cpus         float64 // In cores.
+	memory       float64 // In MB.
+	gpus         float64
+
+	dirty bool
+}
+
+func (c *CreateKernelModal) OnInit() {
+	c.clear()
+}
+
+// Return the selected kernel spec, or nil if there are none.
+func (c *CreateKernelModal) kernelSpec() *domain.KernelSpec {
+	for _, spec := range c.KernelSpecs {
+		if spec.Name == c.selectedSpec {
+			return spec
+		}
+	}
+
+	if len(c.KernelSpecs) > 0 {
+		return c.KernelSpecs[0]
+	}
+
+	return nil
+}
+
+// Return the resources entered by the user, in the units used by gateway.ResourceSpec.
+func (c *CreateKernelModal) resources() *gateway.ResourceSpec {
+	return &gateway.ResourceSpec{
+		Cpu:    int32(c.cpus * 100),
+		Memory: int32(c.memory),
+		Gpu:    int32(c.gpus * 100),
+	}
+}
+
+// Return an input for one of the resources, which updates the given field as the user types.
+func (c *CreateKernelModal) resourceInput(id string, label string, step float64, field *float64) app.UI {
+	return app.Div().Class("pf-v5-c-form__group").Body(
+		app.Div().Class("pf-v5-c-form__group-label").Body(
+			app.Label().Class("pf-v5-c-form__label").For(id).Body(
+				app.Span().Class("pf-v5-c-form__label-text").Text(label),
+			),
+		),
+		app.Div().Class("pf-v5-c-form__group-control").Body(
+			app.Input().
+				Class("pf-v5-c-form-control").
+				Type("number").
+				ID(id).
+				Min(0).
+				Step(step).
+				Value(strconv.FormatFloat(*field, 'f', -1, 64)).
+				OnInput(func(ctx app.Context, e app.Event) {
+					value, err := strconv.ParseFloat(ctx.JSSrc().Get("value").String(), 64)
+					if err != nil || value < 0 {
+						return
+					}
+
+					*field = value
+					c.dirty = true
+				}),
+		),
+	)
+}
+
+func (c *CreateKernelModal) Render() app.UI {
+	form_id := fmt.Sprintf("%s-form", c.ID)
+	spec := c.kernelSpec()
+
+	return &Modal{
+		ID:    c.ID,
+		Title: "Create Kernel",
+		Body: []app.UI{
+			app.Form().
+				Class("pf-v5-c-form").
+				ID(form_id).
+				OnSubmit(func(ctx app.Context, e app.Event) {
+					e.PreventDefault()
+
+					if spec := c.kernelSpec(); spec != nil {
+						c.OnSubmit(spec, c.resources())
+					}
+
+					c.clear()
+				}).Body(
+				app.Div().Class("pf-v5-c-form__group").Body(
+					app.Div().Class("pf-v5-c-form__group-label").Body(
+						app.Label().Class("pf-v5-c-form__label").For(fmt.Sprintf("%s-spec", c.ID)).Body(
+							app.Span().Class("pf-v5-c-form__label-text").Text("Kernel Spec"),
+						),
+					),
+					app.Div().Class("pf-v5-c-form__group-control").Body(
+						app.Select().
+							Class("pf-v5-c-form-control").
+							ID(fmt.Sprintf("%s-spec", c.ID)).
+							OnChange(func(ctx app.Context, e app.Event) {
+								c.selectedSpec = ctx.JSSrc().Get("value").String()
+								c.dirty = true
+							}).
+							Body(
+								app.Range(c.KernelSpecs).Slice(func(i int) app.UI {
+									return app.Option().
+										Value(c.KernelSpecs[i].Name).
+										Selected(spec != nil && c.KernelSpecs[i].Name == spec.Name).
+										Text(c.KernelSpecs[i].DisplayName)
+								}),
+							),
+					),
+				),
+				c.resourceInput(fmt.Sprintf("%s-cpus", c.ID), "CPUs (cores)", 0.01, &c.cpus),
+				c.resourceInput(fmt.Sprintf("%s-memory", c.ID), "Memory (MB)", 1, &c.memory),
+				c.resourceInput(fmt.Sprintf("%s-gpus", c.ID), "GPUs", 0.01, &c.gpus),
+			),
+		},
+		Footer: []app.UI{
+			app.Button().
+				Class("pf-v5-c-button pf-m-primary").
+				Type("submit").
+				Form(form_id).
+				Disabled(spec == nil).
+				Text("Create"),
+			app.Button().
+				Class("pf-v5-c-button pf-m-link").
+				Type("button").
+				Text("Cancel").
+				OnClick(func(ctx app.Context, e app.Event) {
+					handleCancel(c.clear, c.dirty, c.OnCancel)
+				}),
+		},
+		OnClose: func() {
+			handleCancel(c.clear, c.dirty, c.OnCancel)
+		},
+	}
+}
+
+func (c *CreateKernelModal) clear() {
+	c.selectedSpec = ""
+	c.cpus = defaultKernelCpus
+	c.memory = defaultKernelMemory
+	c.gpus = defaultKernelGpus
+	c.dirty = false
+}
@@ -0,0 +1,197 @@
+package components
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+func getIconForDrainStepState(state domain.DrainStepState) string {
+	switch state {
+	case domain.DrainStepPending:
+		return "fas fa-clock"
+	case domain.DrainStepRunning:
+		return "fas fa-spinner fa-pulse fa-spin"
+	case domain.DrainStepSucceeded:
+		return "fas fa-check-circle"
+	case domain.DrainStepFailed:
+		return "fas fa-times-circle"
+	case domain.DrainStepSkipped:
+		return "fas fa-minus-circle"
+	default:
+		app.Logf("[WARNING] Unknown drain step state received: \"%s\"\n", state)
+		return ""
+	}
+}
+
+// Modal for draining a node: cordoning it, migrating each of its replicas to other nodes, and optionally removing its
+// host from the cluster. The status of each step is displayed as the drain progresses.
+type DrainNodeModal struct {
+	app.Compo
+
+	ID     string // HTML ID of the modal; must be unique across the page
+	NodeId string // The node to drain.
+
+	Drainer    domain.NodeDrainer
+	OnFinished func(string, *domain.DrainStatus, error) // Handler to call once the drain of the node has completed or failed.
+	OnClose    func()                                   // Handler to call when closing the modal
+
+	removeHost bool
+	status     *domain.DrainStatus
+	draining   bool
+}
+
+func (c *DrainNodeModal) drain(ctx app.Context) {
+	nodeId, removeHost := c.NodeId, c.removeHost
+
+	c.status = nil
+	c.draining = true
+
+	ctx.Async(func() {
+		status, err := c.Drainer.DrainNode(nodeId, removeHost, func(status *domain.DrainStatus) {
+			ctx.Dispatch(func(ctx app.Context) {
+				c.status = status
+			})
+		})
+
+		ctx.Dispatch(func(ctx app.Context) {
+			if status != nil {
+				c.status = status
+			}
+			c.draining = false
+		})
+
+		if c.OnFinished != nil {
+			c.OnFinished(nodeId, status, err)
+		}
+	})
+}
+
+func (c *DrainNodeModal) stepRow(step *domain.DrainStep) app.UI {
+	duration := "-"
+	if step.StartedAt != nil && step.FinishedAt != nil {
+		duration = step.FinishedAt.Sub(*step.StartedAt).Round(time.Millisecond).String()
+	}
+
+	state := app.Span().Text(string(step.State))
+	if step.Error != "" {
+		state = app.Span().Title(step.Error).Style("color", "#c9190b").Text(string(step.State))
+	}
+
+	return app.Tr().Class("pf-v5-c-table__tr").Body(
+		app.Td().Class("pf-v5-c-table__td").DataSet("label", "Step").Text(step.Description),
+		app.Td().Class("pf-v5-c-table__td").DataSet("label", "State").Body(
+			app.Div().Class("pf-v5-l-flex pf-m-space-items-xs").Body(
+				app.I().Class(getIconForDrainStepState(step.State)).Aria("hidden", true),
+				state,
+			),
+		),
+		app.Td().Class("pf-v5-c-table__td").DataSet("label", "Duration").Text(duration),
+	)
+}
+
+// Return the status line displayed beneath the steps.
+func (c *DrainNodeModal) summary() app.UI {
+	switch {
+	case c.status == nil && c.draining:
+		return app.P().Body(
+			app.I().Class("fas fa-spinner fa-pulse").Style("margin-right", "8px"),
+			app.Text("Starting drain..."),
+		)
+	case c.status == nil:
+		return app.P().Text(fmt.Sprintf("Node %s will be cordoned, and then each of its replicas will be migrated to another node. The drain stops at the first failure.", c.NodeId))
+	case c.status.State == domain.DrainFailed:
+		return app.P().Style("color", "#c9190b").Text(fmt.Sprintf("Could not drain node %s: %s", c.NodeId, c.status.Error))
+	case c.status.State == domain.DrainCompleted:
+		return app.P().Style("color", "#3e8635").Text(fmt.Sprintf("Drained node %s in %v.", c.NodeId, c.status.FinishedAt.Sub(c.status.StartedAt).Round(time.Millisecond)))
+	default:
+		return app.P().Body(
+			app.I().Class("fas fa-spinner fa-pulse").Style("margin-right", "8px"),
+			app.Text(fmt.Sprintf("Draining node %s...", c.NodeId)),
+		)
+	}
+}
+
+func (c *DrainNodeModal) Render() app.UI {
+	form_id := fmt.Sprintf("%s-form", c.ID)
+
+	var steps []*domain.DrainStep
+	if c.status != nil {
+		steps = c.status.Steps
+	}
+
+	return &Modal{
+		ID:           c.ID,
+		Title:        fmt.Sprintf("Drain Node %s", c.NodeId),
+		DisableFocus: true,
+		Body: []app.UI{
+			app.Form().
+				Class("pf-v5-c-form").
+				ID(form_id).
+				OnSubmit(func(ctx app.Context, e app.Event) {
+					e.PreventDefault()
+
+					if !c.draining {
+						c.drain(ctx)
+					}
+				}).Body(
+				app.Div().Class("pf-v5-c-form__group").Body(
+					app.Div().Class("pf-v5-c-form__group-control").Body(
+						app.Div().Class("pf-v5-c-check").Body(
+							app.Input().
+								Class("pf-v5-c-check__input").
+								Type("checkbox").
+								ID(fmt.Sprintf("%s-remove-host", c.ID)).
+								Checked(c.removeHost).
+								Disabled(c.draining).
+								OnChange(func(ctx app.Context, e app.Event) {
+									c.removeHost = ctx.JSSrc().Get("checked").Bool()
+								}),
+							app.Label().
+								Class("pf-v5-c-check__label").
+								For(fmt.Sprintf("%s-remove-host", c.ID)).
+								Text("Remove the host from the cluster once the node has been drained"),
+						),
+					),
+				),
+			),
+			app.If(len(steps) > 0,
+				app.Table().Class("pf-v5-c-table pf-m-compact pf-m-grid-md pf-u-mt-md").Aria("label", "Drain steps").Body(
+					app.THead().Class("pf-v5-c-table__thead").Body(
+						app.Tr().Class("pf-v5-c-table__tr").Body(
+							app.Th().Class("pf-v5-c-table__th").Scope("col").Text("Step"),
+							app.Th().Class("pf-v5-c-table__th").Scope("col").Text("State"),
+							app.Th().Class("pf-v5-c-table__th").Scope("col").Text("Duration"),
+						),
+					),
+					app.TBody().Class("pf-v5-c-table__tbody").Body(
+						app.Range(steps).Slice(func(i int) app.UI {
+							return c.stepRow(steps[i])
+						}),
+					),
+				),
+			),
+			c.summary(),
+		},
+		Footer: []app.UI{
+			app.Button().
+				Class("pf-v5-c-button pf-m-danger").
+				Type("submit").
+				Form(form_id).
+				Disabled(c.draining).
+				Text("Drain"),
+			app.Button().
+				Class("pf-v5-c-button pf-m-link").
+				Type("button").
+				Text("Close").
+				OnClick(func(ctx app.Context, e app.Event) {
+					c.OnClose()
+				}),
+		},
+		OnClose: func() {
+			c.OnClose()
+		},
+	}
+}
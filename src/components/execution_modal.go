@@ -0,0 +1,151 @@
+package components
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+// Modal for executing code on a kernel, or on a single replica of a kernel. The outputs are displayed as they are produced.
+type ExecutionModal struct {
+	app.Compo
+
+	ID       string // HTML ID of the modal; must be unique across the page
+	KernelId string // The kernel on which the code is executed.
+	Replica  *int32 // If set, then the code is executed only by this replica of the kernel.
+
+	Executor domain.CodeExecutor
+	OnClose  func() // Handler to call when closing the modal
+
+	code      string
+	outputs   []*domain.ExecutionOutput
+	reply     *domain.ExecuteReply
+	err       error
+	executing bool
+}
+
+// Return a description of what the code is executed on.
+func (c *ExecutionModal) target() string {
+	return (&domain.ExecuteRequest{KernelId: c.KernelId, ReplicaId: c.Replica}).String()
+}
+
+func (c *ExecutionModal) execute(ctx app.Context) {
+	req := &domain.ExecuteRequest{KernelId: c.KernelId, ReplicaId: c.Replica, Code: c.code}
+
+	c.outputs = nil
+	c.reply = nil
+	c.err = nil
+	c.executing = true
+
+	ctx.Async(func() {
+		reply, err := c.Executor.Execute(context.Background(), req, func(output *domain.ExecutionOutput) {
+			ctx.Dispatch(func(ctx app.Context) {
+				c.outputs = append(c.outputs, output)
+			})
+		})
+
+		ctx.Dispatch(func(ctx app.Context) {
+			c.reply = reply
+			c.err = err
+			c.executing = false
+		})
+	})
+}
+
+// Return the status line displayed beneath the outputs.
+func (c *ExecutionModal) status() app.UI {
+	switch {
+	case c.executing:
+		return app.P().Body(
+			app.I().Class("fas fa-spinner fa-pulse").Style("margin-right", "8px"),
+			app.Text("Executing..."),
+		)
+	case c.err != nil:
+		return app.P().Style("color", "#c9190b").Text(fmt.Sprintf("Could not execute the code: %v", c.err))
+	case c.reply != nil && c.reply.Status == "ok":
+		return app.P().Style("color", "#3e8635").Text(fmt.Sprintf("Execution completed: %s", c.reply))
+	case c.reply != nil:
+		return app.P().Style("color", "#c9190b").Text(fmt.Sprintf("Execution failed: %s", c.reply))
+	default:
+		return app.P()
+	}
+}
+
+func (c *ExecutionModal) Render() app.UI {
+	form_id := fmt.Sprintf("%s-form", c.ID)
+
+	return &Modal{
+		ID:           c.ID,
+		Title:        fmt.Sprintf("Execute Code on %s", c.target()),
+		DisableFocus: true,
+		Body: []app.UI{
+			app.Form().
+				Class("pf-v5-c-form").
+				ID(form_id).
+				OnSubmit(func(ctx app.Context, e app.Event) {
+					e.PreventDefault()
+
+					if !c.executing {
+						c.execute(ctx)
+					}
+				}).Body(
+				app.Div().Class("pf-v5-c-form__group").Body(
+					app.Div().Class("pf-v5-c-form__group-control").Body(
+						app.Textarea().
+							Class("pf-v5-c-form-control").
+							ID(fmt.Sprintf("%s-code", c.ID)).
+							Rows(8).
+							Style("font-family", "monospace").
+							Placeholder("print(\"Hello, world!\")").
+							AutoFocus(true).
+							Text(c.code).
+							OnInput(func(ctx app.Context, e app.Event) {
+								c.code = ctx.JSSrc().Get("value").String()
+							}),
+					),
+				),
+			),
+			app.Div().
+				Class("pf-v5-c-code-block pf-u-mt-md").
+				Body(
+					app.Div().Class("pf-v5-c-code-block__content").Body(
+						app.Range(c.outputs).Slice(func(i int) app.UI {
+							output := c.outputs[i]
+
+							color := "inherit"
+							if output.Type == domain.ExecutionOutputError || output.Name == "stderr" {
+								color = "#c9190b"
+							}
+
+							return app.Pre().
+								Class("pf-v5-c-code-block__pre").
+								Style("color", color).
+								Style("margin", "0").
+								Text(output.Text)
+						}),
+					),
+				),
+			c.status(),
+		},
+		Footer: []app.UI{
+			app.Button().
+				Class("pf-v5-c-button pf-m-primary").
+				Type("submit").
+				Form(form_id).
+				Disabled(c.executing).
+				Text("Execute"),
+			app.Button().
+				Class("pf-v5-c-button pf-m-link").
+				Type("button").
+				Text("Close").
+				OnClick(func(ctx app.Context, e app.Event) {
+					c.OnClose()
+				}),
+		},
+		OnClose: func() {
+			c.OnClose()
+		},
+	}
+}
@@ -32,9 +32,10 @@ type KernelList struct {
 	onTerminateSelectedKernelsButtonClicked TerminateSelectedKernelsButtonClickedHandler // Handler for clicking the 'Terminate Selected Kernels' button.
 	onMigrateButtonClicked                  MigrateButtonClickedHandler                  // Handler for clicking the 'Migrate' button for a specific replica of a specific kernel.
 	onExecuteReplicaButtonClicked           ExecuteReplicaButtonClickedHandler           // Handler for clicking the 'Execute' button for a specific replica of a specific kernel.
+	onInterruptKernelButtonClicked          InterruptKernelButtonClickedHandler          // Handler for clicking the 'Interrupt' button for a specific kernel.
 }
 
-func NewKernelList(kernelProvider domain.KernelProvider, errorHandler domain.ErrorHandler, migrateButtonClickedHandler MigrateButtonClickedHandler, executeKernelButtonClickedHandler ExecuteKernelButtonClickedHandler, executeReplicaButtonClickedHandler ExecuteReplicaButtonClickedHandler, createKernelButtonClickedHandler CreateKernelButtonClickedHandler, terminateSelectedKernelsButtonClickedHandler TerminateSelectedKernelsButtonClickedHandler, terminateSpecificKernelButtonClickedHandler TerminateSpecificKernelButtonClickedHandler) *KernelList {
+func NewKernelList(kernelProvider domain.KernelProvider, errorHandler domain.ErrorHandler, migrateButtonClickedHandler MigrateButtonClickedHandler, executeKernelButtonClickedHandler ExecuteKernelButtonClickedHandler, executeReplicaButtonClickedHandler ExecuteReplicaButtonClickedHandler, createKernelButtonClickedHandler CreateKernelButtonClickedHandler, terminateSelectedKernelsButtonClickedHandler TerminateSelectedKernelsButtonClickedHandler, terminateSpecificKernelButtonClickedHandler TerminateSpecificKernelButtonClickedHandler, interruptKernelButtonClickedHandler InterruptKernelButtonClickedHandler) *KernelList {
 	kl := &KernelList{
 		id:                                      fmt.Sprintf("KernelList-%s", uuid.New().String()[0:26]),
 		kernelProvider:                          kernelProvider,
@@ -45,6 +46,7 @@ func NewKernelList(kernelProvider domain.KernelProvider, errorHandler domain.Err
 		onTerminateSelectedKernelsButtonClicked: terminateSelectedKernelsButtonClickedHandler,
 		onTerminateSpecificKernelButtonClicked:  terminateSpecificKernelButtonClickedHandler,
 		onExecuteKernelButtonClicked:            executeKernelButtonClickedHandler,
+		onInterruptKernelButtonClicked:          interruptKernelButtonClickedHandler,
 		selected:                                make(map[string]bool),
 	}
 
@@ -111,27 +113,54 @@ func (kl *KernelList) recreateState(kernels []*gateway.DistributedJupyterKernel)
 	kl.numSelected = numSelected
 }
 
-func (kl *KernelList) handleKernelsRefresh(kernels []*gateway.DistributedJupyterKernel) bool {
+// Apply the changes to the kernels since the last refresh, rather than recreating the whole state.
+func (kl *KernelList) handleKernelChanges(changes []*domain.ResourceChange[*gateway.DistributedJupyterKernel]) bool {
 	if !kl.Mounted() {
-		app.Logf("KernelList %s (%p) is not mounted; ignoring refresh.", kl.id, kl)
+		app.Logf("KernelList %s (%p) is not mounted; ignoring changes.", kl.id, kl)
 		return false
 	}
 
-	app.Logf("KernelList %s (%p) is handling a kernel refresh. Number of kernels: %d.", kl.id, kl, len(kernels))
-	kl.recreateState(kernels)
+	app.Logf("KernelList %s (%p) is handling %d kernel change(s).", kl.id, kl, len(changes))
+
+	for _, change := range changes {
+		switch change.Type {
+		case domain.ResourceAdded:
+			kl.kernels[change.Id] = change.Current
+			kl.selected[change.Id] = false
+			kl.expanded[change.Id] = false
+		case domain.ResourceUpdated:
+			kl.kernels[change.Id] = change.Current
+
+			if change.Changed("Status") {
+				app.Logf("Kernel %s is now %s (was %s).", change.Id, change.Current.Status, change.Previous.Status)
+			}
+		case domain.ResourceRemoved:
+			if kl.selected[change.Id] {
+				kl.numSelected--
+			}
+
+			delete(kl.kernels, change.Id)
+			delete(kl.selected, change.Id)
+			delete(kl.expanded, change.Id)
+		}
+	}
+
 	kl.Update()
 
 	return true
 }
 
 func (kl *KernelList) OnMount(ctx app.Context) {
-	kl.kernelProvider.SubscribeToRefreshes(kl.id, kl.handleKernelsRefresh)
+	kl.kernelProvider.SubscribeToChanges(kl.id, kl.handleKernelChanges)
+
+	// Catch up with any changes that occurred between creating the list and subscribing to them.
+	kl.recreateState(kl.kernelProvider.Resources())
 
 	go kl.kernelProvider.RefreshResources()
 }
 
 func (kl *KernelList) OnDismount(ctx app.Context) {
-	kl.kernelProvider.UnsubscribeFromRefreshes(kl.id)
+	kl.kernelProvider.UnsubscribeFromChanges(kl.id)
 }
 
 func (kl *KernelList) Render() app.UI {
@@ -179,7 +208,7 @@ func (kl *KernelList) Render() app.UI {
 						OnClick(func(ctx app.Context, e app.Event) {
 							e.StopImmediatePropagation()
 
-							var selected []*gateway.DistributedJupyterKernel = make([]*gateway.DistributedJupyterKernel, kl.numSelected)
+							var selected []*gateway.DistributedJupyterKernel = make([]*gateway.DistributedJupyterKernel, 0, kl.numSelected)
 
 							for kernelId, isSelected := range kl.selected {
 								if isSelected {
@@ -285,6 +314,16 @@ func (kl *KernelList) Render() app.UI {
 																OnClick(func(ctx app.Context, e app.Event) {
 																	go kl.onExecuteKernelButtonClicked(ctx, e, kernels[kernel_id])
 																}),
+															app.Button().
+																Class("pf-v5-c-button pf-m-secondary").
+																Type("button").
+																Text("Interrupt").
+																Style("font-size", "16px").
+																Style("margin-right", "16px").
+																Disabled(kernels[kernel_id].GetStatus() != "busy").
+																OnClick(func(ctx app.Context, e app.Event) {
+																	go kl.onInterruptKernelButtonClicked(ctx, e, kernels[kernel_id])
+																}),
 															app.Button().
 																Class("pf-v5-c-button pf-m-secondary pf-m-danger").
 																Type("button").
@@ -2,7 +2,7 @@ package components
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,8 +13,7 @@ import (
 	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/driver"
-	"nhooyr.io/websocket"
-	"nhooyr.io/websocket/wsjson"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/providers"
 )
 
 var (
@@ -29,6 +28,9 @@ type ExecuteKernelButtonClickedHandler func(app.Context, app.Event, *gateway.Dis
 type CreateKernelButtonClickedHandler func(app.Context, app.Event)
 type TerminateSpecificKernelButtonClickedHandler func(app.Context, app.Event, *gateway.DistributedJupyterKernel)
 type TerminateSelectedKernelsButtonClickedHandler func(app.Context, app.Event, []*gateway.DistributedJupyterKernel)
+type InterruptKernelButtonClickedHandler func(app.Context, app.Event, *gateway.DistributedJupyterKernel)
+type CordonNodeButtonClickedHandler func(app.Context, app.Event, *domain.KubernetesNode)
+type DrainNodeButtonClickedHandler func(app.Context, app.Event, *domain.KubernetesNode)
 
 type MainWindow struct {
 	app.Compo
@@ -43,12 +45,18 @@ type MainWindow struct {
 	err             error                 // Current operational error.
 	updateAvailable bool                  // Field that reports whether an app update is available. False by default.
 
+	connectionEvent *domain.GatewayConnectionEvent // The most recent change to the state of the connection to the Cluster Gateway.
+
 	replicaToMigrate        *gateway.JupyterKernelReplica     // The replica for which the user has clicked the 'Migrate' button.
 	replicaToExecute        *gateway.JupyterKernelReplica     // The replica for which the user has clicked the 'Execute' button.
 	kernelToExecute         *gateway.DistributedJupyterKernel // The kernel for which the user has clicked the 'Execute' button.
+	nodeToDrain             string                            // ID of the node for which the user has clicked the 'Drain' button.
 	migarateModalOpen       bool                              // Indicates whether the MigrateModal should be open. If it is true, then the modal will be displayed.
 	executeReplicaModalOpen bool                              // Indicates whether the ExecuteReplicaModal should be open. If it is true, then the modal will be displayed.
 	executeKernelModalOpen  bool                              // Indicates whether the ExecuteReplicaModal should be open. If it is true, then the modal will be displayed.
+	createKernelModalOpen   bool                              // Indicates whether the CreateKernelModal should be open. If it is true, then the modal will be displayed.
+	drainNodeModalOpen      bool                              // Indicates whether the DrainNodeModal should be open. If it is true, then the modal will be displayed.
+	kernelSpecs             []*domain.KernelSpec              // The kernel specs from which the user may choose when creating a kernel.
 }
 
 func NewMainWindow(gatewayAddress string) *MainWindow {
@@ -64,36 +72,11 @@ func NewMainWindow(gatewayAddress string) *MainWindow {
 
 // Issue a websockets request to the backend to retrieve the configuration.
 func (w *MainWindow) getConfigFromBackend(ctx app.Context) {
-	ctxConnect, cancelConnect := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancelConnect()
-	c, _, err := websocket.Dial(ctxConnect, "ws://localhost:8000"+domain.SYSTEM_CONFIG_ENDPOINT, nil)
-	if err != nil {
-		app.Logf("Failed to connect to backend while trying to get config: %v", err)
-		w.HandleError(err, "Failed to fetch config from backend. Could not connect to the backend.")
-		return
-	}
-	defer c.CloseNow()
-
-	msg := map[string]interface{}{
-		"op": "request-config",
-	}
-
-	ctxWrite, cancelWrite := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancelWrite()
-	err = wsjson.Write(ctxWrite, c, msg)
-	if err != nil {
-		ctx.Dispatch(func(ctx app.Context) {
-			w.HandleError(err, "Failed to fetch list of active nodes from the Cluster Gateway.")
-		})
-		return
-	}
+	ctxRequest, cancelRequest := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancelRequest()
 
-	ctxRead, cancelRead := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancelRead()
 	var opts config.Configuration
-	_, response, err := c.Read(ctxRead)
-	c.Close(websocket.StatusNormalClosure, "")
-	if err != nil {
+	if err := providers.RequestBackend(ctxRequest, domain.SYSTEM_CONFIG_ENDPOINT, domain.ConfigOpRequest, nil, &opts); err != nil {
 		app.Logf("Error encountered while reading configuration from backend: %v", err)
 
 		ctx.Dispatch(func(ctx app.Context) {
@@ -102,22 +85,6 @@ func (w *MainWindow) getConfigFromBackend(ctx app.Context) {
 		return
 	}
 
-	json.Unmarshal(response, &opts)
-	if !opts.Valid {
-		var errMessage domain.ErrorMessage
-		json.Unmarshal(response, &errMessage)
-
-		if errMessage.Valid {
-			ctx.Dispatch(func(ctx app.Context) {
-				app.Logf("Error encountered while reading configuration from backend: %s", errMessage.ErrorMessage)
-				w.HandleError(err, "Failed to read configuration from backend.")
-			})
-		} else {
-			// We didn't receivea valid config, but we also didn't receive a valid error message.
-			panic("Unknown error while receiving config from backend.")
-		}
-	}
-
 	ctx.Dispatch(func(ctx app.Context) {
 		w.onConfigReceived(&opts)
 	})
@@ -132,7 +99,20 @@ func (w *MainWindow) onConfigReceived(configuration *config.Configuration) {
 
 	driver := driver.NewWorkloadDriver(w, configuration)
 	w.WorkloadDriver = driver
+
+	// If the connection to the Cluster Gateway is lost, then we return to Phase 2 until it has been re-established.
+	driver.SubscribeToConnectionEvents("main-window", w.onConnectionEvent)
+
+	// The workload run is managed by the backend rather than by the Cluster Gateway, so we can begin polling for it immediately.
+	driver.WorkloadRunProvider().Start("")
 	w.ConfigurationReceived = true
+
+	// When spoofing the cluster, the backend serves a fake Cluster Gateway at the configured address, so we connect to it straight away.
+	if configuration.SpoofCluster {
+		w.GatewayAddress = configuration.GatewayAddress
+		w.connectButtonHandler()
+	}
+
 	w.Update()
 }
 
@@ -143,6 +123,9 @@ func (w *MainWindow) OnMount(ctx app.Context) {
 		return
 	}
 
+	// The backend is reached at the origin from which the page was loaded, so the dashboard works behind ingresses and port-forwards.
+	providers.ConfigureBackend(providers.BackendOptionsFromPage(app.Window().URL()))
+
 	app.Log("Retrieving configuration from server.")
 
 	// Get the configuration. The UI will be updated once we receive the configuration,
@@ -204,16 +187,104 @@ func (w *MainWindow) connectButtonHandler() {
 	}()
 }
 
+// Called whenever the state of the connection to the Cluster Gateway changes. Lets the user know if the connection was lost or re-established.
+func (w *MainWindow) onConnectionEvent(event *domain.GatewayConnectionEvent) bool {
+	previous := w.connectionEvent
+	w.connectionEvent = event
+
+	switch {
+	case event.State == domain.GatewayReconnecting && event.Attempt == 0:
+		w.addAlert(&Alert{
+			ID:               uuid.New().String(),
+			Name:             "Connection Lost",
+			Class:            "pf-v5-c-alert pf-m-warning",
+			IconWrapperClass: "pf-v5-c-alert__icon",
+			IconClass:        "fas fa-fw fa-exclamation-triangle",
+			Title:            "Connection Lost",
+			Description:      fmt.Sprintf("Lost the connection to the Cluster Gateway at %s. Reconnecting automatically.", event.Address),
+			OnClose:          w.onAlertClosed,
+			HasButton:        false,
+		})
+	case event.State == domain.GatewayConnected && previous != nil && previous.State == domain.GatewayReconnecting:
+		w.addSuccessAlert("Reconnected", fmt.Sprintf("Re-established the connection to the Cluster Gateway at %s.", event.Address))
+	default:
+		w.Update()
+	}
+
+	return true
+}
+
 func (w *MainWindow) onCreateKernelButtonClicked(ctx app.Context, e app.Event) {
 	app.Log("'Create Kernel' button clicked.")
+
+	w.kernelSpecs = w.WorkloadDriver.KernelSpecProvider().Resources()
+	w.createKernelModalOpen = true
+	w.Update()
+}
+
+func (w *MainWindow) onCreateKernelSubmit(spec *domain.KernelSpec, resources *gateway.ResourceSpec) {
+	w.createKernelModalOpen = false
+	w.Update()
+
+	go func() {
+		kernelId, _, err := w.WorkloadDriver.CreateKernel(spec, resources)
+		if err != nil {
+			w.HandleError(err, fmt.Sprintf("Could not create a kernel from kernel spec \"%s\".", spec.DisplayName))
+			return
+		}
+
+		w.addSuccessAlert("Kernel Created", fmt.Sprintf("Created kernel %s from kernel spec \"%s\".", kernelId, spec.DisplayName))
+	}()
 }
 
 func (w *MainWindow) onTerminateSelectedKernelsButtonClicked(ctx app.Context, e app.Event, selectedKernels []*gateway.DistributedJupyterKernel) {
 	app.Logf("'Terminate Selected Kernels' button clicked. NumSelected: %d.", len(selectedKernels))
+
+	kernelIds := make([]string, 0, len(selectedKernels))
+	for _, kernel := range selectedKernels {
+		kernelIds = append(kernelIds, kernel.KernelId)
+	}
+
+	w.reportKernelOperationResults("terminate", w.WorkloadDriver.TerminateKernels(kernelIds))
 }
 
 func (w *MainWindow) onTerminateSpecificKernelButtonClicked(ctx app.Context, e app.Event, selectedKernel *gateway.DistributedJupyterKernel) {
 	app.Logf("'Terminate Specific Kernel' button clicked. Kernel to terminate: %s.", selectedKernel.KernelId)
+
+	if err := w.WorkloadDriver.TerminateKernel(selectedKernel.KernelId); err != nil {
+		w.HandleError(err, fmt.Sprintf("Could not terminate kernel %s.", selectedKernel.KernelId))
+		return
+	}
+
+	w.addSuccessAlert("Kernel Terminated", fmt.Sprintf("Terminated kernel %s.", selectedKernel.KernelId))
+}
+
+func (w *MainWindow) onInterruptKernelButtonClicked(ctx app.Context, e app.Event, kernel *gateway.DistributedJupyterKernel) {
+	app.Logf("'Interrupt' button clicked. Kernel to interrupt: %s.", kernel.KernelId)
+
+	if err := w.WorkloadDriver.InterruptKernel(kernel.KernelId); err != nil {
+		w.HandleError(err, fmt.Sprintf("Could not interrupt kernel %s.", kernel.KernelId))
+		return
+	}
+
+	w.addSuccessAlert("Kernel Interrupted", fmt.Sprintf("Interrupted kernel %s.", kernel.KernelId))
+}
+
+// Tell the user how an operation on several kernels went: an alert if it succeeded for every kernel, or else an error naming the kernels for which it failed.
+func (w *MainWindow) reportKernelOperationResults(operation string, results []*domain.KernelOperationResult) {
+	errs := make([]error, 0)
+	for _, result := range results {
+		if !result.Succeeded() {
+			errs = append(errs, fmt.Errorf("kernel %s: %w", result.KernelId, result.Err))
+		}
+	}
+
+	if len(errs) > 0 {
+		w.HandleError(errors.Join(errs...), fmt.Sprintf("Could not %s %d of %d kernel(s).", operation, len(errs), len(results)))
+		return
+	}
+
+	w.addSuccessAlert("Operation Succeeded", fmt.Sprintf("Successfully performed \"%s\" on %d kernel(s).", operation, len(results)))
 }
 
 func (w *MainWindow) onMigrateButtonClicked(ctx app.Context, e app.Event, replica *gateway.JupyterKernelReplica) {
@@ -229,6 +300,7 @@ func (w *MainWindow) onExecuteReplicaButtonClicked(ctx app.Context, e app.Event,
 
 	w.executeReplicaModalOpen = true
 	w.replicaToExecute = replica
+	w.Update()
 }
 
 // Handler for the "Execute" button for a specific kernel (but not a specific replica).
@@ -237,25 +309,78 @@ func (w *MainWindow) onExecuteKernelButtonClicked(ctx app.Context, e app.Event,
 
 	w.executeKernelModalOpen = true
 	w.kernelToExecute = kernel
+	w.Update()
 }
 
 func (w *MainWindow) onMigrateSubmit(replica *gateway.JupyterKernelReplica, targetNode *domain.KubernetesNode) {
-	err := w.WorkloadDriver.MigrateKernelReplica(&gateway.MigrationRequest{
+	req := &gateway.MigrationRequest{
 		TargetReplica: &gateway.ReplicaInfo{
 			KernelId:  replica.KernelId,
 			ReplicaId: replica.ReplicaId,
 		},
-	})
+	}
 
-	if err != nil {
-		app.Logf("[ERROR] Failed to migrate replica %d of kernel %s.", replica.ReplicaId, replica.KernelId)
-		w.HandleError(err, fmt.Sprintf("Could not migrate replica %d of kernel %s.", replica.ReplicaId, replica.KernelId))
+	// If the user didn't select a node, then the Cluster Gateway chooses one.
+	destination := "a node chosen by the Cluster Gateway"
+	if targetNode != nil {
+		req.TargetNodeId = &targetNode.NodeId
+		destination = targetNode.NodeId
+	}
+
+	w.migarateModalOpen = false
+	w.Update()
+
+	// The migration is tracked, and displayed in the migration history, until the replica is seen on its new node.
+	go func() {
+		app.Logf("Migrating replica %d of kernel %s to %s.", replica.ReplicaId, replica.KernelId, destination)
+
+		if err := w.WorkloadDriver.MigrateKernelReplica(req); err != nil {
+			app.Logf("[ERROR] Failed to migrate replica %d of kernel %s.", replica.ReplicaId, replica.KernelId)
+			w.HandleError(err, fmt.Sprintf("Could not migrate replica %d of kernel %s to %s.", replica.ReplicaId, replica.KernelId, destination))
+			return
+		}
+
+		app.Logf("Successfully migrated replica %d of kernel %s!", replica.ReplicaId, replica.KernelId)
+		w.addSuccessAlert("Replica Migrated", fmt.Sprintf("Migrated replica %d of kernel %s to %s.", replica.ReplicaId, replica.KernelId, destination))
+	}()
+}
+
+// Handler for the "Cordon"/"Uncordon" button of a specific node. Toggles whether the node is schedulable.
+func (w *MainWindow) onCordonNodeButtonClicked(ctx app.Context, e app.Event, node *domain.KubernetesNode) {
+	cordon := !node.Unschedulable
+	app.Logf("User wishes to set whether node %s is cordoned to %v.", node.NodeId, cordon)
+
+	if err := w.WorkloadDriver.CordonNode(node.NodeId, cordon); err != nil {
+		w.HandleError(err, fmt.Sprintf("Could not update node %s.", node.NodeId))
 		return
+	}
+
+	if cordon {
+		w.addSuccessAlert("Node Cordoned", fmt.Sprintf("No further replicas will be placed on node %s.", node.NodeId))
 	} else {
-		app.Logf("Successfully migrated replica %d of kernel %s!", replica.ReplicaId, replica.KernelId)
+		w.addSuccessAlert("Node Uncordoned", fmt.Sprintf("Replicas may be placed on node %s again.", node.NodeId))
 	}
 }
 
+// Handler for the "Drain" button of a specific node.
+func (w *MainWindow) onDrainNodeButtonClicked(ctx app.Context, e app.Event, node *domain.KubernetesNode) {
+	app.Logf("User wishes to drain node %s.", node.NodeId)
+
+	w.drainNodeModalOpen = true
+	w.nodeToDrain = node.NodeId
+	w.Update()
+}
+
+// Called once a drain started from the DrainNodeModal has completed or failed. The modal itself displays each step.
+func (w *MainWindow) onDrainFinished(nodeId string, status *domain.DrainStatus, err error) {
+	if err != nil {
+		w.HandleError(err, fmt.Sprintf("Could not drain node %s.", nodeId))
+		return
+	}
+
+	w.addSuccessAlert("Node Drained", fmt.Sprintf("Completed all %d step(s) of draining node %s.", len(status.Steps), nodeId))
+}
+
 func (w *MainWindow) handleCancel(dirty bool, clear chan struct{}, confirm func()) {
 	if !dirty {
 		confirm()
@@ -272,6 +397,20 @@ func (w *MainWindow) handleCancel(dirty bool, clear chan struct{}, confirm func(
 	w.Update()
 }
 
+func (w *MainWindow) addSuccessAlert(title string, description string) {
+	w.addAlert(&Alert{
+		ID:               uuid.New().String(),
+		Name:             title,
+		Class:            "pf-v5-c-alert pf-m-success",
+		IconWrapperClass: "pf-v5-c-alert__icon",
+		IconClass:        "fas fa-fw fa-check-circle",
+		Title:            title,
+		Description:      description,
+		OnClose:          w.onAlertClosed,
+		HasButton:        false,
+	})
+}
+
 func (w *MainWindow) addAlert(alert *Alert) {
 	app.Logf("Adding new alert: '%s'", alert.Name)
 	w.Alerts.Set(alert.ID, alert)
@@ -320,21 +459,12 @@ func (w *MainWindow) getPhaseOneUI() app.UI {
 // Phase 2:
 // - We're in "Phase 2" if BOTH of the following are true (i.e., a AND b):
 //   - (a) We've received the configuration from the backend server.
-//   - (b) We've not yet connected to the Gateway. If we're spoofing the Gateway connection, then this part is satisfied immediately/automatically/inherently.
+//   - (b) We're not connected to the Gateway, either because we've not yet connected or because the connection was lost.
+//     If we're spoofing the cluster, then we connect to the fake Gateway automatically.
 func (w *MainWindow) checkPhaseTwoUICondition() bool {
 	// We need to have received the configuration. That's condition (a).
 	if w.ConfigurationReceived {
-		// For condition (b), there are two things we can check here.
-		// If we're supposed to spoof the gateway connection, then we essentially skip Phase 2 and can move right to Phase 3.
-		// If we're NOT supposed to spoof the gateway connection -- that is, we're actually supposed to connect, then we need to check if we've connected.
-		// If we have not yet connected, then we are indeed in Phase 2  then we'll return true. If not, then we'll return false.
-		if w.configuration.SpoofCluster {
-			// We return false because we're essentially skipping phase 2.
-			// We're spoofing the gateway connection, so we can just skip past the UI for specifying the gateway's address.
-			return false
-		}
-
-		// If we're NOT spoofing the connection, then we're only in Phase 2 if we've NOT YET CONNECTED.
+		// For condition (b), we're only in Phase 2 if we've NOT YET CONNECTED.
 		// If we have already connected, then we're in Phase 3, not Phase 2.
 		return !w.WorkloadDriver.ConnectedToGateway()
 	}
@@ -367,7 +497,7 @@ func (w *MainWindow) getPhaseTwoUI() app.UI {
 						Text("Disconnected"),
 					app.Div().
 						Class("pf-v5-c-empty-state__body").
-						Text("To start, please enter the IP address and port of the Cluster Gateway gRPC server and press Connect."),
+						Text(w.getPhaseTwoMessage()),
 					app.Div().Class("pf-v5-c-form").Body(
 						app.Div().
 							Class("pf-v5-c-form__group").
@@ -426,23 +556,28 @@ func (w *MainWindow) getPhaseTwoUI() app.UI {
 		)
 }
 
+// Return the instructions displayed during Phase 2, which depend upon whether the connection to the Gateway was lost.
+func (w *MainWindow) getPhaseTwoMessage() string {
+	event := w.connectionEvent
+	if event == nil || event.State != domain.GatewayReconnecting {
+		return "To start, please enter the IP address and port of the Cluster Gateway gRPC server and press Connect."
+	}
+
+	if event.Attempt == 0 {
+		return fmt.Sprintf("Lost the connection to the Cluster Gateway at %s: %s. Reconnecting in %v. Alternatively, enter another address and press Connect.", event.Address, event.Error, event.RetryIn)
+	}
+
+	return fmt.Sprintf("Could not reconnect to the Cluster Gateway at %s after %d attempt(s): %s. Retrying in %v. Alternatively, enter another address and press Connect.", event.Address, event.Attempt, event.Error, event.RetryIn)
+}
+
 // Phase 3:
 // - We're in "Phase 3" if BOTH of the following are true (i.e., a AND b):
 //   - (a) We've received the configuration from the backend server.
-//   - (b) We've not yet connected to the Gateway. If we're spoofing the Gateway connection, then this part is satisfied immediately/automatically/inherently.
+//   - (b) We've connected to the Gateway. If we're spoofing the cluster, then we connect to the fake Gateway automatically.
 func (w *MainWindow) checkPhaseThreeUICondition() bool {
 	// We need to have received the configuration. That's condition (a).
 	if w.ConfigurationReceived {
-		// For condition (b), there are two things we can check here.
-		// If we're supposed to spoof the gateway connection, then we can immediately conclude that we're in Phase 3.
-		// If we're NOT supposed to spoof the gateway connection -- that is, we're actually supposed to connect, then we need to check if we've connected.
-		// In order for us to be in Phase 3, we need to be connected to the cluster gateway.
-		if w.configuration.SpoofCluster {
-			// If we're supposed to spoof the gateway connection, then we can immediately conclude that we're in Phase 3.
-			return true
-		}
-
-		// If we're NOT spoofing the connection, then we're only in Phase 2 if we ARE already connected.
+		// In order for us to be in Phase 3, we need to be connected to the cluster gateway. That's condition (b).
 		return w.WorkloadDriver.ConnectedToGateway()
 	}
 
@@ -486,7 +621,7 @@ func (w *MainWindow) getPhaseThreeUI() app.UI {
 			app.Div().Class("pf-v5-l-grid__item pf-m-gutter pf-m-6-col").Body(
 				app.Div().Class("pf-v5-l-flex pf-m-column pf-m-row-on-md pf-m-column-on-lg").Body(
 					app.Div().Class("pf-v5-l-grid__item pf-m-gutter pf-m-6-col").Body(
-						NewKernelList(w.WorkloadDriver.KernelProvider(), w, w.onMigrateButtonClicked, w.onExecuteKernelButtonClicked, w.onExecuteReplicaButtonClicked, w.onCreateKernelButtonClicked, w.onTerminateSelectedKernelsButtonClicked, w.onTerminateSpecificKernelButtonClicked),
+						NewKernelList(w.WorkloadDriver.KernelProvider(), w, w.onMigrateButtonClicked, w.onExecuteKernelButtonClicked, w.onExecuteReplicaButtonClicked, w.onCreateKernelButtonClicked, w.onTerminateSelectedKernelsButtonClicked, w.onTerminateSpecificKernelButtonClicked, w.onInterruptKernelButtonClicked),
 					),
 					app.Div().Class("pf-v5-l-grid__item pf-m-gutter pf-m-6-col").Body(
 						NewKernelSpecCard(w.WorkloadDriver.KernelSpecProvider()),
@@ -494,8 +629,14 @@ func (w *MainWindow) getPhaseThreeUI() app.UI {
 				),
 			),
 			app.Div().Class("pf-v5-l-grid__item pf-m-gutter pf-m-6-col").Body(
+				app.Div().Class("pf-v5-l-grid__item pf-m-gutter").Style("margin-bottom", "16px").Body(
+					NewWorkloadRunCard(w.WorkloadDriver.WorkloadRunProvider()),
+				),
+				app.Div().Class("pf-v5-l-grid__item pf-m-gutter").Style("margin-bottom", "16px").Body(
+					NewNodeList(w.WorkloadDriver.NodeProvider(), w, false, func(kn *domain.KubernetesNode) { /* Do nothing */ }, w.onCordonNodeButtonClicked, w.onDrainNodeButtonClicked),
+				),
 				app.Div().Class("pf-v5-l-grid__item pf-m-gutter").Body(
-					NewNodeList(w.WorkloadDriver.NodeProvider(), w, false, func(kn *domain.KubernetesNode) { /* Do nothing */ }),
+					NewMigrationHistory(w.WorkloadDriver.MigrationTracker()),
 				),
 			),
 		))
@@ -527,6 +668,8 @@ func (w *MainWindow) getUI() app.UI {
 
 // The Render method is where the component appearance is defined.
 func (w *MainWindow) Render() app.UI {
+	replicaToExecuteId := w.replicaToExecute.GetReplicaId()
+
 	return app.Div().Body(
 		app.Div().
 			Class("pf-v5-c-page").
@@ -564,6 +707,45 @@ func (w *MainWindow) Render() app.UI {
 								})
 							},
 						}),
+						app.If(w.executeKernelModalOpen, &ExecutionModal{
+							ID:       "execute-kernel-modal",
+							KernelId: w.kernelToExecute.GetKernelId(),
+							Executor: w.WorkloadDriver,
+							OnClose: func() {
+								w.executeKernelModalOpen = false
+								w.Update()
+							},
+						}),
+						app.If(w.executeReplicaModalOpen, &ExecutionModal{
+							ID:       "execute-replica-modal",
+							KernelId: w.replicaToExecute.GetKernelId(),
+							Replica:  &replicaToExecuteId,
+							Executor: w.WorkloadDriver,
+							OnClose: func() {
+								w.executeReplicaModalOpen = false
+								w.Update()
+							},
+						}),
+						app.If(w.drainNodeModalOpen, &DrainNodeModal{
+							ID:         "drain-node-modal",
+							NodeId:     w.nodeToDrain,
+							Drainer:    w.WorkloadDriver,
+							OnFinished: w.onDrainFinished,
+							OnClose: func() {
+								w.drainNodeModalOpen = false
+								w.Update()
+							},
+						}),
+						app.If(w.createKernelModalOpen, &CreateKernelModal{
+							ID:          "create-kernel-modal",
+							KernelSpecs: w.kernelSpecs,
+							OnSubmit:    w.onCreateKernelSubmit,
+							OnCancel: func(dirty bool, clear chan struct{}) {
+								w.handleCancel(dirty, clear, func() {
+									w.createKernelModalOpen = false
+								})
+							},
+						}),
 						app.If(w.err != nil, &ErrorModal{
 							ID:          "error-modal",
 							Icon:        "fas fa-times",
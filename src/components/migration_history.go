@@ -0,0 +1,148 @@
+package components
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+func getIconForMigrationState(state domain.MigrationState) string {
+	switch state {
+	case domain.MigrationRequested:
+		return "fas fa-spinner fa-pulse fa-spin"
+	case domain.MigrationAcknowledged:
+		return "fas fa-hourglass-half"
+	case domain.MigrationCompleted:
+		return "fas fa-check-circle"
+	case domain.MigrationFailed:
+		return "fas fa-times-circle"
+	default:
+		app.Logf("[WARNING] Unknown migration state received: \"%s\"\n", state)
+		return ""
+	}
+}
+
+// Displays the progress of every migration performed by the driver, most recent first.
+type MigrationHistory struct {
+	app.Compo
+
+	id               string
+	migrationTracker domain.MigrationTracker
+	migrations       []*domain.MigrationRecord
+}
+
+func NewMigrationHistory(migrationTracker domain.MigrationTracker) *MigrationHistory {
+	return &MigrationHistory{
+		id:               uuid.New().String(),
+		migrationTracker: migrationTracker,
+		migrations:       migrationTracker.Migrations(),
+	}
+}
+
+func (h *MigrationHistory) handleMigrationsChanged(migrations []*domain.MigrationRecord) {
+	if !h.Mounted() {
+		return
+	}
+
+	h.migrations = migrations
+	h.Update()
+}
+
+func (h *MigrationHistory) OnMount(ctx app.Context) {
+	h.migrationTracker.SubscribeToMigrations(h.id, h.handleMigrationsChanged)
+
+	// Catch up with any migrations that occurred between creating the history and subscribing to it.
+	h.migrations = h.migrationTracker.Migrations()
+}
+
+func (h *MigrationHistory) OnDismount(ctx app.Context) {
+	h.migrationTracker.UnsubscribeFromMigrations(h.id)
+}
+
+// Describe where the replica was migrated to: the node on which it was seen, or else the requested node, if any.
+func migrationDestination(record *domain.MigrationRecord) string {
+	switch {
+	case record.NodeId != "":
+		return record.NodeId
+	case record.TargetNodeId != "":
+		return record.TargetNodeId
+	case record.Hostname != "":
+		return record.Hostname
+	default:
+		return "(any)"
+	}
+}
+
+func (h *MigrationHistory) row(record *domain.MigrationRecord) app.UI {
+	duration := "-"
+	if d, ok := record.Duration(); ok {
+		duration = d.Round(time.Millisecond).String()
+	}
+
+	source := record.SourceNodeId
+	if source == "" {
+		source = "(unknown)"
+	}
+
+	state := app.Span().Text(string(record.State))
+	if record.Error != "" {
+		state = app.Span().Title(record.Error).Style("color", "#c9190b").Text(string(record.State))
+	}
+
+	return app.Tr().Class("pf-v5-c-table__tr").Body(
+		app.Td().Class("pf-v5-c-table__td").DataSet("label", "Replica").Text(fmt.Sprintf("kernel-%s-%d", record.KernelId, record.ReplicaId)),
+		app.Td().Class("pf-v5-c-table__td").DataSet("label", "From").Text(source),
+		app.Td().Class("pf-v5-c-table__td").DataSet("label", "To").Text(migrationDestination(record)),
+		app.Td().Class("pf-v5-c-table__td").DataSet("label", "State").Body(
+			app.Div().Class("pf-v5-l-flex pf-m-space-items-xs").Body(
+				app.I().Class(getIconForMigrationState(record.State)).Aria("hidden", true),
+				state,
+			),
+		),
+		app.Td().Class("pf-v5-c-table__td").DataSet("label", "Requested").Text(record.RequestedAt.Format(time.TimeOnly)),
+		app.Td().Class("pf-v5-c-table__td").DataSet("label", "Duration").Text(duration),
+	)
+}
+
+func (h *MigrationHistory) Render() app.UI {
+	migrations := h.migrations
+
+	header := app.Div().Class("pf-v5-c-card__header").Body(
+		app.Div().Class("pf-v5-c-card__title").Body(
+			app.H2().Class("pf-v5-c-title pf-m-2xl").Text("Migration History"),
+		),
+	)
+
+	if len(migrations) == 0 {
+		return app.Div().Class("pf-v5-c-card").Body(
+			header,
+			app.Div().Class("pf-v5-c-card__body").Text("No replicas have been migrated yet."),
+		)
+	}
+
+	return app.Div().Class("pf-v5-c-card").Body(
+		header,
+		app.Div().Class("pf-v5-c-card__body").Body(
+			app.Table().Class("pf-v5-c-table pf-m-compact pf-m-grid-md").Aria("label", "Migration history").Body(
+				app.THead().Class("pf-v5-c-table__thead").Body(
+					app.Tr().Class("pf-v5-c-table__tr").Body(
+						app.Th().Class("pf-v5-c-table__th").Scope("col").Text("Replica"),
+						app.Th().Class("pf-v5-c-table__th").Scope("col").Text("From"),
+						app.Th().Class("pf-v5-c-table__th").Scope("col").Text("To"),
+						app.Th().Class("pf-v5-c-table__th").Scope("col").Text("State"),
+						app.Th().Class("pf-v5-c-table__th").Scope("col").Text("Requested"),
+						app.Th().Class("pf-v5-c-table__th").Scope("col").Text("Duration"),
+					),
+				),
+				app.TBody().Class("pf-v5-c-table__tbody").Body(
+					app.Range(migrations).Slice(func(i int) app.UI {
+						return h.row(migrations[len(migrations)-1-i])
+					}),
+				),
+			),
+		),
+	)
+}
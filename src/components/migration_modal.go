@@ -40,13 +40,14 @@ func (c *MigrationModal) OnNodeSelected(selectedNode *domain.KubernetesNode) {
 
 func (c *MigrationModal) Render() app.UI {
 	modal_id := fmt.Sprintf("migrate-kernel-%s-%d-modal", c.Replica.KernelId, c.Replica.ReplicaId)
+	form_id := fmt.Sprintf("%s-form", modal_id)
 	return &Modal{
 		ID:    modal_id,
 		Title: fmt.Sprintf("Migrate kernel-%s-%d", c.Replica.KernelId, c.Replica.ReplicaId),
 		Body: []app.UI{
 			app.Form().
 				Class("pf-v5-c-form").
-				ID(modal_id).
+				ID(form_id).
 				OnSubmit(func(ctx app.Context, e app.Event) {
 					e.PreventDefault()
 
@@ -57,7 +58,7 @@ func (c *MigrationModal) Render() app.UI {
 				app.Div().Class("pf-v5-c-form__group").Body(
 					app.Div().Class("pf-v5-l-grid pf-m-gutter").Body(
 						app.Div().Class("pf-v5-l-grid__item pf-m-gutter pf-m-12-col").Body(
-							NewNodeList(c.WorkloadDriver.NodeProvider(), c.ErrorHandler, true, c.OnNodeSelected),
+							NewNodeList(c.WorkloadDriver.NodeProvider(), c.ErrorHandler, true, c.OnNodeSelected, nil, nil),
 						),
 					),
 				),
@@ -67,7 +68,7 @@ func (c *MigrationModal) Render() app.UI {
 			app.Button().
 				Class("pf-v5-c-button pf-m-primary").
 				Type("submit").
-				Form("encrypt-and-sign-form").
+				Form(form_id).
 				Text("Migrate"),
 			app.Button().
 				Class("pf-v5-c-button pf-m-link").
@@ -16,22 +16,27 @@ type NodeList struct {
 
 	id string
 
-	onNodeSelected func(*domain.KubernetesNode)
-	errorHandler   domain.ErrorHandler
-	nodeProvider   domain.NodeProvider
+	onNodeSelected        func(*domain.KubernetesNode)
+	onCordonButtonClicked CordonNodeButtonClickedHandler // Nil if the node actions are hidden.
+	onDrainButtonClicked  DrainNodeButtonClickedHandler  // Nil if the node actions are hidden.
+	errorHandler          domain.ErrorHandler
+	nodeProvider          domain.NodeProvider
 
 	Nodes       []*domain.KubernetesNode
 	expanded    map[string]bool
 	selectedIdx int
 }
 
-func NewNodeList(nodeProvider domain.NodeProvider, errorHandler domain.ErrorHandler, radioButtonsVisible bool, onNodeSelected func(*domain.KubernetesNode)) *NodeList {
+// The buttons for cordoning and draining each node are only displayed if the handlers are non-nil.
+func NewNodeList(nodeProvider domain.NodeProvider, errorHandler domain.ErrorHandler, radioButtonsVisible bool, onNodeSelected func(*domain.KubernetesNode), onCordonButtonClicked CordonNodeButtonClickedHandler, onDrainButtonClicked DrainNodeButtonClickedHandler) *NodeList {
 	nodeList := &NodeList{
-		id:                  fmt.Sprintf("NodeList-%s", uuid.New().String()[0:26]),
-		onNodeSelected:      onNodeSelected,
-		nodeProvider:        nodeProvider,
-		errorHandler:        errorHandler,
-		radioButtonsVisible: radioButtonsVisible,
+		id:                    fmt.Sprintf("NodeList-%s", uuid.New().String()[0:26]),
+		onNodeSelected:        onNodeSelected,
+		onCordonButtonClicked: onCordonButtonClicked,
+		onDrainButtonClicked:  onDrainButtonClicked,
+		nodeProvider:          nodeProvider,
+		errorHandler:          errorHandler,
+		radioButtonsVisible:   radioButtonsVisible,
 	}
 
 	nodes := nodeProvider.Resources()
@@ -89,6 +94,40 @@ func (nl *NodeList) getMaxHeight(node_id string) string {
 	}
 }
 
+// Return the buttons for cordoning (or uncordoning) and draining the node.
+func (nl *NodeList) nodeActions(node *domain.KubernetesNode) app.UI {
+	if nl.onCordonButtonClicked == nil || nl.onDrainButtonClicked == nil {
+		return app.Div()
+	}
+
+	cordonLabel := "Cordon"
+	if node.Unschedulable {
+		cordonLabel = "Uncordon"
+	}
+
+	return app.Div().
+		Class("pf-v5-c-data-list__cell pf-m-align-right pf-m-no-fill").
+		Body(
+			app.Button().
+				Class("pf-v5-c-button pf-m-secondary").
+				Type("button").
+				Text(cordonLabel).
+				Style("font-size", "16px").
+				Style("margin-right", "16px").
+				OnClick(func(ctx app.Context, e app.Event) {
+					go nl.onCordonButtonClicked(ctx, e, node)
+				}),
+			app.Button().
+				Class("pf-v5-c-button pf-m-secondary pf-m-danger").
+				Type("button").
+				Text("Drain").
+				Style("font-size", "16px").
+				OnClick(func(ctx app.Context, e app.Event) {
+					go nl.onDrainButtonClicked(ctx, e, node)
+				}),
+		)
+}
+
 func (nl *NodeList) Render() app.UI {
 	nodes := nl.Nodes
 
@@ -136,6 +175,7 @@ func (nl *NodeList) Render() app.UI {
 																app.Input().Class("pf-v5-c-radio__input").Type("radio").Name(fmt.Sprintf("node-list-%s-radio-buttons", nl.id)).ID(fmt.Sprintf("node-%d-radio", i)).OnInput(func(ctx app.Context, e app.Event) {
 																	app.Logf("Checkbox node-%d-radio received input. Context: %v. Event: %v.", i, ctx, e)
 																	nl.selectedIdx = i
+																	nl.onNodeSelected(nodes[i])
 																}),
 															),
 														)),
@@ -181,6 +221,16 @@ func (nl *NodeList) Render() app.UI {
 																				Text("Node "+nodes[i].NodeId).
 																				Style("font-weight", "bold").
 																				Style("font-size", "20px"),
+																			app.If(nodes[i].Unschedulable,
+																				app.Span().Class("pf-v5-c-label pf-m-orange").Body(
+																					app.Span().Class("pf-v5-c-label__content").Body(
+																						app.Span().Class("pf-v5-c-label__icon").Body(
+																							app.I().Class("fas fa-ban").Aria("hidden", true),
+																						),
+																						app.Text("Cordoned"),
+																					),
+																				),
+																			),
 																		),
 																),
 															app.Div().Class("pf-v5-c-description-list pf-m-2-col-on-lg").
@@ -254,7 +304,19 @@ func (nl *NodeList) Render() app.UI {
 																		Content:      "/web/icons/gpu-icon.svg",
 																		UseClass:     false,
 																	},
+																	// Most nodes have no vGPUs, in which case there's nothing to show.
+																	app.If(nodes[i].CapacityVGPUs > 0 || nodes[i].AllocatedVGPUs > 0,
+																		&ResourceLabel{
+																			ResourceName: "vGPU",
+																			Allocated:    nodes[i].AllocatedVGPUs,
+																			Capacity:     nodes[i].CapacityVGPUs,
+																			FontSize:     16,
+																			Class:        "fas fa-layer-group",
+																			UseClass:     true,
+																		},
+																	),
 																)),
+													nl.nodeActions(nodes[i]),
 												),
 										),
 									// Expanded content.
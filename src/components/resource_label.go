@@ -21,18 +21,24 @@ type ResourceLabel struct {
 	Capacity     float64
 }
 
+// Return the percentage of the capacity that is allocated, between 0 and 100.
 func getPercentage(allocated float64, capacity float64) float64 {
 	if capacity == 0 {
 		return 0.0
 	}
 
-	return allocated / capacity
+	return allocated / capacity * 100
+}
+
+func (rl *ResourceLabel) text() string {
+	return fmt.Sprintf("%.2f / %.2f (%.2f%%)", rl.Allocated, rl.Capacity, getPercentage(rl.Allocated, rl.Capacity))
 }
 
 func (rl *ResourceLabel) Render() app.UI {
 	if rl.UseClass {
 		return app.Div().
 			Class("pf-v5-l-flex pf-m-space-items-xs").
+			Title(rl.ResourceName).
 			Body(
 				app.Div().
 					Class("pf-v5-l-flex__item").
@@ -46,13 +52,14 @@ func (rl *ResourceLabel) Render() app.UI {
 					Class("pf-v5-l-flex__item").
 					Body(
 						app.Span().
-							Text(fmt.Sprintf("%.2f / %.2f (%.2f%%)", rl.Allocated, rl.Capacity, getPercentage(rl.Allocated, rl.Capacity))).
+							Text(rl.text()).
 							Style("font-size", fmt.Sprintf("%dpx", rl.FontSize)),
 					),
 			)
 	} else {
 		return app.Div().
 			Class("pf-v5-l-flex pf-m-space-items-xs").
+			Title(rl.ResourceName).
 			Body(
 				app.Div().
 					Class("pf-v5-l-flex__item").
@@ -66,7 +73,7 @@ func (rl *ResourceLabel) Render() app.UI {
 					Class("pf-v5-l-flex__item").
 					Body(
 						app.Span().
-							Text(fmt.Sprintf("%.2f / %.2f (%.2f%%)", rl.Allocated, rl.Capacity, getPercentage(rl.Allocated, rl.Capacity))).
+							Text(rl.text()).
 							Style("font-size", fmt.Sprintf("%dpx", rl.FontSize)),
 					),
 			)
@@ -0,0 +1,162 @@
+package components
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+func getIconForWorkloadRunState(state domain.WorkloadRunState) string {
+	switch state {
+	case domain.WorkloadRunPending:
+		return "fas fa-hourglass-start"
+	case domain.WorkloadRunRunning:
+		return "fas fa-spinner fa-pulse fa-spin"
+	case domain.WorkloadRunPaused:
+		return "fas fa-pause"
+	case domain.WorkloadRunFinished:
+		return "fas fa-check-circle"
+	case domain.WorkloadRunAborted:
+		return "fas fa-ban"
+	default:
+		app.Logf("[WARNING] Unknown workload run state received: \"%s\"\n", state)
+		return ""
+	}
+}
+
+// Displays the status of the workload run managed by the backend, along with controls to start, pause, resume, and stop it.
+type WorkloadRunCard struct {
+	app.Compo
+
+	id                  string
+	WorkloadRunProvider domain.WorkloadRunProvider
+	run                 *domain.WorkloadRun
+}
+
+func NewWorkloadRunCard(workloadRunProvider domain.WorkloadRunProvider) *WorkloadRunCard {
+	card := &WorkloadRunCard{
+		id:                  uuid.New().String(),
+		WorkloadRunProvider: workloadRunProvider,
+	}
+
+	if runs := workloadRunProvider.Resources(); len(runs) > 0 {
+		card.run = runs[0]
+	}
+
+	return card
+}
+
+func (c *WorkloadRunCard) handleWorkloadRunRefreshed(runs []*domain.WorkloadRun) bool {
+	if !c.Mounted() {
+		app.Logf("WorkloadRunCard %s (%p) is not mounted; ignoring refresh.", c.id, c)
+		return false
+	}
+
+	if len(runs) > 0 {
+		c.run = runs[0]
+	} else {
+		c.run = nil
+	}
+
+	c.Update()
+
+	return true
+}
+
+func (c *WorkloadRunCard) OnMount(ctx app.Context) {
+	c.WorkloadRunProvider.SubscribeToRefreshes(c.id, c.handleWorkloadRunRefreshed)
+
+	go c.WorkloadRunProvider.RefreshResources()
+}
+
+func (c *WorkloadRunCard) OnDismount(ctx app.Context) {
+	c.WorkloadRunProvider.UnsubscribeFromRefreshes(c.id)
+}
+
+func (c *WorkloadRunCard) controlButton(text string, class string, enabled bool, action func() error) app.UI {
+	return app.Button().
+		Class(class).
+		Type("button").
+		Text(text).
+		Style("font-size", "16px").
+		Style("margin-right", "16px").
+		Disabled(!enabled).
+		OnClick(func(ctx app.Context, e app.Event) {
+			e.StopImmediatePropagation()
+			app.Logf("'%s Workload' button clicked.", text)
+			go action()
+		})
+}
+
+func (c *WorkloadRunCard) descriptionListGroup(term string, description string) app.UI {
+	return app.Div().Class("pf-v5-c-description-list__group").Body(
+		app.Dt().Class("pf-v5-c-description-list__term").Text(term),
+		app.Dd().Class("pf-v5-c-description-list__description").Body(
+			app.Div().Class("pf-v5-c-description-list__text").Text(description),
+		),
+	)
+}
+
+func formatRunTimestamp(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Format(time.DateTime)
+}
+
+func (c *WorkloadRunCard) Render() app.UI {
+	run := c.run
+
+	header := app.Div().Class("pf-v5-c-card__header").Body(
+		app.Div().Class("pf-v5-c-card__title").Body(
+			app.H2().Class("pf-v5-c-title pf-m-2xl").Text("Workload"),
+		),
+		app.Div().Class("pf-v5-c-card__actions pf-m-no-offset").Body(
+			c.controlButton("Start", "pf-v5-c-button pf-m-inline pf-m-primary", run != nil && (run.State == domain.WorkloadRunPending || run.Done()), c.WorkloadRunProvider.StartRun),
+			c.controlButton("Pause", "pf-v5-c-button pf-m-inline pf-m-secondary", run != nil && run.State == domain.WorkloadRunRunning, c.WorkloadRunProvider.PauseRun),
+			c.controlButton("Resume", "pf-v5-c-button pf-m-inline pf-m-secondary", run != nil && run.State == domain.WorkloadRunPaused, c.WorkloadRunProvider.ResumeRun),
+			c.controlButton("Stop", "pf-v5-c-button pf-m-inline pf-m-secondary pf-m-danger", run != nil && (run.State == domain.WorkloadRunRunning || run.State == domain.WorkloadRunPaused), c.WorkloadRunProvider.StopRun),
+		),
+	)
+
+	// If the run hasn't been loaded yet (or no workload was configured), then just render the header of the card.
+	if run == nil {
+		return app.Div().Class("pf-v5-c-card").Body(
+			header,
+			app.Div().Class("pf-v5-c-card__body").Text("No workload has been configured. Pass one to the backend with --workload."),
+		)
+	}
+
+	progress := int(run.Progress() * 100)
+
+	return app.Div().Class("pf-v5-c-card").Body(
+		header,
+		app.Div().Class("pf-v5-c-card__body").Body(
+			app.Div().Class("pf-v5-l-flex pf-m-space-items-sm").Style("margin-bottom", "16px").Body(
+				app.I().Class(getIconForWorkloadRunState(run.State)).Style("font-size", "16px"),
+				app.Span().Style("font-weight", "bold").Text(fmt.Sprintf("%s (%s)", run.Workload, run.State)),
+			),
+			app.Div().Class("pf-v5-c-progress").ID(fmt.Sprintf("workload-run-progress-%s", c.id)).Body(
+				app.Div().Class("pf-v5-c-progress__description").Text(fmt.Sprintf("%d of %d session(s) completed", run.SessionsCompleted, run.NumSessions)),
+				app.Div().Class("pf-v5-c-progress__status").Aria("hidden", true).Body(
+					app.Span().Class("pf-v5-c-progress__measure").Text(fmt.Sprintf("%d%%", progress)),
+				),
+				app.Div().Class("pf-v5-c-progress__bar").Role("progressbar").Aria("valuemin", 0).Aria("valuemax", 100).Aria("valuenow", progress).Body(
+					app.Div().Class("pf-v5-c-progress__indicator").Style("width", fmt.Sprintf("%d%%", progress)),
+				),
+			),
+			app.Dl().Class("pf-v5-c-description-list pf-m-compact pf-m-horizontal pf-m-2-col").Style("margin-top", "16px").Body(
+				c.descriptionListGroup("Cells Completed", fmt.Sprintf("%d", run.CellsCompleted)),
+				c.descriptionListGroup("Errors", fmt.Sprintf("%d", run.NumErrors)),
+				c.descriptionListGroup("Started", formatRunTimestamp(run.StartedAt)),
+				c.descriptionListGroup("Finished", formatRunTimestamp(run.FinishedAt)),
+				app.If(run.PausedAt != nil, c.descriptionListGroup("Paused", formatRunTimestamp(run.PausedAt))),
+				app.If(run.Error != "", c.descriptionListGroup("Error", run.Error)),
+			),
+		),
+	)
+}
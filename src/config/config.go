@@ -3,8 +3,15 @@ package config
 import (
 	"encoding/json"
 	"flag"
+	"fmt"
+	"log"
+	"net"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/workload"
 	"k8s.io/client-go/util/homedir"
 )
 
@@ -15,14 +22,51 @@ const (
 )
 
 type Configuration struct {
-	SpoofCluster            bool   `yaml:"spoof-gateway" json:"spoof-gateway" description:"If true, use the fake cluster."`
-	InCluster               bool   `yaml:"in-cluster" json:"in-cluster" description:"Should be true if running from within the kubernetes cluster."`
-	KernelQueryInterval     string `yaml:"kernel-query-interval" json:"kernel-query-interval" default:"5s" description:"How frequently to query the Cluster for updated kernel information."`
-	NodeQueryInterval       string `yaml:"node-query-interval" json:"node-query-interval" default:"10s" description:"How frequently to query the Cluster for updated Kubernetes node information."`
-	KernelSpecQueryInterval string `yaml:"kernel-spec-query-interval" json:"kernel-spec-query-interval" default:"600s" description:"How frequently to query the Cluster for updated Jupyter kernel spec information."`
-	KubeConfig              string `yaml:"kubeconfig" json:"kubeconfig" description:"Absolute path to the kubeconfig file."`
-	GatewayAddress          string `yaml:"gateway-address" json:"gateway-address" description:"The IP address that the front-end should use to connect to the Gateway."`
-	JupyterServerAddress    string `yaml:"jupyter-server-address" json:"jupyter-server-address" description:"The IP address of the Jupyter Server."`
+	SpoofCluster            bool     `yaml:"spoof-gateway" json:"spoof-gateway" description:"If true, serve an in-process fake Cluster Gateway at GatewayAddress and use it instead of a real cluster."`
+	InCluster               bool     `yaml:"in-cluster" json:"in-cluster" description:"Should be true if running from within the kubernetes cluster."`
+	ListenAddress           string   `yaml:"listen-address" json:"-" description:"Address at which the backend serves the frontend and its endpoints."` // The frontend uses the address from which it was loaded instead.
+	KernelQueryInterval     string   `yaml:"kernel-query-interval" json:"kernel-query-interval" default:"5s" description:"How frequently to query the Cluster for updated kernel information."`
+	NodeQueryInterval       string   `yaml:"node-query-interval" json:"node-query-interval" default:"10s" description:"How frequently to query the Cluster for updated Kubernetes node information."`
+	KernelSpecQueryInterval string   `yaml:"kernel-spec-query-interval" json:"kernel-spec-query-interval" default:"600s" description:"How frequently to query the Cluster for updated Jupyter kernel spec information."`
+	WorkloadQueryInterval   string   `yaml:"workload-query-interval" json:"workload-query-interval" default:"2s" description:"How frequently to query the backend for the status of the workload run."`
+	KubeConfig              string   `yaml:"kubeconfig" json:"kubeconfig" description:"Absolute path to the kubeconfig file."`
+	KubeNamespaces          []string `yaml:"kube-namespaces" json:"kube-namespaces" description:"Namespaces whose pods are displayed on the nodes. Empty means every namespace."`
+	KubePodSelector         string   `yaml:"kube-pod-selector" json:"kube-pod-selector" description:"Label selector restricting the pods that are displayed on the nodes."`
+	KubeNodeSelector        string   `yaml:"kube-node-selector" json:"kube-node-selector" description:"Label selector restricting the nodes that are displayed."`
+	KubeGpuResource         string   `yaml:"kube-gpu-resource" json:"kube-gpu-resource" description:"Extended resource through which the nodes advertise their GPUs."`
+	KubeVgpuResource        string   `yaml:"kube-vgpu-resource" json:"kube-vgpu-resource" description:"Extended resource through which the nodes advertise their vGPUs."`
+	GatewayAddress          string   `yaml:"gateway-address" json:"gateway-address" description:"The IP address that the front-end should use to connect to the Gateway."`
+	RpcTimeout              string   `yaml:"rpc-timeout" json:"rpc-timeout" default:"30s" description:"Timeout for individual RPC calls to the Cluster Gateway, e.g., to migrate a replica."`
+	GatewayProbeInterval    string   `yaml:"gateway-probe-interval" json:"gateway-probe-interval" default:"5s" description:"How frequently the front-end checks that the Cluster Gateway is reachable."`
+	ProxyMaxConnections     int      `yaml:"proxy-max-connections" json:"proxy-max-connections" description:"Maximum number of websocket connections to the fake Cluster Gateway open at once. Zero means unlimited."`
+	ProxyIdleTimeout        string   `yaml:"proxy-idle-timeout" json:"proxy-idle-timeout" default:"10m" description:"How long a websocket connection to the fake Cluster Gateway may go unused before it is closed. Zero means never."`
+	GatewayMaxBackoff       string   `yaml:"gateway-max-backoff" json:"gateway-max-backoff" default:"30s" description:"The longest the front-end waits between attempts to reconnect to the Cluster Gateway."`
+	JupyterServerAddress    string   `yaml:"jupyter-server-address" json:"jupyter-server-address" description:"The IP address of the Jupyter Server."`
+	JupyterServerToken      string   `yaml:"jupyter-server-token" json:"-" description:"Token with which to authenticate with the Jupyter Server."` // Never sent to the frontend.
+	WorkloadPath            string   `yaml:"workload" json:"workload" description:"Path to a YAML or JSON file containing the workload specification."`
+	Seed                    int64    `yaml:"seed" json:"seed" description:"Seed for the random number generators used when spoofing the cluster."`
+
+	GatewayTLS     bool   `yaml:"gateway-tls" json:"gateway-tls" description:"If true, connect to the Cluster Gateway via wss:// rather than ws://."`
+	TLSCert        string `yaml:"tls-cert" json:"-" description:"Certificate with which the backend, and the fake Cluster Gateway when spoofing the cluster, serve TLS."`
+	TLSKey         string `yaml:"tls-key" json:"-" description:"Private key of the certificate with which TLS is served."`
+	TLSCACert      string `yaml:"tls-ca-cert" json:"-" description:"CA certificate trusted, in addition to the system's, when connecting via TLS. Browsers use their own."`
+	TLSClientCert  string `yaml:"tls-client-cert" json:"-" description:"Client certificate presented when connecting to the Cluster Gateway via TLS. Browsers use their own."`
+	TLSClientKey   string `yaml:"tls-client-key" json:"-" description:"Private key of the client certificate."`
+	AuthToken      string `yaml:"auth-token" json:"-" description:"Bearer token that requests to the backend's endpoints must carry."`                  // Never sent to the frontend.
+	AuthHmacSecret string `yaml:"auth-hmac-secret" json:"-" description:"Secret with which requests to the backend's endpoints may be signed instead."` // Never sent to the frontend.
+
+	AllowedOrigins []string `yaml:"allowed-origins" json:"-" description:"Hosts of the cross-origin pages that may open websockets to the backend and the fake Cluster Gateway."`
+
+	MigrationPolicy           domain.MigrationPolicyName `yaml:"migration-policy" json:"migration-policy" description:"Policy with which kernel replicas are migrated automatically: none, threshold, drain, or chaos."`
+	MigrationInterval         string                     `yaml:"migration-interval" json:"migration-interval" default:"30s" description:"How frequently the migration policy is evaluated."`
+	MigrationDryRun           bool                       `yaml:"migration-dry-run" json:"migration-dry-run" description:"If true, then the migrations decided upon by the migration policy are recorded, but not performed."`
+	MigrationRateLimit        int                        `yaml:"migration-rate-limit" json:"migration-rate-limit" description:"Maximum number of automatic migrations per minute. Zero means unlimited."`
+	MigrationHighWatermark    float64                    `yaml:"migration-high-watermark" json:"migration-high-watermark" description:"Utilization above which the threshold policy moves replicas off of a node."`
+	MigrationLowWatermark     float64                    `yaml:"migration-low-watermark" json:"migration-low-watermark" description:"Utilization below which the threshold policy moves replicas onto a node."`
+	MigrationDrainNodes       []string                   `yaml:"migration-drain-nodes" json:"migration-drain-nodes" description:"Nodes from which the drain policy moves every replica."`
+	MigrationChaosProbability float64                    `yaml:"migration-chaos-probability" json:"migration-chaos-probability" description:"Probability with which the chaos policy migrates a random replica each time it is evaluated."`
+
+	Workload *domain.Workload `yaml:"-" json:"workload-spec,omitempty"` // The workload loaded from WorkloadPath, if one was specified.
 
 	Valid bool `json:"Valid"` // Used to determine if the struct was sent/received correctly over the network.
 }
@@ -36,37 +80,196 @@ func (c *Configuration) String() string {
 	return string(out)
 }
 
+// Parse the configuration from the command-line flags. Exits if the configuration is invalid.
 func GetConfiguration() *Configuration {
-	var spoofFlag = flag.Bool("spoof-cluster", true, "Spoof the connection to the Cluster Gateway.")
-	var inClusterFlag = flag.Bool("in-cluster", false, "Should be true if running from within the kubernetes cluster.")
-	var kernelQueryIntervalFlag = flag.String("kernel-query-interval", "60s", "How often to refresh kernels from Cluster Gateway.")
-	var nodeQueryIntervalFlag = flag.String("node-query-interval", "120s", "How often to refresh nodes from Cluster Gateway.")
-	var gatewayAddressFlag = flag.String("gateway-address", "localhost:9990", "The IP address that the front-end should use to connect to the Gateway.")
-	var kernelSpecQueryIntervalFlag = flag.String("kernel-spec-query-interval", "600s", "How frequently to query the Cluster for updated Jupyter kernel spec information.")
-	var jupyterServerAddressFlag = flag.String("jupyter-server-address", "http://localhost:8888", "The IP address of the Jupyter Server.")
+	build := RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	conf, err := build()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return conf
+}
+
+// Register the configuration's flags with the given flag set.
+// The returned function builds the Configuration once the flag set has been parsed.
+func RegisterFlags(flags *flag.FlagSet) func() (*Configuration, error) {
+	var spoofFlag = flags.Bool("spoof-cluster", true, "Serve an in-process fake Cluster Gateway at the gateway address, and connect to it instead of a real cluster.")
+	var inClusterFlag = flags.Bool("in-cluster", false, "Should be true if running from within the kubernetes cluster.")
+	var listenAddressFlag = flags.String("listen-address", ":8000", "Address (host:port) at which the backend serves the frontend and its endpoints. The frontend reaches the backend at the address from which it was loaded.")
+	var kernelQueryIntervalFlag = flags.String("kernel-query-interval", "60s", "How often to refresh kernels from Cluster Gateway, if it does not support watching them.")
+	var nodeQueryIntervalFlag = flags.String("node-query-interval", "120s", "How often to refresh nodes from Cluster Gateway. Also how often the backend polls Kubernetes for the nodes' resource usage.")
+	var gatewayAddressFlag = flags.String("gateway-address", "localhost:9990", "The IP address that the front-end should use to connect to the Gateway.")
+	var rpcTimeoutFlag = flags.String("rpc-timeout", "30s", "Timeout for individual RPC calls to the Cluster Gateway, e.g., to create a kernel or to migrate a replica.")
+	var gatewayProbeIntervalFlag = flags.String("gateway-probe-interval", "5s", "How frequently the front-end checks that the Cluster Gateway is reachable. If it isn't, then the front-end reconnects automatically.")
+	var gatewayMaxBackoffFlag = flags.String("gateway-max-backoff", "30s", "The longest the front-end waits between attempts to reconnect to the Cluster Gateway. The wait doubles after each failed attempt.")
+	var proxyMaxConnectionsFlag = flags.Int("proxy-max-connections", 100, "Maximum number of websocket connections to the fake Cluster Gateway open at once. Further connections are refused. Zero means unlimited.")
+	var proxyIdleTimeoutFlag = flags.String("proxy-idle-timeout", "10m", "How long a websocket connection to the fake Cluster Gateway may go unused before it is closed. Zero means never.")
+	var kernelSpecQueryIntervalFlag = flags.String("kernel-spec-query-interval", "600s", "How frequently to query the Cluster for updated Jupyter kernel spec information.")
+	var workloadQueryIntervalFlag = flags.String("workload-query-interval", "2s", "How frequently to query the backend for the status of the workload run.")
+	var jupyterServerAddressFlag = flags.String("jupyter-server-address", "http://localhost:8888", "The IP address of the Jupyter Server.")
+	var jupyterServerTokenFlag = flags.String("jupyter-server-token", "", "Token with which to authenticate with the Jupyter Server, if it requires one.")
+	var workloadFlag = flags.String("workload", "", "Path to a YAML or JSON file containing the workload specification.")
+	var seedFlag = flags.Int64("seed", 0, "Seed for the random number generators used when spoofing the cluster.")
+	var migrationPolicyFlag = flags.String("migration-policy", string(domain.MigrationPolicyNone), "Policy with which kernel replicas are migrated automatically: none, threshold, drain, or chaos.")
+	var migrationIntervalFlag = flags.String("migration-interval", "30s", "How frequently the migration policy is evaluated.")
+	var migrationDryRunFlag = flags.Bool("migration-dry-run", false, "Record the migrations decided upon by the migration policy, but don't perform them.")
+	var migrationRateLimitFlag = flags.Int("migration-rate-limit", 10, "Maximum number of automatic migrations per minute. Zero means unlimited.")
+	var migrationHighWatermarkFlag = flags.Float64("migration-high-watermark", 0.85, "Utilization (of CPU, memory, or GPUs) above which the threshold policy moves replicas off of a node.")
+	var migrationLowWatermarkFlag = flags.Float64("migration-low-watermark", 0.5, "Utilization (of CPU, memory, and GPUs) below which the threshold policy moves replicas onto a node.")
+	var migrationDrainNodesFlag = flags.String("migration-drain-nodes", "", "Comma-separated list of the nodes from which the drain policy moves every replica.")
+	var migrationChaosProbabilityFlag = flags.Float64("migration-chaos-probability", 0.1, "Probability with which the chaos policy migrates a random replica each time it is evaluated.")
+
+	var gatewayTLSFlag = flags.Bool("gateway-tls", false, "Connect to the Cluster Gateway via wss:// rather than ws://. When spoofing the cluster, the fake Cluster Gateway serves TLS with --tls-cert.")
+	var tlsCertFlag = flags.String("tls-cert", "", "Certificate (PEM) with which the backend serves TLS, in which case the frontend connects via https:// and wss://.")
+	var tlsKeyFlag = flags.String("tls-key", "", "Private key (PEM) of --tls-cert.")
+	var tlsCACertFlag = flags.String("tls-ca-cert", "", "CA certificate (PEM) trusted, in addition to the system's, when the driver connects via TLS. Not used by browsers, which use their own trust store.")
+	var tlsClientCertFlag = flags.String("tls-client-cert", "", "Client certificate (PEM) presented when the driver connects to the Cluster Gateway via TLS. Not used by browsers.")
+	var tlsClientKeyFlag = flags.String("tls-client-key", "", "Private key (PEM) of --tls-client-cert.")
+	var authTokenFlag = flags.String("auth-token", "", "Bearer token that every request to the backend's /api endpoints must carry. Open the dashboard with ?token=<token>.")
+	var authHmacSecretFlag = flags.String("auth-hmac-secret", "", "Secret with which requests to the backend's /api endpoints may be signed, as an alternative to the bearer token.")
+	var allowedOriginsFlag = flags.String("allowed-origins", "", "Comma-separated list of the hosts (e.g., \"dashboard.example.com\" or \"*.example.com:8443\") of the cross-origin pages that may open websockets to the backend and the fake Cluster Gateway. Same-origin websockets are always allowed.")
+
+	var kubeNamespacesFlag = flags.String("kube-namespaces", "default", "Comma-separated list of the namespaces whose pods are displayed on the nodes. Empty means every namespace.")
+	var kubePodSelectorFlag = flags.String("kube-pod-selector", "", "Label selector (e.g., \"app=kernel\") restricting the pods that are displayed on the nodes.")
+	var kubeNodeSelectorFlag = flags.String("kube-node-selector", "", "Label selector restricting the nodes that are displayed.")
+	var kubeGpuResourceFlag = flags.String("kube-gpu-resource", "nvidia.com/gpu", "Extended resource through which the nodes advertise their GPUs. The GPUs allocated on a node are those requested by its pods (within --kube-namespaces).")
+	var kubeVgpuResourceFlag = flags.String("kube-vgpu-resource", "nvidia.com/gpu.shared", "Extended resource through which the nodes advertise their vGPUs, e.g., time-sliced GPUs. Empty if there are none.")
 
 	var kubeconfigFlag *string
 	if home := homedir.HomeDir(); home != "" {
-		kubeconfigFlag = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
+		kubeconfigFlag = flags.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
 	} else {
-		kubeconfigFlag = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
+		kubeconfigFlag = flags.String("kubeconfig", "", "absolute path to the kubeconfig file")
 	}
 
-	flag.Parse()
+	return func() (*Configuration, error) {
+		var spec *domain.Workload
+		if *workloadFlag != "" {
+			var err error
+			spec, err = workload.ParseFile(*workloadFlag)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load workload specification \"%s\": %w", *workloadFlag, err)
+			}
+		}
+
+		migrationPolicy := domain.MigrationPolicyName(*migrationPolicyFlag)
+		if !migrationPolicy.Valid() {
+			return nil, fmt.Errorf("unknown migration policy \"%s\"", *migrationPolicyFlag)
+		}
+
+		if _, _, err := net.SplitHostPort(*listenAddressFlag); err != nil {
+			return nil, fmt.Errorf("invalid listen address \"%s\": %w", *listenAddressFlag, err)
+		}
 
-	return &Configuration{
-		SpoofCluster:            *spoofFlag,
-		InCluster:               *inClusterFlag,
-		KernelQueryInterval:     *kernelQueryIntervalFlag,
-		NodeQueryInterval:       *nodeQueryIntervalFlag,
-		KubeConfig:              *kubeconfigFlag,
-		GatewayAddress:          *gatewayAddressFlag,
-		KernelSpecQueryInterval: *kernelSpecQueryIntervalFlag,
-		JupyterServerAddress:    *jupyterServerAddressFlag,
-		Valid:                   true,
+		if timeout, err := time.ParseDuration(*rpcTimeoutFlag); err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid RPC timeout \"%s\": must be a positive duration", *rpcTimeoutFlag)
+		}
+
+		if interval, err := time.ParseDuration(*gatewayProbeIntervalFlag); err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid gateway probe interval \"%s\": must be a positive duration", *gatewayProbeIntervalFlag)
+		}
+
+		if backoff, err := time.ParseDuration(*gatewayMaxBackoffFlag); err != nil || backoff <= 0 {
+			return nil, fmt.Errorf("invalid gateway backoff \"%s\": must be a positive duration", *gatewayMaxBackoffFlag)
+		}
+
+		if *proxyMaxConnectionsFlag < 0 {
+			return nil, fmt.Errorf("invalid proxy connection limit %d: cannot be negative", *proxyMaxConnectionsFlag)
+		}
+
+		if timeout, err := time.ParseDuration(*proxyIdleTimeoutFlag); err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid proxy idle timeout \"%s\": must be a non-negative duration", *proxyIdleTimeoutFlag)
+		}
+
+		if (*tlsCertFlag == "") != (*tlsKeyFlag == "") {
+			return nil, fmt.Errorf("--tls-cert and --tls-key must be specified together")
+		}
+
+		if (*tlsClientCertFlag == "") != (*tlsClientKeyFlag == "") {
+			return nil, fmt.Errorf("--tls-client-cert and --tls-client-key must be specified together")
+		}
+
+		if *gatewayTLSFlag && *spoofFlag && *tlsCertFlag == "" {
+			return nil, fmt.Errorf("serving the fake Cluster Gateway via TLS requires --tls-cert and --tls-key")
+		}
+
+		allowedOrigins := splitList(*allowedOriginsFlag)
+		for _, pattern := range allowedOrigins {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid allowed origin \"%s\": %w", pattern, err)
+			}
+		}
+
+		if interval, err := time.ParseDuration(*migrationIntervalFlag); err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid migration interval \"%s\": must be a positive duration", *migrationIntervalFlag)
+		}
+
+		if *migrationLowWatermarkFlag > *migrationHighWatermarkFlag {
+			return nil, fmt.Errorf("the migration low watermark (%v) cannot exceed the high watermark (%v)", *migrationLowWatermarkFlag, *migrationHighWatermarkFlag)
+		}
+
+		return &Configuration{
+			SpoofCluster:              *spoofFlag,
+			InCluster:                 *inClusterFlag,
+			ListenAddress:             *listenAddressFlag,
+			KernelQueryInterval:       *kernelQueryIntervalFlag,
+			NodeQueryInterval:         *nodeQueryIntervalFlag,
+			KubeConfig:                *kubeconfigFlag,
+			KubeNamespaces:            splitList(*kubeNamespacesFlag),
+			KubePodSelector:           *kubePodSelectorFlag,
+			KubeNodeSelector:          *kubeNodeSelectorFlag,
+			KubeGpuResource:           *kubeGpuResourceFlag,
+			KubeVgpuResource:          *kubeVgpuResourceFlag,
+			GatewayAddress:            *gatewayAddressFlag,
+			RpcTimeout:                *rpcTimeoutFlag,
+			GatewayProbeInterval:      *gatewayProbeIntervalFlag,
+			GatewayMaxBackoff:         *gatewayMaxBackoffFlag,
+			ProxyMaxConnections:       *proxyMaxConnectionsFlag,
+			ProxyIdleTimeout:          *proxyIdleTimeoutFlag,
+			GatewayTLS:                *gatewayTLSFlag,
+			TLSCert:                   *tlsCertFlag,
+			TLSKey:                    *tlsKeyFlag,
+			TLSCACert:                 *tlsCACertFlag,
+			TLSClientCert:             *tlsClientCertFlag,
+			TLSClientKey:              *tlsClientKeyFlag,
+			AuthToken:                 *authTokenFlag,
+			AuthHmacSecret:            *authHmacSecretFlag,
+			AllowedOrigins:            allowedOrigins,
+			KernelSpecQueryInterval:   *kernelSpecQueryIntervalFlag,
+			WorkloadQueryInterval:     *workloadQueryIntervalFlag,
+			JupyterServerAddress:      *jupyterServerAddressFlag,
+			JupyterServerToken:        *jupyterServerTokenFlag,
+			WorkloadPath:              *workloadFlag,
+			Workload:                  spec,
+			Seed:                      *seedFlag,
+			MigrationPolicy:           migrationPolicy,
+			MigrationInterval:         *migrationIntervalFlag,
+			MigrationDryRun:           *migrationDryRunFlag,
+			MigrationRateLimit:        *migrationRateLimitFlag,
+			MigrationHighWatermark:    *migrationHighWatermarkFlag,
+			MigrationLowWatermark:     *migrationLowWatermarkFlag,
+			MigrationDrainNodes:       splitList(*migrationDrainNodesFlag),
+			MigrationChaosProbability: *migrationChaosProbabilityFlag,
+			Valid:                     true,
+		}, nil
 	}
 }
 
+// Split a comma-separated list, ignoring empty entries.
+func splitList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
 // func GetOptions() *Configuration {
 // 	var yamlPath string
 // 	flag.StringVar(&yamlPath, "config", "config.yaml", "Path to the YAML configuration file.")
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GatewayConnectionState string
+
+const (
+	GatewayDisconnected GatewayConnectionState = "disconnected" // Not connected, and not attempting to connect.
+	GatewayConnecting   GatewayConnectionState = "connecting"   // Connecting to the Cluster Gateway for the first time.
+	GatewayConnected    GatewayConnectionState = "connected"
+	GatewayReconnecting GatewayConnectionState = "reconnecting" // The connection was lost, and is being re-established automatically.
+)
+
+// Published whenever the state of the connection to the Cluster Gateway changes, or a reconnection attempt fails.
+type GatewayConnectionEvent struct {
+	State     GatewayConnectionState `json:"state"`
+	Address   string                 `json:"address"`
+	Attempt   int                    `json:"attempt"`         // The number of reconnection attempts made so far. Zero unless reconnecting.
+	RetryIn   time.Duration          `json:"retry_in"`        // How long until the next reconnection attempt. Zero unless reconnecting.
+	Error     string                 `json:"error,omitempty"` // Why the connection was lost, or why the last attempt failed.
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+func (e *GatewayConnectionEvent) String() string {
+	out, err := json.Marshal(e)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
+
+// Reports the state of the connection to the Cluster Gateway.
+type GatewayConnectionMonitor interface {
+	// Return the most recent connection event.
+	ConnectionState() *GatewayConnectionEvent
+
+	// Subscribe to connection events. The handler should return false once it no longer wishes to receive events.
+	SubscribeToConnectionEvents(string, func(*GatewayConnectionEvent) bool)
+
+	// Unsubscribe from connection events.
+	UnsubscribeFromConnectionEvents(string)
+}
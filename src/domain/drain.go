@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Operations supported by the backend's KUBERNETES_NODES_ENDPOINT, in addition to requesting and watching the nodes.
+// Each expects a NodeCordonRequest, and responds with a NodeCordonResponse.
+const (
+	NodeOpCordon   = "cordon-node"   // Mark the node as unschedulable, so that no further replicas are placed on it.
+	NodeOpUncordon = "uncordon-node" // Mark the node as schedulable again.
+)
+
+type NodeCordonRequest struct {
+	NodeId string `json:"node_id"`
+}
+
+// Sent by the backend once a node has been cordoned or uncordoned.
+type NodeCordonResponse struct {
+	NodeId        string `json:"node_id"`
+	Unschedulable bool   `json:"unschedulable"`
+}
+
+type DrainStepKind string
+
+const (
+	DrainStepCordon  DrainStepKind = "cordon"  // Cordon the node via the backend.
+	DrainStepMigrate DrainStepKind = "migrate" // Migrate one of the node's replicas to another node.
+	DrainStepRemove  DrainStepKind = "remove"  // Remove the node's host from the cluster via the Cluster Gateway.
+)
+
+type DrainStepState string
+
+const (
+	DrainStepPending   DrainStepState = "pending"
+	DrainStepRunning   DrainStepState = "running"
+	DrainStepSucceeded DrainStepState = "succeeded"
+	DrainStepFailed    DrainStepState = "failed"
+	DrainStepSkipped   DrainStepState = "skipped" // Not attempted, as an earlier step failed.
+)
+
+// A single step of draining a node.
+type DrainStep struct {
+	Kind        DrainStepKind  `json:"kind"`
+	Description string         `json:"description"`
+	KernelId    string         `json:"kernel_id,omitempty"` // Set for migrations.
+	ReplicaId   int32          `json:"replica_id"`          // Set for migrations.
+	State       DrainStepState `json:"state"`
+	StartedAt   *time.Time     `json:"started_at,omitempty"`
+	FinishedAt  *time.Time     `json:"finished_at,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+type DrainState string
+
+const (
+	DrainRunning   DrainState = "running"
+	DrainCompleted DrainState = "completed" // Every step succeeded.
+	DrainFailed    DrainState = "failed"    // One of the steps failed, and the drain was stopped.
+)
+
+// The progress of taking a node out of service: cordoning it, migrating each of its replicas to other nodes, and
+// optionally removing its host from the cluster. The migration steps are added once the node has been cordoned.
+type DrainStatus struct {
+	NodeId     string       `json:"node_id"`
+	State      DrainState   `json:"state"`
+	Steps      []*DrainStep `json:"steps"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+	Error      string       `json:"error,omitempty"` // The error of the step that failed, if any.
+}
+
+func (s *DrainStatus) String() string {
+	out, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
+
+// Takes Kubernetes nodes out of service, and puts them back into service.
+type NodeDrainer interface {
+	// Mark the node as unschedulable (if cordon is true) or as schedulable again (if false).
+	CordonNode(nodeId string, cordon bool) error
+
+	// Cordon the node, and then migrate every replica on it to other nodes, stopping at the first failure. If removeHost
+	// is true, then the node's host is finally removed from the cluster. The handler, if non-nil, is passed a copy of the
+	// status whenever it changes. Returns the final status, along with the error of the step that failed, if any.
+	DrainNode(nodeId string, removeHost bool, onProgress func(*DrainStatus)) (*DrainStatus, error)
+}
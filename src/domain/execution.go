@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Operation supported by the backend's EXECUTE_ENDPOINT. Expects an ExecuteRequest. Each ExecutionOutput is streamed as an
+// intermediate response as it is produced, and the final response is the ExecuteReply.
+const ExecuteOp = "execute"
+
+var (
+	ErrExecutionFailed = errors.New("cell execution did not complete successfully")
+
+	// The Cluster Gateway has no way of routing an execution to a single replica of a kernel, so such requests are
+	// rejected rather than being executed by every replica.
+	ErrReplicaExecutionUnsupported = errors.New("executing code on a single replica of a kernel is not supported by the Cluster Gateway")
+)
+
+// Code to be executed by a kernel, or by a single replica of a distributed kernel.
+type ExecuteRequest struct {
+	KernelId  string `json:"kernel_id"`
+	ReplicaId *int32 `json:"replica_id,omitempty"` // If set, then only this replica of the kernel executes the code. Not yet supported (see ErrReplicaExecutionUnsupported).
+	Code      string `json:"code"`
+}
+
+func (r *ExecuteRequest) String() string {
+	if r.ReplicaId != nil {
+		return fmt.Sprintf("replica %d of kernel %s", *r.ReplicaId, r.KernelId)
+	}
+
+	return fmt.Sprintf("kernel %s", r.KernelId)
+}
+
+type ExecutionOutputType string
+
+const (
+	ExecutionOutputStream  ExecutionOutputType = "stream"         // Text written to stdout or stderr.
+	ExecutionOutputResult  ExecutionOutputType = "execute_result" // The value of the last expression of the cell.
+	ExecutionOutputDisplay ExecutionOutputType = "display_data"   // Rich output, e.g., a plot.
+	ExecutionOutputError   ExecutionOutputType = "error"          // An exception raised by the code.
+)
+
+// Output produced by a kernel while it executes code, as published on its IOPub channel.
+type ExecutionOutput struct {
+	Type ExecutionOutputType    `json:"type"`
+	Name string                 `json:"name,omitempty"` // For streams, either "stdout" or "stderr".
+	Text string                 `json:"text"`           // The output as plain text. For errors, the traceback.
+	Data map[string]interface{} `json:"data,omitempty"` // For results and display data, the output keyed by MIME type.
+}
+
+// The kernel's reply once it has finished executing code.
+type ExecuteReply struct {
+	Status         string   `json:"status"` // One of "ok", "error", or "aborted".
+	ExecutionCount int      `json:"execution_count"`
+	ErrorName      string   `json:"ename,omitempty"`
+	ErrorValue     string   `json:"evalue,omitempty"`
+	Traceback      []string `json:"traceback,omitempty"`
+}
+
+// Return nil if the code was executed successfully, or else an error wrapping ErrExecutionFailed.
+func (r *ExecuteReply) Err() error {
+	if r.Status == "ok" {
+		return nil
+	}
+
+	if r.ErrorName != "" {
+		return fmt.Errorf("%w: status=%s, %s: %s", ErrExecutionFailed, r.Status, r.ErrorName, r.ErrorValue)
+	}
+
+	return fmt.Errorf("%w: status=%s", ErrExecutionFailed, r.Status)
+}
+
+func (r *ExecuteReply) String() string {
+	if r.Status == "ok" {
+		return fmt.Sprintf("ok [%d]", r.ExecutionCount)
+	}
+
+	return fmt.Sprintf("%s: %s", r.Status, strings.TrimSpace(r.ErrorName+" "+r.ErrorValue))
+}
+
+// Executes code on kernels.
+type CodeExecutor interface {
+	// Execute the code and wait for the kernel's reply. Each output is passed to the handler, if any, as it is produced.
+	// An error is returned only if the code could not be executed; if the code itself failed, then see ExecuteReply.Err.
+	Execute(ctx context.Context, req *ExecuteRequest, onOutput func(*ExecutionOutput)) (*ExecuteReply, error)
+}
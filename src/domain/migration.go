@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Decides which kernel replicas the driver migrates automatically, based on the utilization of the Kubernetes nodes.
+type MigrationPolicyName string
+
+const (
+	MigrationPolicyNone      MigrationPolicyName = "none"      // Never migrate replicas automatically.
+	MigrationPolicyThreshold MigrationPolicyName = "threshold" // Move replicas off of nodes whose utilization exceeds the high watermark, onto nodes below the low watermark.
+	MigrationPolicyDrain     MigrationPolicyName = "drain"     // Move every replica off of the specified nodes.
+	MigrationPolicyChaos     MigrationPolicyName = "chaos"     // Occasionally move a random replica to a random node.
+)
+
+var MigrationPolicies = []MigrationPolicyName{MigrationPolicyNone, MigrationPolicyThreshold, MigrationPolicyDrain, MigrationPolicyChaos}
+
+func (p MigrationPolicyName) Valid() bool {
+	for _, policy := range MigrationPolicies {
+		if p == policy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// A migration decided upon by a migration policy, and its outcome.
+type MigrationDecision struct {
+	Time         time.Time           `json:"time"`
+	Policy       MigrationPolicyName `json:"policy"`
+	KernelId     string              `json:"kernel_id"`
+	ReplicaId    int32               `json:"replica_id"`
+	SourceNodeId string              `json:"source_node_id"`
+	TargetNodeId string              `json:"target_node_id"`
+	Reason       string              `json:"reason"`
+	DryRun       bool                `json:"dry_run"`         // If true, then the migration was not actually performed.
+	RateLimited  bool                `json:"rate_limited"`    // If true, then the migration was skipped because too many migrations were performed recently.
+	Error        string              `json:"error,omitempty"` // Set if the migration was attempted, but failed.
+}
+
+func (d *MigrationDecision) String() string {
+	out, err := json.Marshal(d)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
+
+type MigrationState string
+
+const (
+	MigrationRequested    MigrationState = "requested"    // The request has been sent to the Cluster Gateway, which hasn't responded yet.
+	MigrationAcknowledged MigrationState = "acknowledged" // The Cluster Gateway has responded, but the replica hasn't been seen on its new node yet.
+	MigrationCompleted    MigrationState = "completed"    // The replica has been seen on its new node.
+	MigrationFailed       MigrationState = "failed"
+)
+
+// The progress of a single migration, from the request to the replica being seen on its new node.
+type MigrationRecord struct {
+	Id           string         `json:"id"`
+	KernelId     string         `json:"kernel_id"`
+	ReplicaId    int32          `json:"replica_id"`
+	SourceNodeId string         `json:"source_node_id"`           // The node hosting the replica when the migration was requested, if known.
+	TargetNodeId string         `json:"target_node_id,omitempty"` // The node requested. Empty if the Cluster Gateway was left to choose one.
+	State        MigrationState `json:"state"`
+	RequestedAt  time.Time      `json:"requested_at"`
+	RespondedAt  *time.Time     `json:"responded_at,omitempty"`
+	Hostname     string         `json:"hostname,omitempty"` // From the MigrateKernelResponse.
+	ObservedAt   *time.Time     `json:"observed_at,omitempty"`
+	NodeId       string         `json:"node_id,omitempty"` // The node on which the replica was seen after the migration.
+	Error        string         `json:"error,omitempty"`
+}
+
+// Return how long the migration took, from the request until the replica was seen on its new node (or the migration
+// failed). Returns false if it hasn't finished yet.
+func (r *MigrationRecord) Duration() (time.Duration, bool) {
+	switch {
+	case r.ObservedAt != nil:
+		return r.ObservedAt.Sub(r.RequestedAt), true
+	case r.State == MigrationFailed && r.RespondedAt != nil:
+		return r.RespondedAt.Sub(r.RequestedAt), true
+	default:
+		return 0, false
+	}
+}
+
+func (r *MigrationRecord) String() string {
+	out, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
+
+// Tracks the migrations performed by the driver, whether requested by the user or by a migration policy.
+type MigrationTracker interface {
+	Migrations() []*MigrationRecord                         // Return every migration, oldest first.
+	SubscribeToMigrations(string, func([]*MigrationRecord)) // Subscribe to the progress of the migrations. The handler is passed every migration whenever one of them changes.
+	UnsubscribeFromMigrations(string)                       // Unsubscribe from the progress of the migrations.
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// The version of the protocol spoken over the backend's websocket endpoints. Requests of any other version are rejected.
+const ProtocolVersion = 1
+
+type ErrorCode string
+
+// The codes of the errors with which the backend responds to requests that fail.
+const (
+	ErrCodeBadRequest         ErrorCode = "bad_request"         // The request is malformed, e.g., it has no op, or its payload is missing a required field.
+	ErrCodeUnsupportedVersion ErrorCode = "unsupported_version" // The request's version is not ProtocolVersion.
+	ErrCodeUnknownOp          ErrorCode = "unknown_op"          // The endpoint doesn't support the request's op.
+	ErrCodeNotFound           ErrorCode = "not_found"           // The resource to which the request refers doesn't exist.
+	ErrCodeFailedPrecondition ErrorCode = "failed_precondition" // The request cannot be performed in the current state, e.g., pausing a run that isn't running.
+	ErrCodeUnavailable        ErrorCode = "unavailable"         // Something on which the request depends, e.g., Kubernetes or the Jupyter Server, could not be reached.
+	ErrCodeInternal           ErrorCode = "internal"
+)
+
+// A request sent to one of the backend's websocket endpoints. Any number of requests may be sent over the same
+// websocket, without waiting for the responses to the previous ones.
+type Request struct {
+	Version int             `json:"version"`
+	Id      string          `json:"id"` // Chosen by the client, and echoed in each response to the request.
+	Op      string          `json:"op"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func (r *Request) String() string {
+	out, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
+
+// A response to a Request. Most operations send a single, final response. Streaming operations, e.g., executing code
+// or watching the nodes, first send any number of intermediate responses. The responses to different requests sent
+// over the same websocket may be interleaved.
+type Response struct {
+	Version int             `json:"version"`
+	Id      string          `json:"id"` // The ID of the request.
+	Op      string          `json:"op"` // The op of the request.
+	Final   bool            `json:"final"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Error   *ResponseError  `json:"error,omitempty"` // Set if the request failed, in which case the response is final.
+}
+
+func (r *Response) String() string {
+	out, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
+
+// Sent by the backend in place of a payload if a request fails.
+type ResponseError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+func NewResponseError(code ErrorCode, format string, args ...interface{}) *ResponseError {
+	return &ResponseError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+// Return the code of the ResponseError in the error's chain, or ErrCodeInternal if there isn't one.
+// Returns the empty string if the error is nil.
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.Code
+	}
+
+	return ErrCodeInternal
+}
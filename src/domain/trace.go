@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+)
+
+// A recorded notebook trace. All times are relative to the start of the trace.
+type Trace struct {
+	Sessions []*TraceSession `json:"sessions"`
+}
+
+// Total duration of the trace, i.e., the time at which the last session ends.
+func (t *Trace) Duration() time.Duration {
+	var end time.Duration
+	for _, session := range t.Sessions {
+		if session.End > end {
+			end = session.End
+		}
+	}
+
+	return end
+}
+
+// A single session recorded in a trace.
+type TraceSession struct {
+	Id    string        `json:"id"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	Cells []*TraceCell  `json:"cells"` // Sorted by start time.
+}
+
+// The peak resource usage of any of the session's cells. Used when creating the session's kernel.
+func (s *TraceSession) PeakResources() *gateway.ResourceSpec {
+	peak := &gateway.ResourceSpec{}
+	for _, cell := range s.Cells {
+		peak.Cpu = max(peak.Cpu, cell.Cpu)
+		peak.Memory = max(peak.Memory, cell.Memory)
+		peak.Gpu = max(peak.Gpu, cell.Gpu)
+	}
+
+	return peak
+}
+
+// A single cell execution recorded in a trace.
+type TraceCell struct {
+	Start  time.Duration `json:"start"`
+	End    time.Duration `json:"end"`
+	Cpu    int32         `json:"cpu"`    // In 1/100 core.
+	Memory int32         `json:"memory"` // In MB.
+	Gpu    int32         `json:"gpu"`    // In 1/100 GPU.
+}
+
+// Describes how a recorded trace should be replayed.
+// A workload with a trace is replayed from the trace rather than being generated from its tenants.
+type WorkloadTrace struct {
+	Path       string  `yaml:"path" json:"path"`               // Path to the CSV or JSON Lines trace file. Relative paths are resolved against the directory of the workload specification.
+	Speedup    float64 `yaml:"speedup" json:"speedup"`         // Factor by which to compress the trace's timeline. 1 (or 0) replays the trace in real time.
+	KernelSpec string  `yaml:"kernel-spec" json:"kernel-spec"` // Name of the Jupyter kernel spec used for every replayed session.
+
+	Trace *Trace `yaml:"-" json:"-"` // The trace loaded from Path.
+}
+
+// Summarizes how far a replay drifted from the timestamps of the trace it was replaying.
+type DriftReport struct {
+	Samples int           `json:"samples"`
+	Mean    time.Duration `json:"mean"`
+	P50     time.Duration `json:"p50"`
+	P99     time.Duration `json:"p99"`
+	Max     time.Duration `json:"max"`
+}
+
+func (r *DriftReport) String() string {
+	out, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
+
+// Summarize the drift of the given events. Events without drift information are ignored.
+func SummarizeDrift(events []*WorkloadEvent) *DriftReport {
+	drifts := make([]time.Duration, 0, len(events))
+	for _, event := range events {
+		if event.Drift != nil {
+			drifts = append(drifts, *event.Drift)
+		}
+	}
+
+	report := &DriftReport{Samples: len(drifts)}
+	if len(drifts) == 0 {
+		return report
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i] < drifts[j]
+	})
+
+	var total time.Duration
+	for _, drift := range drifts {
+		total += drift
+	}
+
+	report.Mean = total / time.Duration(len(drifts))
+	report.P50 = drifts[(len(drifts)-1)*50/100]
+	report.P99 = drifts[(len(drifts)-1)*99/100]
+	report.Max = drifts[len(drifts)-1]
+
+	return report
+}
@@ -3,11 +3,11 @@ package domain
 import (
 	"encoding/json"
 	"errors"
-	"net/http"
+	"strings"
 	"time"
 
 	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
-	"nhooyr.io/websocket"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
 )
 
 const (
@@ -19,6 +19,20 @@ const (
 
 	// Used internally (by the frontend) to get the current set of Jupyter kernel specs from the backend.
 	KERNEL_SPEC_ENDPOINT = "/api/kernelspec"
+
+	// Used internally (by the frontend) to start, pause, resume, stop, and query the status of the workload run managed by the backend.
+	WORKLOAD_ENDPOINT = "/api/workload"
+
+	// Used internally (by the frontend) to execute code on a kernel, or on one of its replicas, via the backend.
+	EXECUTE_ENDPOINT = "/api/execute"
+)
+
+// Operations supported by the backend's endpoints. See also NodeOpCordon, WorkloadOpStart, ExecuteOp, etc.
+const (
+	ConfigOpRequest     = "request-config"       // Supported by SYSTEM_CONFIG_ENDPOINT. Responds with the configuration.
+	KernelSpecOpRequest = "request-kernel-specs" // Supported by KERNEL_SPEC_ENDPOINT. Responds with the kernel specs.
+	NodeOpRequest       = "request-nodes"        // Supported by KUBERNETES_NODES_ENDPOINT. Responds with the nodes, keyed by ID.
+	NodeOpWatch         = "watch-nodes"          // Supported by KUBERNETES_NODES_ENDPOINT. Streams a NodeEvent per intermediate response.
 )
 
 var (
@@ -33,17 +47,62 @@ type ErrorHandler interface {
 	HandleError(error, string)
 }
 
+// Creates and terminates distributed kernels via the Cluster Gateway.
+type KernelManager interface {
+	CreateKernel(*KernelSpec, *gateway.ResourceSpec) (string, *gateway.KernelConnectionInfo, error) // Create a distributed kernel from the given kernel spec. Returns the ID of the new kernel and its connection info.
+	TerminateKernel(string) error                                                                   // Terminate the specified kernel, along with all of its replicas.
+}
+
 type WorkloadDriver interface {
 	// Return true if we're connected to the Cluster Gateway.
 	ConnectedToGateway() bool
 
+	// Report the state of the connection to the Cluster Gateway, which is re-established automatically if it is lost.
+	GatewayConnectionMonitor
+
 	KernelSpecProvider() KernelSpecProvider // Return the entity responsible for providing the up-to-date list of Jupyter kernel specs.
 	KernelProvider() KernelProvider         // Return the entity responsible for providing the up-to-date list of Jupyter kernels.
 	NodeProvider() NodeProvider             // Return the entity responsible for providing the up-to-date list of Kubernetes nodes.
 
 	// Tell the Cluster Gateway to migrate a particular replica.
 	MigrateKernelReplica(*gateway.MigrationRequest) error
-	DialGatewayGRPC(string) error // Attempt to connect to the Cluster Gateway's gRPC server using the provided address. Returns an error if connection failed, or nil on success. This should NOT be called from the UI goroutine.
+	MigrationTracker() MigrationTracker       // Return the entity tracking the progress of every migration.
+	MigrationDecisions() []*MigrationDecision // Return the migrations decided upon by the configured migration policy, if any, including those that were not performed.
+	DialGatewayGRPC(string) error             // Connect to the Cluster Gateway's gRPC server using the provided address, replacing the current connection, if any. Returns an error if connection failed, or nil on success. This should NOT be called from the UI goroutine.
+
+	// Create and terminate distributed kernels via the Cluster Gateway.
+	KernelManager
+
+	TerminateKernels([]string) []*KernelOperationResult // Terminate each of the specified kernels, reporting whether each was terminated.
+	InterruptKernel(string) error                       // Interrupt the cell, if any, that the specified kernel is executing.
+	InterruptKernels([]string) []*KernelOperationResult // Interrupt each of the specified kernels, reporting whether each was interrupted.
+
+	// Execute code on a kernel, or on one of its replicas, via the backend.
+	CodeExecutor
+
+	// Cordon and drain Kubernetes nodes.
+	NodeDrainer
+
+	StartWorkload(*Workload) error    // Begin driving the given workload in the background. Returns ErrWorkloadAlreadyRunning if a workload is already active.
+	PauseWorkload() error             // Pause the active workload. No new sessions are created and no new cells are submitted until it is resumed.
+	ResumeWorkload() error            // Resume the paused workload.
+	StopWorkload() error              // Abort the active workload. Sessions created by the workload are torn down.
+	WaitWorkload() error              // Block until the active workload completes, returning any error it encountered.
+	WorkloadEvents() []*WorkloadEvent // Return the events recorded by the most recent workload.
+	WorkloadRun() *WorkloadRun        // Return the status of the most recent workload run, or nil if no workload has been started.
+	Metrics() *metrics.Recorder       // Return the recorder of the latency of every request issued by the driver and its providers.
+
+	WorkloadRunProvider() WorkloadRunProvider // Return the entity responsible for providing the status of, and controlling, the workload run managed by the backend.
+}
+
+// The outcome of an operation on one of several kernels.
+type KernelOperationResult struct {
+	KernelId string
+	Err      error // Nil if the operation succeeded.
+}
+
+func (r *KernelOperationResult) Succeeded() bool {
+	return r.Err == nil
 }
 
 type WorkloadDriverOptions struct {
@@ -54,13 +113,15 @@ type ResourceProvider[resource any] interface {
 	Count() int32          // Number of currently-active resources.
 	Resources() []resource // List of currently-active resources.
 	RefreshResources()     // Manually/explicitly refresh the set of active resources from the Cluster Gateway.
-	Start(string) error    // Start querying for resources periodically.
+	Start(string) error    // Start watching for changes to the resources, or querying for them periodically.
 
 	RefreshOccurred()                                   // Called automatically when a refresh occurred; informs the subscribers.
 	QueryResources()                                    // Call in its own goroutine; polls for resources.
 	SubscribeToRefreshes(string, func([]resource) bool) // Subscribe to Kernel refreshes.
 	UnsubscribeFromRefreshes(string)                    // Unsubscribe from Kernel refreshes.
-	DialGatewayGRPC(string) error                       // Attempt to connect to the Cluster Gateway's gRPC server using the provided address. Returns an error if connection failed, or nil on success. This should NOT be called from the UI goroutine.
+
+	SubscribeToChanges(string, func([]*ResourceChange[resource]) bool) // Subscribe to the changes between successive refreshes. Only called if something changed.
+	UnsubscribeFromChanges(string)                                     // Unsubscribe from changes.
 }
 
 type KernelProvider interface {
@@ -75,6 +136,15 @@ type NodeProvider interface {
 	ResourceProvider[*KubernetesNode]
 }
 
+type WorkloadRunProvider interface {
+	ResourceProvider[*WorkloadRun]
+
+	StartRun() error  // Ask the backend to start the workload run.
+	PauseRun() error  // Ask the backend to pause the workload run.
+	ResumeRun() error // Ask the backend to resume the workload run.
+	StopRun() error   // Ask the backend to abort the workload run.
+}
+
 type KubernetesNode struct {
 	NodeId          string           `json:"Nodes"`
 	Pods            []*KubernetesPod `json:"Pods"`
@@ -88,6 +158,7 @@ type KubernetesNode struct {
 	AllocatedMemory float64          `json:"AllocatedMemory"`
 	AllocatedGPUs   float64          `json:"AllocatedGPUs"`
 	AllocatedVGPUs  float64          `json:"AllocatedVGPUs"`
+	Unschedulable   bool             `json:"Unschedulable"` // True if the node has been cordoned, in which case no further replicas are placed on it.
 
 	Valid bool `json:"Valid"` // Used to determine if the struct was sent/received correctly over the network.
 }
@@ -119,28 +190,58 @@ func (kp *KubernetesPod) String() string {
 	return string(out)
 }
 
-type ErrorMessage struct {
-	ErrorMessage string `json:"ErrorMessage"`
-	Valid        bool   `json:"Valid"` // Used to determine if the struct was sent/received correctly over the network.
+type ResourceEventType string
+
+// Types of the events streamed by a watch, e.g., of the kernels or of the Kubernetes nodes.
+// A watch begins with a ResourceAdded event for every current resource, followed by a ResourceSynced event.
+const (
+	ResourceAdded   ResourceEventType = "added"
+	ResourceUpdated ResourceEventType = "updated"
+	ResourceRemoved ResourceEventType = "removed"
+	ResourceSynced  ResourceEventType = "synced" // Every current resource has been listed.
+)
+
+// A change to a single resource between two successive refreshes of a ResourceProvider.
+type ResourceChange[Resource any] struct {
+	Type     ResourceEventType // ResourceAdded, ResourceUpdated, or ResourceRemoved.
+	Id       string
+	Previous Resource       // The resource before the change. The zero value if it was added.
+	Current  Resource       // The resource after the change. The zero value if it was removed.
+	Fields   []*FieldChange // For ResourceUpdated changes, the fields that changed, e.g., "Replicas[1].NodeId".
 }
 
-func (m *ErrorMessage) String() string {
-	out, err := json.Marshal(m)
-	if err != nil {
-		panic(err)
+// Return true if the given field, or any field within it, changed. E.g., "Replicas" matches "Replicas[1].NodeId".
+func (c *ResourceChange[Resource]) Changed(field string) bool {
+	for _, change := range c.Fields {
+		if change.Field == field || strings.HasPrefix(change.Field, field+".") || strings.HasPrefix(change.Field, field+"[") {
+			return true
+		}
 	}
 
-	return string(out)
+	return false
 }
 
-type BackendHttpHandler interface {
-	http.Handler
+// A single field of a resource that changed.
+type FieldChange struct {
+	Field    string      `json:"field"`
+	Previous interface{} `json:"previous"`
+	Current  interface{} `json:"current"`
+}
 
-	// Write an error back to the client.
-	WriteError(*websocket.Conn, string)
+// A change to the set of Kubernetes nodes, sent by the backend to clients watching the nodes.
+type NodeEvent struct {
+	Type   ResourceEventType `json:"type"`
+	NodeId string            `json:"node_id"`
+	Node   *KubernetesNode   `json:"node,omitempty"` // Not set for ResourceSynced events. For ResourceRemoved events, the node as it was last seen.
+}
 
-	// Handle a message/request from the front-end.
-	HandleRequest(*websocket.Conn, *http.Request, map[string]interface{})
+func (e *NodeEvent) String() string {
+	out, err := json.Marshal(e)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
 }
 
 type KernelSpec struct {
@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+)
+
+const (
+	// The current version of the workload specification format.
+	WorkloadSpecVersion = "v1"
+
+	ArrivalProcessConstant = "constant" // Arrivals occur at fixed intervals.
+	ArrivalProcessPoisson  = "poisson"  // Arrivals follow a Poisson process, i.e., exponentially-distributed inter-arrival times.
+	ArrivalProcessBursty   = "bursty"   // Arrivals follow a two-state Markov-modulated Poisson process (MMPP) that alternates between idle and bursty periods.
+	ArrivalProcessDiurnal  = "diurnal"  // Arrivals follow a Poisson process whose rate varies sinusoidally, e.g., over the course of a day.
+	ArrivalProcessStep     = "step"     // Arrivals follow a Poisson process whose rate changes at fixed points in time.
+	ArrivalProcessRamp     = "ramp"     // Arrivals follow a Poisson process whose rate changes linearly from Rate to EndRate.
+
+	WorkloadEventSessionCreated WorkloadEventType = "session-created"
+	WorkloadEventCellSubmitted  WorkloadEventType = "cell-submitted"
+	WorkloadEventCellCompleted  WorkloadEventType = "cell-completed"
+	WorkloadEventSessionStopped WorkloadEventType = "session-stopped"
+	WorkloadEventError          WorkloadEventType = "error"
+)
+
+var (
+	ErrWorkloadAlreadyRunning = errors.New("a workload is already running")
+	ErrNoActiveWorkload       = errors.New("there is no active workload")
+	ErrWorkloadNotRunning     = errors.New("the workload is not running")
+	ErrWorkloadNotPaused      = errors.New("the workload is not paused")
+)
+
+// A declarative description of a workload to be driven against the cluster.
+type Workload struct {
+	Version  string            `yaml:"version" json:"version"`   // Version of the specification format. Currently, this must be WorkloadSpecVersion.
+	Name     string            `yaml:"name" json:"name"`         // Human-readable name of the workload.
+	Seed     int64             `yaml:"seed" json:"seed"`         // Seed used for any randomness in the workload, so that runs are repeatable.
+	Duration time.Duration     `yaml:"duration" json:"duration"` // Maximum duration of the workload, after which it is aborted. Zero means no limit.
+	Tenants  []*WorkloadTenant `yaml:"tenants" json:"tenants"`   // The tenants participating in the workload.
+	Trace    *WorkloadTrace    `yaml:"trace" json:"trace"`       // If set, the workload is replayed from a recorded trace instead of being generated from Tenants.
+}
+
+func (w *Workload) String() string {
+	out, err := json.Marshal(w)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
+
+// Return a hex-encoded SHA-256 hash of the workload, including the contents of its trace (if any).
+// Two specifications that describe the same workload have the same hash, regardless of how they are formatted.
+func (w *Workload) Hash() string {
+	hash := sha256.New()
+	hash.Write([]byte(w.String()))
+
+	if w.Trace != nil && w.Trace.Trace != nil {
+		trace, err := json.Marshal(w.Trace.Trace)
+		if err != nil {
+			panic(err)
+		}
+		hash.Write(trace)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Total number of sessions that will be created over the course of the workload.
+func (w *Workload) NumSessions() int {
+	if w.Trace != nil && w.Trace.Trace != nil {
+		return len(w.Trace.Trace.Sessions)
+	}
+
+	total := 0
+	for _, tenant := range w.Tenants {
+		total += tenant.NumSessions
+	}
+
+	return total
+}
+
+// A tenant creates one or more identical sessions, each of which executes the tenant's cells in order.
+type WorkloadTenant struct {
+	Name        string                `yaml:"name" json:"name"`                 // Name of the tenant. Used to derive the IDs of the tenant's sessions.
+	NumSessions int                   `yaml:"sessions" json:"sessions"`         // Number of sessions that this tenant creates.
+	KernelSpec  string                `yaml:"kernel-spec" json:"kernel-spec"`   // Name of the Jupyter kernel spec used by each session. Matches KernelSpec.Name.
+	Arrival     *WorkloadArrival      `yaml:"arrival" json:"arrival"`           // How the tenant's sessions arrive. If nil, all sessions are created immediately.
+	CellArrival *WorkloadArrival      `yaml:"cell-arrival" json:"cell-arrival"` // If set, the gaps between a session's cell submissions are drawn from this process instead of the cells' think times.
+	Resources   *gateway.ResourceSpec `yaml:"resources" json:"resources"`       // Resources requested for each session's kernel.
+	Cells       []*WorkloadCell       `yaml:"cells" json:"cells"`               // The cells executed, in order, by each session.
+}
+
+// Return the resources with which each of the tenant's sessions is provisioned: the most requested of each resource by
+// the tenant or by any of its cells, since a kernel's resources cannot be changed once it has been created.
+// Returns nil if neither the tenant nor its cells request any resources.
+func (t *WorkloadTenant) PeakResources() *gateway.ResourceSpec {
+	var peak *gateway.ResourceSpec
+	if t.Resources != nil {
+		peak = &gateway.ResourceSpec{Cpu: t.Resources.Cpu, Memory: t.Resources.Memory, Gpu: t.Resources.Gpu}
+	}
+
+	for _, cell := range t.Cells {
+		if cell.Resources == nil {
+			continue
+		}
+		if peak == nil {
+			peak = &gateway.ResourceSpec{}
+		}
+
+		peak.Cpu = max(peak.Cpu, cell.Resources.Cpu)
+		peak.Memory = max(peak.Memory, cell.Resources.Memory)
+		peak.Gpu = max(peak.Gpu, cell.Resources.Gpu)
+	}
+
+	return peak
+}
+
+// Describes an arrival process, e.g., of a tenant's sessions. Which fields apply depends upon the process.
+type WorkloadArrival struct {
+	Process string  `yaml:"process" json:"process"` // The arrival process. One of the ArrivalProcess constants.
+	Rate    float64 `yaml:"rate" json:"rate"`       // Mean number of arrivals per second. For "bursty", the rate while idle. For "diurnal", the mean rate. For "ramp", the initial rate.
+
+	BurstRate    float64       `yaml:"burst-rate" json:"burst-rate"`       // bursty: mean number of arrivals per second during a burst.
+	MeanBurst    time.Duration `yaml:"mean-burst" json:"mean-burst"`       // bursty: mean duration of a burst.
+	MeanIdle     time.Duration `yaml:"mean-idle" json:"mean-idle"`         // bursty: mean duration of the idle periods between bursts.
+	Amplitude    float64       `yaml:"amplitude" json:"amplitude"`         // diurnal: amplitude of the rate's oscillation, in arrivals per second. Cannot exceed Rate.
+	Period       time.Duration `yaml:"period" json:"period"`               // diurnal: period of the rate's oscillation.
+	Phase        time.Duration `yaml:"phase" json:"phase"`                 // diurnal: offset into the period at which the process starts.
+	Steps        []*RateStep   `yaml:"steps" json:"steps"`                 // step: the rate changes. Before the first step, the rate is Rate.
+	EndRate      float64       `yaml:"end-rate" json:"end-rate"`           // ramp: the final rate.
+	RampDuration time.Duration `yaml:"ramp-duration" json:"ramp-duration"` // ramp: how long it takes for the rate to change from Rate to EndRate.
+}
+
+// A point in time at which the rate of a "step" arrival process changes.
+type RateStep struct {
+	At   time.Duration `yaml:"at" json:"at"`     // Offset from the start of the process.
+	Rate float64       `yaml:"rate" json:"rate"` // Mean number of arrivals per second from this point onwards.
+}
+
+// A single cell execution performed by a session.
+type WorkloadCell struct {
+	Code      string                `yaml:"code" json:"code"`             // The code to execute. If empty, the cell simply sleeps for Duration.
+	Duration  time.Duration         `yaml:"duration" json:"duration"`     // How long the cell is expected to run for.
+	ThinkTime time.Duration         `yaml:"think-time" json:"think-time"` // How long to wait after the cell completes before moving on.
+	Resources *gateway.ResourceSpec `yaml:"resources" json:"resources"`   // Resources requested by this cell. Defaults to those of the tenant. The session's kernel is provisioned for the largest request of any of its cells.
+}
+
+type WorkloadEventType string
+
+// Something that happened while driving a workload.
+type WorkloadEvent struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Type      WorkloadEventType `json:"type"`
+	Tenant    string            `json:"tenant"`
+	SessionId string            `json:"session_id"`
+	KernelId  string            `json:"kernel_id"`
+	CellIndex int               `json:"cell_index"` // -1 if the event does not pertain to a particular cell.
+	Error     string            `json:"error,omitempty"`
+	Drift     *time.Duration    `json:"drift,omitempty"` // When replaying a trace, how late the event occurred relative to the trace.
+}
+
+func (e *WorkloadEvent) String() string {
+	out, err := json.Marshal(e)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
+
+// Creates, drives, and tears down the Jupyter sessions (and their kernels) used by a workload.
+type SessionManager interface {
+	// Create a new session backed by a kernel of the specified kernel spec. Returns the ID of the kernel.
+	CreateSession(ctx context.Context, sessionId string, kernelSpec string, resources *gateway.ResourceSpec) (string, error)
+
+	// Execute code on the specified kernel, returning once the execution has completed.
+	ExecuteCode(ctx context.Context, kernelId string, code string) error
+
+	// Execute code on the specified kernel or replica, streaming its outputs. See CodeExecutor.
+	CodeExecutor
+
+	// Stop the session, shutting down its kernel.
+	StopSession(ctx context.Context, sessionId string) error
+}
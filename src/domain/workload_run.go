@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Operations supported by the backend's WORKLOAD_ENDPOINT. Each responds with the resulting WorkloadRun.
+const (
+	WorkloadOpRequest = "request-workload" // Return the status of the current run.
+	WorkloadOpStart   = "start-workload"   // Start a new run of the backend's configured workload.
+	WorkloadOpPause   = "pause-workload"
+	WorkloadOpResume  = "resume-workload"
+	WorkloadOpStop    = "stop-workload" // Abort the current run. Its sessions are torn down in the background.
+)
+
+type WorkloadRunState string
+
+const (
+	WorkloadRunPending  WorkloadRunState = "pending"  // The run has been created but has not yet started.
+	WorkloadRunRunning  WorkloadRunState = "running"  // The run is creating sessions and submitting cells.
+	WorkloadRunPaused   WorkloadRunState = "paused"   // The run has been paused. Existing cells are allowed to complete, but nothing new is submitted.
+	WorkloadRunFinished WorkloadRunState = "finished" // The run completed on its own, possibly with errors.
+	WorkloadRunAborted  WorkloadRunState = "aborted"  // The run was stopped before it completed.
+)
+
+// The status of a single run of a workload.
+type WorkloadRun struct {
+	Id       string           `json:"id"`
+	Workload string           `json:"workload"` // Name of the workload being run.
+	State    WorkloadRunState `json:"state"`
+
+	NumSessions       int `json:"num-sessions"`       // Total number of sessions the workload will create.
+	SessionsCompleted int `json:"sessions-completed"` // Number of sessions that have been torn down.
+	CellsCompleted    int `json:"cells-completed"`    // Number of cells that have finished executing.
+	NumErrors         int `json:"num-errors"`         // Number of errors encountered so far.
+
+	CreatedAt  time.Time  `json:"created-at"`
+	StartedAt  *time.Time `json:"started-at,omitempty"`
+	PausedAt   *time.Time `json:"paused-at,omitempty"` // Set while the run is paused.
+	FinishedAt *time.Time `json:"finished-at,omitempty"`
+	Error      string     `json:"error,omitempty"` // The error that the run finished with, if any.
+
+	Valid bool `json:"Valid"` // Used to determine if the struct was sent/received correctly over the network.
+}
+
+// Return the fraction of the run's sessions that have completed, between 0 and 1.
+func (r *WorkloadRun) Progress() float64 {
+	if r.NumSessions == 0 {
+		return 0
+	}
+
+	return float64(r.SessionsCompleted) / float64(r.NumSessions)
+}
+
+// Return true if the run has finished or been aborted.
+func (r *WorkloadRun) Done() bool {
+	return r.State == WorkloadRunFinished || r.State == WorkloadRunAborted
+}
+
+func (r *WorkloadRun) String() string {
+	out, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(out)
+}
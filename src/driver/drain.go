@@ -0,0 +1,275 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/providers"
+)
+
+var (
+	ErrCordonRequestFailed = errors.New("the backend could not cordon or uncordon the node")
+	ErrNodeDraining        = errors.New("the node is already being drained")
+)
+
+// Mark the node as unschedulable (if cordon is true) or as schedulable again (if false), via the backend.
+func (d *workloadDriverImpl) CordonNode(nodeId string, cordon bool) error {
+	op := domain.NodeOpUncordon
+	if cordon {
+		op = domain.NodeOpCordon
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.rpcCallTimeout)
+	defer cancel()
+	start := time.Now()
+	err := requestCordon(ctx, nodeId, op)
+	d.recorder.Observe(metrics.OpCordonNode, nodeId, start, err)
+
+	if err != nil {
+		app.Logf("[ERROR] Failed to %s node %s: %v", op, nodeId, err)
+		return err
+	}
+
+	app.Logf("Completed \"%s\" operation on node %s.", op, nodeId)
+
+	return nil
+}
+
+// Issue the cordon or uncordon operation to the backend, and wait for its response.
+func requestCordon(ctx context.Context, nodeId string, op string) error {
+	var resp domain.NodeCordonResponse
+	err := providers.RequestBackend(ctx, domain.KUBERNETES_NODES_ENDPOINT, op, &domain.NodeCordonRequest{NodeId: nodeId}, &resp)
+
+	var responseErr *domain.ResponseError
+	if errors.As(err, &responseErr) {
+		return fmt.Errorf("%w: %w", ErrCordonRequestFailed, responseErr)
+	}
+
+	return err
+}
+
+// Tell the Cluster Gateway to remove the host, i.e., to stop scheduling replicas on the node altogether.
+func (d *workloadDriverImpl) removeHost(hostId string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.rpcCallTimeout)
+	defer cancel()
+	rpcClient := d.connection.Client()
+	if rpcClient == nil {
+		return ErrRpcDisconnected
+	}
+
+	start := time.Now()
+	_, err := rpcClient.RemoveHost(ctx, &gateway.HostId{Id: hostId})
+	d.recorder.Observe(metrics.OpRemoveHost, hostId, start, err)
+
+	return err
+}
+
+// Cordon the node, and then migrate every replica on it to nodes chosen by the Cluster Gateway, one at a time,
+// stopping at the first failure. If removeHost is true, then the node's host is finally removed from the cluster.
+// The node is left cordoned either way, so that it can be inspected (or uncordoned) afterwards.
+func (d *workloadDriverImpl) DrainNode(nodeId string, removeHost bool, onProgress func(*domain.DrainStatus)) (*domain.DrainStatus, error) {
+	if !d.ConnectedToGateway() {
+		app.Log("[ERROR] Cannot drain node as we're not connected to the Cluster Gateway.")
+		return nil, ErrRpcDisconnected
+	}
+
+	d.drainMutex.Lock()
+	if _, ok := d.draining[nodeId]; ok {
+		d.drainMutex.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrNodeDraining, nodeId)
+	}
+	d.draining[nodeId] = struct{}{}
+	d.drainMutex.Unlock()
+
+	defer func() {
+		d.drainMutex.Lock()
+		delete(d.draining, nodeId)
+		d.drainMutex.Unlock()
+	}()
+
+	app.Logf("Draining node %s. Remove host afterwards: %v.", nodeId, removeHost)
+
+	drain := newNodeDrain(nodeId, onProgress)
+
+	cordon := drain.add(&domain.DrainStep{Kind: domain.DrainStepCordon, Description: fmt.Sprintf("Cordon node %s", nodeId)})
+	var remove *domain.DrainStep
+	if removeHost {
+		remove = drain.add(&domain.DrainStep{Kind: domain.DrainStepRemove, Description: fmt.Sprintf("Remove host %s from the cluster", nodeId)})
+	}
+	drain.notify()
+
+	if err := drain.run(cordon, func() error { return d.CordonNode(nodeId, true) }); err != nil {
+		return drain.finish(err)
+	}
+
+	// The replicas are only listed once the node is cordoned, as no further replicas can be placed on it after that.
+	replicas := replicasOnNode(d.kernelProvider.Resources(), nodeId)
+	migrations := make([]*domain.DrainStep, 0, len(replicas))
+	for _, replica := range replicas {
+		migrations = append(migrations, &domain.DrainStep{
+			Kind:        domain.DrainStepMigrate,
+			Description: fmt.Sprintf("Migrate replica %d of kernel %s", replica.ReplicaId, replica.KernelId),
+			KernelId:    replica.KernelId,
+			ReplicaId:   replica.ReplicaId,
+		})
+	}
+	drain.insertAfter(cordon, migrations...)
+	drain.notify()
+
+	for _, step := range migrations {
+		err := drain.run(step, func() error {
+			return d.MigrateKernelReplica(&gateway.MigrationRequest{
+				TargetReplica: &gateway.ReplicaInfo{
+					KernelId:  step.KernelId,
+					ReplicaId: step.ReplicaId,
+				},
+			})
+		})
+
+		if err != nil {
+			return drain.finish(err)
+		}
+	}
+
+	if remove != nil {
+		if err := drain.run(remove, func() error { return d.removeHost(nodeId) }); err != nil {
+			return drain.finish(err)
+		}
+	}
+
+	return drain.finish(nil)
+}
+
+// Return the replicas placed on the node, sorted by kernel and then by replica.
+func replicasOnNode(kernels []*gateway.DistributedJupyterKernel, nodeId string) []*gateway.JupyterKernelReplica {
+	replicas := make([]*gateway.JupyterKernelReplica, 0)
+	for _, kernel := range kernels {
+		for _, replica := range kernel.GetReplicas() {
+			if replica.GetNodeId() == nodeId {
+				replicas = append(replicas, replica)
+			}
+		}
+	}
+
+	sort.Slice(replicas, func(i, j int) bool {
+		if replicas[i].KernelId != replicas[j].KernelId {
+			return replicas[i].KernelId < replicas[j].KernelId
+		}
+		return replicas[i].ReplicaId < replicas[j].ReplicaId
+	})
+
+	return replicas
+}
+
+// Records the progress of a single drain, and reports it to the handler. The steps are performed sequentially by a
+// single goroutine, so the handler is passed copies of the status.
+type nodeDrain struct {
+	status     *domain.DrainStatus
+	onProgress func(*domain.DrainStatus)
+}
+
+func newNodeDrain(nodeId string, onProgress func(*domain.DrainStatus)) *nodeDrain {
+	return &nodeDrain{
+		status: &domain.DrainStatus{
+			NodeId:    nodeId,
+			State:     domain.DrainRunning,
+			Steps:     make([]*domain.DrainStep, 0),
+			StartedAt: time.Now(),
+		},
+		onProgress: onProgress,
+	}
+}
+
+func (n *nodeDrain) snapshot() *domain.DrainStatus {
+	status := *n.status
+	status.Steps = make([]*domain.DrainStep, 0, len(n.status.Steps))
+	for _, step := range n.status.Steps {
+		step := *step
+		status.Steps = append(status.Steps, &step)
+	}
+
+	return &status
+}
+
+func (n *nodeDrain) notify() {
+	if n.onProgress != nil {
+		n.onProgress(n.snapshot())
+	}
+}
+
+// Append a pending step.
+func (n *nodeDrain) add(step *domain.DrainStep) *domain.DrainStep {
+	step.State = domain.DrainStepPending
+	n.status.Steps = append(n.status.Steps, step)
+	return step
+}
+
+// Insert pending steps immediately after the given step.
+func (n *nodeDrain) insertAfter(after *domain.DrainStep, steps ...*domain.DrainStep) {
+	for _, step := range steps {
+		step.State = domain.DrainStepPending
+	}
+
+	for i, existing := range n.status.Steps {
+		if existing == after {
+			n.status.Steps = append(n.status.Steps[:i+1], append(steps, n.status.Steps[i+1:]...)...)
+			return
+		}
+	}
+
+	n.status.Steps = append(n.status.Steps, steps...)
+}
+
+// Perform the step, recording its outcome.
+func (n *nodeDrain) run(step *domain.DrainStep, op func() error) error {
+	startedAt := time.Now()
+	step.State = domain.DrainStepRunning
+	step.StartedAt = &startedAt
+	n.notify()
+
+	err := op()
+
+	finishedAt := time.Now()
+	step.FinishedAt = &finishedAt
+	if err != nil {
+		step.State = domain.DrainStepFailed
+		step.Error = err.Error()
+	} else {
+		step.State = domain.DrainStepSucceeded
+	}
+
+	return err
+}
+
+// Record the outcome of the drain. If it failed, then the steps that were not attempted are marked as skipped.
+func (n *nodeDrain) finish(err error) (*domain.DrainStatus, error) {
+	finishedAt := time.Now()
+	n.status.FinishedAt = &finishedAt
+
+	if err != nil {
+		n.status.State = domain.DrainFailed
+		n.status.Error = err.Error()
+
+		for _, step := range n.status.Steps {
+			if step.State == domain.DrainStepPending {
+				step.State = domain.DrainStepSkipped
+			}
+		}
+
+		app.Logf("[ERROR] Failed to drain node %s: %v", n.status.NodeId, err)
+	} else {
+		n.status.State = domain.DrainCompleted
+		app.Logf("Drained node %s in %v.", n.status.NodeId, finishedAt.Sub(n.status.StartedAt))
+	}
+
+	n.notify()
+
+	return n.snapshot(), err
+}
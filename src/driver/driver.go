@@ -2,38 +2,58 @@ package driver
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/maxence-charriere/go-app/v9/pkg/app"
 	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/providers"
-	"github.com/scusemua/djn-workload-driver/m/v2/src/proxy"
-	"go.uber.org/zap"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/security"
+)
+
+const (
+	// Timeout for individual RPC calls to the Cluster Gateway.
+	defaultRpcCallTimeout = time.Second * 30
+
+	// Scheme used to sign the messages exchanged with the kernels that we create.
+	kernelSignatureScheme = "hmac-sha256"
 )
 
 var (
-	ErrRequestIgnoredCxnSpoofed = errors.New("migration operation cannot be performed as the connection to the Cluster Gateway is spoofed")
-	ErrRpcDisconnected          = errors.New("cannot perform the requested RPC as we are not connected to the Cluster Gateway")
+	ErrRpcDisconnected        = errors.New("cannot perform the requested RPC as we are not connected to the Cluster Gateway")
+	ErrExecutionRequestFailed = errors.New("the backend could not execute the code")
 )
 
 type workloadDriverImpl struct {
-	connectedToGateway bool   // Flag indicating whether or not we're currently connected to the Cluster Gateway.
-	gatewayAddress     string // IP address of the Gateway.
+	connection *providers.GatewayConnection // The connection to the Cluster Gateway, shared with the providers.
+	startOnce  sync.Once                    // Starts the providers and the migration engine upon the first successful connection.
+
+	errorHandler      domain.ErrorHandler // Pass errors here to be displayed to the user.
+	nodeQueryInterval time.Duration       // How frequently to query the Gateway for node updates.
+	rpcCallTimeout    time.Duration       // Timeout for individual RPC calls.
 
-	errorHandler           domain.ErrorHandler          // Pass errors here to be displayed to the user.
-	spoofGatewayConnection bool                         // Used for development when not actually using a real cluster.
-	nodeQueryInterval      time.Duration                // How frequently to query the Gateway for node updates.
-	rpcCallTimeout         time.Duration                // Timeout for individual RPC calls.
-	rpcClient              gateway.ClusterGatewayClient // gRPC client to the Cluster Gateway.
+	kernels *GatewayKernelManager // Creates and terminates kernels over the connection to the Cluster Gateway.
 
-	kernelProvider     domain.KernelProvider
-	nodeProvider       domain.NodeProvider
-	kernelSpecProvider domain.KernelSpecProvider
+	noNodeWatch bool // If true, then the node provider isn't started, as there's no backend from which to fetch the nodes.
+
+	kernelProvider      domain.KernelProvider
+	nodeProvider        domain.NodeProvider
+	kernelSpecProvider  domain.KernelSpecProvider
+	workloadRunProvider domain.WorkloadRunProvider
+
+	workloadManager  *WorkloadManager  // Drives workloads started directly by this driver (rather than by the backend).
+	migrationEngine  *MigrationEngine  // Migrates replicas automatically. Nil if no migration policy is configured.
+	migrationTracker *migrationTracker // Tracks the progress of every migration, whether requested by the user or by the migration engine.
+	recorder         *metrics.Recorder // Records the latency of every request issued by the driver and its providers.
+
+	drainMutex sync.Mutex
+	draining   map[string]struct{} // The nodes that are currently being drained.
 }
 
 func NewWorkloadDriver(errorHandler domain.ErrorHandler, opts *config.Configuration) *workloadDriverImpl {
@@ -52,24 +72,65 @@ func NewWorkloadDriver(errorHandler domain.ErrorHandler, opts *config.Configurat
 		panic(err)
 	}
 
+	workloadQueryInterval, err := time.ParseDuration(opts.WorkloadQueryInterval)
+	if err != nil {
+		panic(err)
+	}
+
+	rpcCallTimeout := parseRpcCallTimeout(opts)
+	connectionOpts := gatewayConnectionOptions(opts)
+
+	// Likewise for the backend's address and credentials. In the browser, the MainWindow configures the backend from the page's URL instead.
+	credentials := security.Credentials{Token: opts.AuthToken, HmacSecret: opts.AuthHmacSecret}
+	if !credentials.Empty() || opts.TLSCert != "" || opts.ListenAddress != "" {
+		providers.ConfigureBackend(providers.BackendOptions{
+			Address:     providers.LocalBackendAddress(opts.ListenAddress),
+			Secure:      opts.TLSCert != "",
+			TLSConfig:   connectionOpts.TLSConfig,
+			Credentials: credentials,
+		})
+	}
+
+	migrationPolicy, err := NewMigrationPolicy(opts)
+	if err != nil {
+		panic(err)
+	}
+
 	// kernelMap := cmap.New[*gateway.DistributedJupyterKernel]()
 	// nodeMap := cmap.New[*domain.KubernetesNode]()
 	driver := &workloadDriverImpl{
 		// kernels:                &kernelMap,
 		// nodes:                  &nodeMap,
-		errorHandler:           errorHandler,
-		spoofGatewayConnection: opts.SpoofCluster,
-		nodeQueryInterval:      nodeQueryInterval,
+		connection:        providers.NewGatewayConnection(connectionOpts),
+		errorHandler:      errorHandler,
+		nodeQueryInterval: nodeQueryInterval,
+		rpcCallTimeout:    rpcCallTimeout,
+		recorder:          metrics.NewRecorder(),
+		draining:          make(map[string]struct{}),
 	}
+	driver.kernels = NewGatewayKernelManager(driver.connection, rpcCallTimeout, driver.recorder)
 
-	if driver.spoofGatewayConnection {
-		driver.kernelProvider = providers.NewSpoofedKernelProvider(kernelQueryInterval, errorHandler)
-	} else {
-		driver.kernelProvider = providers.NewKernelProvider(kernelQueryInterval, errorHandler)
-	}
+	// When spoofing the cluster, the kernels come from the in-process fake Cluster Gateway (see cluster.FakeCluster).
+	driver.kernelProvider = providers.NewKernelProvider(kernelQueryInterval, errorHandler, driver.recorder, driver.connection)
+
+	driver.nodeProvider = providers.NewNodeProvider(nodeQueryInterval, errorHandler, driver.recorder)
+	driver.kernelSpecProvider = providers.NewBaseKernelSpecProvider(kernelSpecQueryInterval, errorHandler, driver.recorder)
+	driver.workloadRunProvider = providers.NewWorkloadRunProvider(workloadQueryInterval, errorHandler, driver.recorder)
+	driver.workloadManager = NewWorkloadManager(opts, driver.kernels, errorHandler, driver.recorder)
+	driver.migrationTracker = newMigrationTracker(driver.kernelProvider)
+
+	if migrationPolicy != nil {
+		migrationInterval, err := time.ParseDuration(opts.MigrationInterval)
+		if err != nil {
+			panic(err)
+		}
 
-	driver.nodeProvider = providers.NewNodeProvider(nodeQueryInterval, errorHandler, opts.SpoofCluster)
-	driver.kernelSpecProvider = providers.NewBaseKernelSpecProvider(kernelSpecQueryInterval, errorHandler)
+		driver.migrationEngine = NewMigrationEngine(migrationPolicy, driver.MigrateKernelReplica, driver.nodeProvider, driver.kernelProvider, MigrationEngineOptions{
+			Interval:  migrationInterval,
+			DryRun:    opts.MigrationDryRun,
+			RateLimit: opts.MigrationRateLimit,
+		})
+	}
 
 	return driver
 }
@@ -80,43 +141,54 @@ func (d *workloadDriverImpl) Start(addr string) error {
 }
 
 func (d *workloadDriverImpl) ConnectedToGateway() bool {
-	return d.connectedToGateway
+	return d.connection.Client() != nil
 }
 
+// Connect to the Cluster Gateway, replacing the current connection, if any. The providers and the migration engine are
+// started upon the first successful connection, and thereafter share whichever connection is current.
 // This should NOT be called from the UI goroutine.
 func (d *workloadDriverImpl) DialGatewayGRPC(gatewayAddress string) error {
-	if d.spoofGatewayConnection {
-		time.Sleep(time.Second * 1)
-		d.connectedToGateway = true
-		d.gatewayAddress = gatewayAddress
-	} else if gatewayAddress == "" {
-		return domain.ErrEmptyGatewayAddr
-	} else {
-		// p.logger.Info(fmt.Sprintf("Attempting to dial Gateway gRPC server now. Address: %s\n", p.gatewayAddress))
-		app.Logf("Attempting to dial Gateway gRPC server now. Address: %s\n", gatewayAddress)
-
-		webSocketProxyClient := proxy.NewWebSocketProxyClient(time.Minute)
-		conn, err := grpc.Dial("ws://"+gatewayAddress, grpc.WithContextDialer(webSocketProxyClient.Dialer), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
-		if err != nil {
-			// p.logger.Error(fmt.Sprintf("Failed to dial Gateway gRPC server. Address: %s. Error: %v.\n", p.gatewayAddress, zap.Error(err)))
-			app.Logf("Failed to dial Gateway gRPC server. Address: %s. Error: %v.\n", gatewayAddress, zap.Error(err))
-			return err
-		}
-
-		// p.logger.Info(fmt.Sprintf("Successfully dialed Cluster Gateway at address %s.\n", p.gatewayAddress))
-		app.Logf("Successfully dialed Cluster Gateway at address %s.\n", gatewayAddress)
-		d.rpcClient = gateway.NewClusterGatewayClient(conn)
-		d.connectedToGateway = true
-		d.gatewayAddress = gatewayAddress
+	if err := d.connection.Connect(gatewayAddress); err != nil {
+		return err
 	}
 
-	app.Log("Starting Gateway Querier now.")
-	d.kernelProvider.Start(gatewayAddress)
-	d.nodeProvider.Start(gatewayAddress)
+	d.startOnce.Do(func() {
+		app.Log("Starting Gateway Querier now.")
+		d.kernelProvider.Start(gatewayAddress)
+
+		if !d.noNodeWatch {
+			d.nodeProvider.Start(gatewayAddress)
+		}
+
+		if d.migrationEngine != nil {
+			d.migrationEngine.Start()
+		}
+	})
 
 	return nil
 }
 
+// Don't watch the nodes, which are fetched from the backend, e.g., when running headless without one.
+// This must be called before the first call to DialGatewayGRPC.
+func (d *workloadDriverImpl) DisableNodeWatch() {
+	d.noNodeWatch = true
+}
+
+// Return the most recent change to the state of the connection to the Cluster Gateway.
+func (d *workloadDriverImpl) ConnectionState() *domain.GatewayConnectionEvent {
+	return d.connection.ConnectionState()
+}
+
+// Subscribe to changes to the state of the connection to the Cluster Gateway.
+func (d *workloadDriverImpl) SubscribeToConnectionEvents(id string, handler func(*domain.GatewayConnectionEvent) bool) {
+	d.connection.SubscribeToConnectionEvents(id, handler)
+}
+
+// Unsubscribe from changes to the state of the connection to the Cluster Gateway.
+func (d *workloadDriverImpl) UnsubscribeFromConnectionEvents(id string) {
+	d.connection.UnsubscribeFromConnectionEvents(id)
+}
+
 // Return a list of currently-active kernels.
 func (d *workloadDriverImpl) Resources() []*gateway.DistributedJupyterKernel {
 	return d.kernelProvider.Resources()
@@ -147,13 +219,14 @@ func (d *workloadDriverImpl) NodeProvider() domain.NodeProvider {
 	return d.nodeProvider
 }
 
-func (d *workloadDriverImpl) MigrateKernelReplica(arg *gateway.MigrationRequest) error {
-	if d.spoofGatewayConnection {
-		app.Log("[WARNING] We're spoofing the connection to the Gateway. Ignoring migration request.")
-		return ErrRequestIgnoredCxnSpoofed
-	}
+// Return the entity responsible for providing the status of, and controlling, the workload run managed by the backend.
+func (d *workloadDriverImpl) WorkloadRunProvider() domain.WorkloadRunProvider {
+	return d.workloadRunProvider
+}
 
-	if !d.connectedToGateway {
+func (d *workloadDriverImpl) MigrateKernelReplica(arg *gateway.MigrationRequest) error {
+	rpcClient := d.connection.Client()
+	if rpcClient == nil {
 		app.Log("[ERROR] Cannot perform migration operation as we're not connected to the Cluster Gateway.")
 		return ErrRpcDisconnected
 	}
@@ -162,9 +235,14 @@ func (d *workloadDriverImpl) MigrateKernelReplica(arg *gateway.MigrationRequest)
 		panic("Received nil argument for call to MigrateKernelReplica")
 	}
 
+	record := d.migrationTracker.begin(arg)
+
 	ctx, cancel := context.WithTimeout(context.Background(), d.rpcCallTimeout)
 	defer cancel()
-	resp, err := d.rpcClient.MigrateKernelReplica(ctx, arg)
+	start := time.Now()
+	resp, err := rpcClient.MigrateKernelReplica(ctx, arg)
+	d.recorder.Observe(metrics.OpMigrateKernelReplica, arg.TargetReplica.GetKernelId(), start, err)
+	d.migrationTracker.respond(record, resp, err)
 
 	if err != nil {
 		app.Logf("[ERROR] Recevied error in response to MigrateKernelReplica: %v", err)
@@ -176,6 +254,198 @@ func (d *workloadDriverImpl) MigrateKernelReplica(arg *gateway.MigrationRequest)
 	return nil
 }
 
+// Create a distributed kernel from the given kernel spec, with each replica using the given resources.
+// Returns the ID of the new kernel and its connection info.
+func (d *workloadDriverImpl) CreateKernel(spec *domain.KernelSpec, resources *gateway.ResourceSpec) (string, *gateway.KernelConnectionInfo, error) {
+	return d.kernels.CreateKernel(spec, resources)
+}
+
+// Terminate the specified kernel, along with all of its replicas.
+func (d *workloadDriverImpl) TerminateKernel(kernelId string) error {
+	return d.kernels.TerminateKernel(kernelId)
+}
+
+// Terminate each of the specified kernels concurrently, reporting whether each was terminated.
+func (d *workloadDriverImpl) TerminateKernels(kernelIds []string) []*domain.KernelOperationResult {
+	return forEachKernel(kernelIds, d.TerminateKernel)
+}
+
+// Interrupt the cell, if any, that the specified kernel is executing.
+func (d *workloadDriverImpl) InterruptKernel(kernelId string) error {
+	rpcClient := d.connection.Client()
+	if rpcClient == nil {
+		app.Log("[ERROR] Cannot interrupt kernel as we're not connected to the Cluster Gateway.")
+		return ErrRpcDisconnected
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.rpcCallTimeout)
+	defer cancel()
+	start := time.Now()
+	_, err := rpcClient.InterruptKernel(ctx, &gateway.KernelId{Id: kernelId})
+	d.recorder.Observe(metrics.OpInterruptKernel, kernelId, start, err)
+
+	if err != nil {
+		app.Logf("[ERROR] Failed to interrupt kernel %s: %v", kernelId, err)
+		return err
+	}
+
+	app.Logf("Interrupted kernel %s.", kernelId)
+
+	return nil
+}
+
+// Interrupt each of the specified kernels concurrently, reporting whether each was interrupted.
+func (d *workloadDriverImpl) InterruptKernels(kernelIds []string) []*domain.KernelOperationResult {
+	return forEachKernel(kernelIds, d.InterruptKernel)
+}
+
+// Execute code on a kernel, or on one of its replicas, via the backend. Each output is passed to the handler as it is produced.
+func (d *workloadDriverImpl) Execute(ctx context.Context, req *domain.ExecuteRequest, onOutput func(*domain.ExecutionOutput)) (*domain.ExecuteReply, error) {
+	start := time.Now()
+
+	var reply domain.ExecuteReply
+	err := providers.StreamBackend(ctx, domain.EXECUTE_ENDPOINT, domain.ExecuteOp, req, func(data json.RawMessage) error {
+		var output domain.ExecutionOutput
+		if err := json.Unmarshal(data, &output); err != nil {
+			return err
+		}
+
+		if onOutput != nil {
+			onOutput(&output)
+		}
+		return nil
+	}, &reply)
+
+	if err != nil {
+		var responseErr *domain.ResponseError
+		if errors.As(err, &responseErr) {
+			err = fmt.Errorf("%w: %w", ErrExecutionRequestFailed, responseErr)
+		}
+
+		app.Logf("[ERROR] Failed to execute code on %s: %v", req, err)
+		d.recorder.Observe(metrics.OpExecuteCode, req.KernelId, start, err)
+		return nil, err
+	}
+
+	d.recorder.Observe(metrics.OpExecuteCode, req.KernelId, start, reply.Err())
+	return &reply, nil
+}
+
+// Apply the operation to each of the kernels concurrently. The results are in the same order as the kernel IDs.
+func forEachKernel(kernelIds []string, op func(string) error) []*domain.KernelOperationResult {
+	results := make([]*domain.KernelOperationResult, len(kernelIds))
+
+	var wg sync.WaitGroup
+	for i, kernelId := range kernelIds {
+		wg.Add(1)
+		go func(i int, kernelId string) {
+			defer wg.Done()
+			results[i] = &domain.KernelOperationResult{KernelId: kernelId, Err: op(kernelId)}
+		}(i, kernelId)
+	}
+	wg.Wait()
+
+	return results
+}
+
 func (d *workloadDriverImpl) GatewayAddress() string {
-	return d.gatewayAddress
+	return d.connection.Address()
+}
+
+// Begin driving the given workload in the background.
+// Returns ErrWorkloadAlreadyRunning if a workload is already active.
+func (d *workloadDriverImpl) StartWorkload(workload *domain.Workload) error {
+	return d.workloadManager.Start(workload)
+}
+
+// Pause the active workload. No new sessions are created and no new cells are submitted until it is resumed.
+func (d *workloadDriverImpl) PauseWorkload() error {
+	return d.workloadManager.Pause()
+}
+
+// Resume the paused workload.
+func (d *workloadDriverImpl) ResumeWorkload() error {
+	return d.workloadManager.Resume()
+}
+
+// Abort the active workload. Sessions created by the workload are torn down before this returns.
+func (d *workloadDriverImpl) StopWorkload() error {
+	return d.workloadManager.Stop()
+}
+
+// Block until the active workload completes, returning any error it encountered.
+func (d *workloadDriverImpl) WaitWorkload() error {
+	return d.workloadManager.Wait()
+}
+
+// Return the events recorded by the most recent workload.
+func (d *workloadDriverImpl) WorkloadEvents() []*domain.WorkloadEvent {
+	return d.workloadManager.Events()
+}
+
+// Return the recorder of the latency of every request issued by the driver and its providers.
+func (d *workloadDriverImpl) Metrics() *metrics.Recorder {
+	return d.recorder
+}
+
+// Return the tracker of the progress of every migration.
+func (d *workloadDriverImpl) MigrationTracker() domain.MigrationTracker {
+	return d.migrationTracker
+}
+
+// Return the migrations decided upon by the migration policy, including those that were not performed.
+func (d *workloadDriverImpl) MigrationDecisions() []*domain.MigrationDecision {
+	if d.migrationEngine == nil {
+		return []*domain.MigrationDecision{}
+	}
+
+	return d.migrationEngine.Decisions()
+}
+
+// Return the status of the most recent workload run, or nil if no workload has been started.
+func (d *workloadDriverImpl) WorkloadRun() *domain.WorkloadRun {
+	return d.workloadManager.Run()
+}
+
+// Return the timeout for individual RPC calls to the Cluster Gateway.
+func parseRpcCallTimeout(opts *config.Configuration) time.Duration {
+	// Configurations from older backends may not specify a timeout.
+	if opts.RpcTimeout == "" {
+		return defaultRpcCallTimeout
+	}
+
+	rpcCallTimeout, err := time.ParseDuration(opts.RpcTimeout)
+	if err != nil {
+		panic(err)
+	}
+
+	return rpcCallTimeout
+}
+
+// Return the options with which the connection to the Cluster Gateway is monitored and secured.
+func gatewayConnectionOptions(opts *config.Configuration) providers.GatewayConnectionOptions {
+	var (
+		connectionOpts providers.GatewayConnectionOptions
+		err            error
+	)
+
+	// Configurations from older backends may not specify how the connection is monitored, in which case the defaults are used.
+	if opts.GatewayProbeInterval != "" {
+		if connectionOpts.HealthCheckInterval, err = time.ParseDuration(opts.GatewayProbeInterval); err != nil {
+			panic(err)
+		}
+	}
+	if opts.GatewayMaxBackoff != "" {
+		if connectionOpts.MaxReconnectBackoff, err = time.ParseDuration(opts.GatewayMaxBackoff); err != nil {
+			panic(err)
+		}
+	}
+
+	// The TLS files are only sent to the driver when it runs natively (e.g., headless). In the browser, they're empty.
+	if connectionOpts.TLSConfig, err = security.ClientTLSConfig(opts.TLSCACert, opts.TLSClientCert, opts.TLSClientKey); err != nil {
+		panic(err)
+	}
+	connectionOpts.Secure = opts.GatewayTLS
+
+	return connectionOpts
 }
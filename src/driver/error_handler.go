@@ -0,0 +1,19 @@
+package driver
+
+import (
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"go.uber.org/zap"
+)
+
+// An ErrorHandler that simply logs errors. Used when there is no UI to display them, e.g., when running headless.
+type loggerErrorHandler struct {
+	logger *zap.Logger
+}
+
+func NewLoggerErrorHandler(logger *zap.Logger) domain.ErrorHandler {
+	return &loggerErrorHandler{logger: logger}
+}
+
+func (h *loggerErrorHandler) HandleError(err error, errMsg string) {
+	h.logger.Error(errMsg, zap.Error(err))
+}
@@ -0,0 +1,102 @@
+package driver
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/providers"
+)
+
+// Creates and terminates kernels via the StartKernel and KillKernel RPCs of the Cluster Gateway.
+type GatewayKernelManager struct {
+	connection     *providers.GatewayConnection // The connection to the Cluster Gateway.
+	rpcCallTimeout time.Duration                // Timeout for individual RPC calls.
+	recorder       *metrics.Recorder            // Records the latency of each RPC.
+}
+
+func NewGatewayKernelManager(connection *providers.GatewayConnection, rpcCallTimeout time.Duration, recorder *metrics.Recorder) *GatewayKernelManager {
+	return &GatewayKernelManager{
+		connection:     connection,
+		rpcCallTimeout: rpcCallTimeout,
+		recorder:       recorder,
+	}
+}
+
+// Connect to the Cluster Gateway at the configured address, and return a GatewayKernelManager that uses the connection.
+// The backend uses this to drive workloads against the (fake) Cluster Gateway, as it has no WorkloadDriver of its own.
+func ConnectGatewayKernelManager(opts *config.Configuration, recorder *metrics.Recorder) (*GatewayKernelManager, error) {
+	connection := providers.NewGatewayConnection(gatewayConnectionOptions(opts))
+	if err := connection.Connect(opts.GatewayAddress); err != nil {
+		return nil, err
+	}
+
+	return NewGatewayKernelManager(connection, parseRpcCallTimeout(opts), recorder), nil
+}
+
+// Create a distributed kernel from the given kernel spec, with each replica using the given resources.
+// Returns the ID of the new kernel and its connection info.
+func (m *GatewayKernelManager) CreateKernel(spec *domain.KernelSpec, resources *gateway.ResourceSpec) (string, *gateway.KernelConnectionInfo, error) {
+	rpcClient := m.connection.Client()
+	if rpcClient == nil {
+		app.Log("[ERROR] Cannot create kernel as we're not connected to the Cluster Gateway.")
+		return "", nil, ErrRpcDisconnected
+	}
+
+	if spec == nil {
+		panic("Received nil kernel spec for call to CreateKernel")
+	}
+
+	kernelId := uuid.New().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
+	defer cancel()
+	start := time.Now()
+	connectionInfo, err := rpcClient.StartKernel(ctx, &gateway.KernelSpec{
+		Id:              kernelId,
+		Session:         uuid.New().String(),
+		Argv:            spec.ArgV,
+		SignatureScheme: kernelSignatureScheme,
+		Key:             uuid.New().String(),
+		Resource:        resources,
+	})
+	m.recorder.Observe(metrics.OpStartKernel, kernelId, start, err)
+
+	if err != nil {
+		app.Logf("[ERROR] Failed to create kernel from spec \"%s\": %v", spec.Name, err)
+		return "", nil, err
+	}
+
+	app.Logf("Created kernel %s from spec \"%s\".", kernelId, spec.Name)
+
+	return kernelId, connectionInfo, nil
+}
+
+// Terminate the specified kernel, along with all of its replicas.
+func (m *GatewayKernelManager) TerminateKernel(kernelId string) error {
+	rpcClient := m.connection.Client()
+	if rpcClient == nil {
+		app.Log("[ERROR] Cannot terminate kernel as we're not connected to the Cluster Gateway.")
+		return ErrRpcDisconnected
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
+	defer cancel()
+	start := time.Now()
+	_, err := rpcClient.KillKernel(ctx, &gateway.KernelId{Id: kernelId})
+	m.recorder.Observe(metrics.OpKillKernel, kernelId, start, err)
+
+	if err != nil {
+		app.Logf("[ERROR] Failed to terminate kernel %s: %v", kernelId, err)
+		return err
+	}
+
+	app.Logf("Terminated kernel %s.", kernelId)
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package driver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+const (
+	// Window over which the number of automatic migrations is rate limited.
+	migrationRateWindow = time.Minute
+
+	// A replica is not migrated again until this many evaluations of the policy have passed, so that it isn't bounced
+	// between nodes before their utilization reflects the previous migration.
+	migrationCooldownIntervals = 3
+)
+
+type MigrationEngineOptions struct {
+	Interval  time.Duration // How frequently the policy is evaluated.
+	DryRun    bool          // If true, then decisions are recorded, but not performed.
+	RateLimit int           // Maximum number of migrations per minute. Zero means unlimited.
+}
+
+// Periodically evaluates a MigrationPolicy against the nodes and kernels reported by the providers, and migrates
+// the replicas that it selects. Every decision is recorded, including those that were not performed.
+type MigrationEngine struct {
+	policy         MigrationPolicy
+	migrate        func(*gateway.MigrationRequest) error // Performs a migration, e.g., WorkloadDriver.MigrateKernelReplica.
+	nodeProvider   domain.NodeProvider
+	kernelProvider domain.KernelProvider
+	opts           MigrationEngineOptions
+
+	mutex        sync.Mutex
+	decisions    []*domain.MigrationDecision
+	recent       []time.Time          // When each of the migrations within the rate limiting window was performed.
+	lastMigrated map[string]time.Time // When each replica was last migrated (or would have been, if this is a dry run).
+	stop         chan struct{}        // Closed to stop the engine. Nil if the engine isn't running.
+}
+
+func NewMigrationEngine(policy MigrationPolicy, migrate func(*gateway.MigrationRequest) error, nodeProvider domain.NodeProvider, kernelProvider domain.KernelProvider, opts MigrationEngineOptions) *MigrationEngine {
+	return &MigrationEngine{
+		policy:         policy,
+		migrate:        migrate,
+		nodeProvider:   nodeProvider,
+		kernelProvider: kernelProvider,
+		opts:           opts,
+		decisions:      make([]*domain.MigrationDecision, 0),
+		lastMigrated:   make(map[string]time.Time),
+	}
+}
+
+// Begin evaluating the policy in the background. Does nothing if the engine is already running.
+func (e *MigrationEngine) Start() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.stop != nil {
+		return
+	}
+
+	app.Logf("Starting migration engine. Policy: %s. Interval: %v. Dry run: %v. Rate limit: %d/min.", e.policy.Name(), e.opts.Interval, e.opts.DryRun, e.opts.RateLimit)
+
+	stop := make(chan struct{})
+	e.stop = stop
+
+	go func() {
+		ticker := time.NewTicker(e.opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.Evaluate()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop evaluating the policy. Migrations that are in progress are allowed to complete.
+func (e *MigrationEngine) Stop() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.stop != nil {
+		close(e.stop)
+		e.stop = nil
+	}
+}
+
+// Return every decision made so far, in the order in which they were made.
+func (e *MigrationEngine) Decisions() []*domain.MigrationDecision {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	// The decisions are copied, as the outcome of the most recent ones may not have been recorded yet.
+	decisions := make([]*domain.MigrationDecision, 0, len(e.decisions))
+	for _, decision := range e.decisions {
+		decision := *decision
+		decisions = append(decisions, &decision)
+	}
+
+	return decisions
+}
+
+// Evaluate the policy once against the current nodes and kernels, and carry out its decisions.
+func (e *MigrationEngine) Evaluate() {
+	state := newClusterState(e.nodeProvider.Resources(), e.kernelProvider.Resources())
+	if len(state.nodes) == 0 {
+		return
+	}
+
+	for _, decision := range e.policy.Plan(state) {
+		key := fmt.Sprintf("%s-%d", decision.KernelId, decision.ReplicaId)
+		if !e.admit(key, decision) {
+			continue
+		}
+
+		switch {
+		case decision.RateLimited:
+			app.Logf("Skipping migration decided by the %s policy, as the rate limit of %d/min has been reached: %s", decision.Policy, e.opts.RateLimit, decision)
+		case decision.DryRun:
+			app.Logf("Dry run of migration decided by the %s policy: %s", decision.Policy, decision)
+		default:
+			err := e.migrate(&gateway.MigrationRequest{
+				TargetReplica: &gateway.ReplicaInfo{
+					KernelId:  decision.KernelId,
+					ReplicaId: decision.ReplicaId,
+				},
+				TargetNodeId: &decision.TargetNodeId,
+			})
+
+			if err != nil {
+				app.Logf("[ERROR] Failed to migrate replica %d of kernel %s from %s to %s: %v", decision.ReplicaId, decision.KernelId, decision.SourceNodeId, decision.TargetNodeId, err)
+
+				e.mutex.Lock()
+				decision.Error = err.Error()
+				e.mutex.Unlock()
+				continue
+			}
+
+			app.Logf("Performed migration decided by the %s policy: %s", decision.Policy, decision)
+		}
+	}
+}
+
+// Record the decision, and determine whether it should be carried out. Returns false if the replica is cooling down
+// from a recent migration, in which case the decision is dropped.
+func (e *MigrationEngine) admit(key string, decision *domain.MigrationDecision) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	now := time.Now()
+	if last, ok := e.lastMigrated[key]; ok && now.Sub(last) < e.opts.Interval*migrationCooldownIntervals {
+		return false
+	}
+
+	decision.Time = now
+	decision.Policy = e.policy.Name()
+	decision.DryRun = e.opts.DryRun
+
+	// Forget the migrations that have left the rate limiting window.
+	for len(e.recent) > 0 && now.Sub(e.recent[0]) >= migrationRateWindow {
+		e.recent = e.recent[1:]
+	}
+
+	if e.opts.RateLimit > 0 && len(e.recent) >= e.opts.RateLimit {
+		decision.RateLimited = true
+	} else {
+		e.recent = append(e.recent, now)
+		e.lastMigrated[key] = now
+	}
+
+	e.decisions = append(e.decisions, decision)
+	return true
+}
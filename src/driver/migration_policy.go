@@ -0,0 +1,273 @@
+package driver
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+// Decides which kernel replicas to migrate, and where to, given the current state of the cluster.
+type MigrationPolicy interface {
+	Name() domain.MigrationPolicyName
+
+	// Return the migrations to perform, in order of priority. The engine fills in the decisions' time, policy, and outcome.
+	Plan(state *clusterState) []*domain.MigrationDecision
+}
+
+// Create the migration policy specified by the configuration. Returns nil if replicas should not be migrated automatically.
+func NewMigrationPolicy(opts *config.Configuration) (MigrationPolicy, error) {
+	switch opts.MigrationPolicy {
+	case domain.MigrationPolicyNone, "":
+		return nil, nil
+	case domain.MigrationPolicyThreshold:
+		return &thresholdPolicy{highWatermark: opts.MigrationHighWatermark, lowWatermark: opts.MigrationLowWatermark}, nil
+	case domain.MigrationPolicyDrain:
+		if len(opts.MigrationDrainNodes) == 0 {
+			return nil, fmt.Errorf("the \"%s\" migration policy requires at least one node to drain", domain.MigrationPolicyDrain)
+		}
+		return newDrainPolicy(opts.MigrationDrainNodes), nil
+	case domain.MigrationPolicyChaos:
+		return &chaosPolicy{probability: opts.MigrationChaosProbability, rand: rand.New(rand.NewSource(opts.Seed))}, nil
+	default:
+		return nil, fmt.Errorf("unknown migration policy \"%s\"", opts.MigrationPolicy)
+	}
+}
+
+// A snapshot of the nodes and of the kernel replicas placed on them, from which policies plan migrations.
+type clusterState struct {
+	nodes          []*domain.KubernetesNode                   // Sorted by ID, so that plans depend only upon the state of the cluster.
+	replicasByNode map[string][]*gateway.JupyterKernelReplica // Replicas of the kernels that may be migrated, keyed by node ID.
+	kernelNodes    map[string]map[string]struct{}             // The nodes hosting a replica of each kernel.
+	incoming       map[string]int                             // Number of replicas that the current plan moves onto each node.
+}
+
+func newClusterState(nodes []*domain.KubernetesNode, kernels []*gateway.DistributedJupyterKernel) *clusterState {
+	state := &clusterState{
+		nodes:          make([]*domain.KubernetesNode, 0, len(nodes)),
+		replicasByNode: make(map[string][]*gateway.JupyterKernelReplica),
+		kernelNodes:    make(map[string]map[string]struct{}),
+		incoming:       make(map[string]int),
+	}
+
+	for _, node := range nodes {
+		if node != nil {
+			state.nodes = append(state.nodes, node)
+		}
+	}
+	sort.Slice(state.nodes, func(i, j int) bool {
+		return state.nodes[i].NodeId < state.nodes[j].NodeId
+	})
+
+	for _, kernel := range kernels {
+		state.kernelNodes[kernel.GetKernelId()] = make(map[string]struct{})
+		for _, replica := range kernel.GetReplicas() {
+			state.kernelNodes[kernel.GetKernelId()][replica.GetNodeId()] = struct{}{}
+		}
+
+		if !migratable(kernel) {
+			continue
+		}
+
+		for _, replica := range kernel.GetReplicas() {
+			state.replicasByNode[replica.GetNodeId()] = append(state.replicasByNode[replica.GetNodeId()], replica)
+		}
+	}
+
+	for _, replicas := range state.replicasByNode {
+		sort.Slice(replicas, func(i, j int) bool {
+			if replicas[i].KernelId != replicas[j].KernelId {
+				return replicas[i].KernelId < replicas[j].KernelId
+			}
+			return replicas[i].ReplicaId < replicas[j].ReplicaId
+		})
+	}
+
+	return state
+}
+
+// Kernels that are starting or shutting down cannot be migrated.
+func migratable(kernel *gateway.DistributedJupyterKernel) bool {
+	switch kernel.GetStatus() {
+	case "idle", "busy":
+		return true
+	default:
+		return false
+	}
+}
+
+// Return the utilization of the node's most utilized resource (CPU, memory, GPUs, or vGPUs), between 0 and 1.
+func nodeUtilization(node *domain.KubernetesNode) float64 {
+	utilization := 0.0
+	for _, resource := range [][2]float64{
+		{node.AllocatedCPU, node.CapacityCPU},
+		{node.AllocatedMemory, node.CapacityMemory},
+		{node.AllocatedGPUs, node.CapacityGPUs},
+		{node.AllocatedVGPUs, node.CapacityVGPUs},
+	} {
+		if resource[1] > 0 {
+			utilization = max(utilization, resource[0]/resource[1])
+		}
+	}
+
+	return utilization
+}
+
+// Record that the plan moves the replica onto the target node, so that it is not chosen again for the same kernel.
+func (s *clusterState) plan(replica *gateway.JupyterKernelReplica, target *domain.KubernetesNode, reason string) *domain.MigrationDecision {
+	s.kernelNodes[replica.KernelId][target.NodeId] = struct{}{}
+	s.incoming[target.NodeId]++
+
+	return &domain.MigrationDecision{
+		KernelId:     replica.KernelId,
+		ReplicaId:    replica.ReplicaId,
+		SourceNodeId: replica.NodeId,
+		TargetNodeId: target.NodeId,
+		Reason:       reason,
+	}
+}
+
+// Return the nodes to which the replica may be moved: those that aren't cordoned, that don't already host a replica
+// of its kernel, that the filter accepts, and onto which the current plan doesn't already move another replica.
+func (s *clusterState) candidates(replica *gateway.JupyterKernelReplica, accept func(*domain.KubernetesNode) bool) []*domain.KubernetesNode {
+	candidates := make([]*domain.KubernetesNode, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		if _, ok := s.kernelNodes[replica.KernelId][node.NodeId]; ok || node.Unschedulable || s.incoming[node.NodeId] > 0 {
+			continue
+		}
+
+		if accept == nil || accept(node) {
+			candidates = append(candidates, node)
+		}
+	}
+
+	return candidates
+}
+
+// Return the least utilized of the nodes, or nil if there are none.
+func leastUtilized(nodes []*domain.KubernetesNode) *domain.KubernetesNode {
+	var best *domain.KubernetesNode
+	for _, node := range nodes {
+		if best == nil || nodeUtilization(node) < nodeUtilization(best) {
+			best = node
+		}
+	}
+
+	return best
+}
+
+// Moves one replica off of each node whose utilization exceeds the high watermark, onto the least utilized node
+// whose utilization is below the low watermark. Nodes remain overloaded until enough replicas have been moved over
+// successive evaluations.
+type thresholdPolicy struct {
+	highWatermark float64
+	lowWatermark  float64
+}
+
+func (p *thresholdPolicy) Name() domain.MigrationPolicyName {
+	return domain.MigrationPolicyThreshold
+}
+
+func (p *thresholdPolicy) Plan(state *clusterState) []*domain.MigrationDecision {
+	decisions := make([]*domain.MigrationDecision, 0)
+
+	for _, node := range state.nodes {
+		utilization := nodeUtilization(node)
+		if utilization <= p.highWatermark {
+			continue
+		}
+
+		for _, replica := range state.replicasByNode[node.NodeId] {
+			target := leastUtilized(state.candidates(replica, func(candidate *domain.KubernetesNode) bool {
+				return nodeUtilization(candidate) < p.lowWatermark
+			}))
+
+			if target != nil {
+				reason := fmt.Sprintf("utilization of %s is %.2f, above the high watermark of %.2f", node.NodeId, utilization, p.highWatermark)
+				decisions = append(decisions, state.plan(replica, target, reason))
+				break
+			}
+		}
+	}
+
+	return decisions
+}
+
+// Moves every replica off of the specified nodes, onto the least utilized of the other nodes.
+type drainPolicy struct {
+	nodes map[string]struct{}
+}
+
+func newDrainPolicy(nodes []string) *drainPolicy {
+	policy := &drainPolicy{nodes: make(map[string]struct{}, len(nodes))}
+	for _, node := range nodes {
+		policy.nodes[node] = struct{}{}
+	}
+
+	return policy
+}
+
+func (p *drainPolicy) Name() domain.MigrationPolicyName {
+	return domain.MigrationPolicyDrain
+}
+
+func (p *drainPolicy) Plan(state *clusterState) []*domain.MigrationDecision {
+	decisions := make([]*domain.MigrationDecision, 0)
+
+	for _, node := range state.nodes {
+		if _, ok := p.nodes[node.NodeId]; !ok {
+			continue
+		}
+
+		for _, replica := range state.replicasByNode[node.NodeId] {
+			target := leastUtilized(state.candidates(replica, func(candidate *domain.KubernetesNode) bool {
+				_, draining := p.nodes[candidate.NodeId]
+				return !draining
+			}))
+
+			if target != nil {
+				decisions = append(decisions, state.plan(replica, target, fmt.Sprintf("draining %s", node.NodeId)))
+			}
+		}
+	}
+
+	return decisions
+}
+
+// With the given probability, moves a random replica to a random node. Used to study how the cluster and the
+// workload respond to migrations that have nothing to do with load.
+type chaosPolicy struct {
+	probability float64
+	rand        *rand.Rand
+}
+
+func (p *chaosPolicy) Name() domain.MigrationPolicyName {
+	return domain.MigrationPolicyChaos
+}
+
+func (p *chaosPolicy) Plan(state *clusterState) []*domain.MigrationDecision {
+	if p.rand.Float64() >= p.probability {
+		return nil
+	}
+
+	replicas := make([]*gateway.JupyterKernelReplica, 0)
+	for _, node := range state.nodes {
+		replicas = append(replicas, state.replicasByNode[node.NodeId]...)
+	}
+
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	replica := replicas[p.rand.Intn(len(replicas))]
+	candidates := state.candidates(replica, nil)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	target := candidates[p.rand.Intn(len(candidates))]
+	return []*domain.MigrationDecision{state.plan(replica, target, "chaos")}
+}
@@ -0,0 +1,207 @@
+package driver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+const (
+	// ID with which the tracker subscribes to changes to the kernels.
+	migrationTrackerSubscriberId = "migration-tracker"
+)
+
+// Tracks each migration from the request, through the Cluster Gateway's response, until the replica is seen on its
+// new node by the KernelProvider. The replica may be seen there before the response arrives.
+type migrationTracker struct {
+	kernelProvider domain.KernelProvider
+
+	mutex       sync.Mutex
+	records     []*domain.MigrationRecord
+	subscribers map[string]func([]*domain.MigrationRecord)
+}
+
+func newMigrationTracker(kernelProvider domain.KernelProvider) *migrationTracker {
+	tracker := &migrationTracker{
+		kernelProvider: kernelProvider,
+		records:        make([]*domain.MigrationRecord, 0),
+		subscribers:    make(map[string]func([]*domain.MigrationRecord)),
+	}
+
+	kernelProvider.SubscribeToChanges(migrationTrackerSubscriberId, tracker.handleKernelChanges)
+
+	return tracker
+}
+
+// Return a copy of every migration, oldest first. Must be called with the mutex held.
+func (t *migrationTracker) snapshot() []*domain.MigrationRecord {
+	records := make([]*domain.MigrationRecord, 0, len(t.records))
+	for _, record := range t.records {
+		record := *record
+		records = append(records, &record)
+	}
+
+	return records
+}
+
+func (t *migrationTracker) Migrations() []*domain.MigrationRecord {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.snapshot()
+}
+
+func (t *migrationTracker) SubscribeToMigrations(id string, handler func([]*domain.MigrationRecord)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.subscribers[id] = handler
+}
+
+func (t *migrationTracker) UnsubscribeFromMigrations(id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.subscribers, id)
+}
+
+// Unlock the mutex, and then pass every migration to the subscribers. Must be called with the mutex held.
+func (t *migrationTracker) unlockAndNotify() {
+	records := t.snapshot()
+	handlers := make([]func([]*domain.MigrationRecord), 0, len(t.subscribers))
+	for _, handler := range t.subscribers {
+		handlers = append(handlers, handler)
+	}
+	t.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(records)
+	}
+}
+
+// Find the replica among the current kernels. Returns nil if there's no such replica.
+func findReplica(kernels []*gateway.DistributedJupyterKernel, kernelId string, replicaId int32) *gateway.JupyterKernelReplica {
+	for _, kernel := range kernels {
+		if kernel.GetKernelId() != kernelId {
+			continue
+		}
+
+		for _, replica := range kernel.GetReplicas() {
+			if replica.GetReplicaId() == replicaId {
+				return replica
+			}
+		}
+	}
+
+	return nil
+}
+
+// Record that the migration has been requested.
+func (t *migrationTracker) begin(req *gateway.MigrationRequest) *domain.MigrationRecord {
+	record := &domain.MigrationRecord{
+		Id:           uuid.New().String(),
+		KernelId:     req.TargetReplica.GetKernelId(),
+		ReplicaId:    req.TargetReplica.GetReplicaId(),
+		TargetNodeId: req.GetTargetNodeId(),
+		State:        domain.MigrationRequested,
+		RequestedAt:  time.Now(),
+	}
+
+	if replica := findReplica(t.kernelProvider.Resources(), record.KernelId, record.ReplicaId); replica != nil {
+		record.SourceNodeId = replica.GetNodeId()
+	}
+
+	t.mutex.Lock()
+	t.records = append(t.records, record)
+	t.unlockAndNotify()
+
+	return record
+}
+
+// Record the Cluster Gateway's response to the migration request.
+func (t *migrationTracker) respond(record *domain.MigrationRecord, resp *gateway.MigrateKernelResponse, err error) {
+	// The replica may already be on its new node, in which case no further change to the kernel will reveal it.
+	replica := findReplica(t.kernelProvider.Resources(), record.KernelId, record.ReplicaId)
+
+	t.mutex.Lock()
+
+	now := time.Now()
+	record.RespondedAt = &now
+
+	if err != nil {
+		record.State = domain.MigrationFailed
+		record.Error = err.Error()
+	} else {
+		record.Hostname = resp.GetHostname()
+
+		if record.State == domain.MigrationRequested {
+			record.State = domain.MigrationAcknowledged
+			t.observe(record, replica, now)
+		}
+	}
+
+	t.unlockAndNotify()
+}
+
+// Mark the migration as completed if the replica is on a different node than the one it was migrated from (and on
+// the requested node, if one was requested). Returns true if it was. Must be called with the mutex held.
+func (t *migrationTracker) observe(record *domain.MigrationRecord, replica *gateway.JupyterKernelReplica, now time.Time) bool {
+	if replica == nil || replica.GetNodeId() == record.SourceNodeId {
+		return false
+	}
+
+	if record.TargetNodeId != "" && replica.GetNodeId() != record.TargetNodeId {
+		return false
+	}
+
+	record.State = domain.MigrationCompleted
+	record.ObservedAt = &now
+	record.NodeId = replica.GetNodeId()
+
+	app.Logf("Replica %d of kernel %s has been migrated from %s to %s in %v.", record.ReplicaId, record.KernelId, record.SourceNodeId, record.NodeId, now.Sub(record.RequestedAt))
+
+	return true
+}
+
+// Look for the replicas of the migrations that are in progress on their new nodes.
+func (t *migrationTracker) handleKernelChanges(changes []*domain.ResourceChange[*gateway.DistributedJupyterKernel]) bool {
+	t.mutex.Lock()
+
+	now := time.Now()
+	changed := false
+
+	for _, record := range t.records {
+		if record.State != domain.MigrationRequested && record.State != domain.MigrationAcknowledged {
+			continue
+		}
+
+		for _, change := range changes {
+			if change.Id != record.KernelId {
+				continue
+			}
+
+			switch change.Type {
+			case domain.ResourceRemoved:
+				record.State = domain.MigrationFailed
+				record.Error = fmt.Sprintf("kernel %s was removed before its replica was seen on its new node", record.KernelId)
+				changed = true
+			case domain.ResourceUpdated:
+				changed = t.observe(record, findReplica([]*gateway.DistributedJupyterKernel{change.Current}, record.KernelId, record.ReplicaId), now) || changed
+			}
+		}
+	}
+
+	if changed {
+		t.unlockAndNotify()
+	} else {
+		t.mutex.Unlock()
+	}
+
+	// Keep tracking the migrations for as long as the driver exists.
+	return true
+}
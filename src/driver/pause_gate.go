@@ -0,0 +1,106 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Lets a workload be paused and resumed. Runners wait at the gate before creating a session or submitting a cell.
+// The gate also tracks how long the workload has spent paused, so that schedules can be shifted accordingly.
+type pauseGate struct {
+	mutex       sync.Mutex
+	paused      bool
+	pausedAt    time.Time
+	pausedTotal time.Duration // Total time spent paused, excluding the current pause.
+	resumed     chan struct{} // Closed when the gate is resumed. Replaced each time the gate is paused.
+}
+
+func newPauseGate() *pauseGate {
+	return &pauseGate{resumed: make(chan struct{})}
+}
+
+// Close the gate. Returns false if it was already closed.
+func (g *pauseGate) pause() bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.paused {
+		return false
+	}
+
+	g.paused = true
+	g.pausedAt = time.Now()
+	g.resumed = make(chan struct{})
+	return true
+}
+
+// Open the gate. Returns false if it wasn't closed.
+func (g *pauseGate) resume() bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if !g.paused {
+		return false
+	}
+
+	g.paused = false
+	g.pausedTotal += time.Since(g.pausedAt)
+	close(g.resumed)
+	return true
+}
+
+// Return true if the gate is closed, along with the time at which it was closed.
+func (g *pauseGate) isPaused() (bool, time.Time) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.paused, g.pausedAt
+}
+
+// Return the total time spent paused, excluding the current pause (if any).
+func (g *pauseGate) pausedFor() time.Duration {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.pausedTotal
+}
+
+// Block until the gate is open. Returns false if the context was cancelled first.
+func (g *pauseGate) wait(ctx context.Context) bool {
+	for {
+		g.mutex.Lock()
+		paused, resumed := g.paused, g.resumed
+		g.mutex.Unlock()
+
+		if !paused {
+			return ctx.Err() == nil
+		}
+
+		select {
+		case <-resumed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// Sleep until the given offset from start has elapsed, not counting any time spent paused.
+// Returns false if the context was cancelled first.
+func (g *pauseGate) sleepUntil(ctx context.Context, start time.Time, offset time.Duration) bool {
+	for {
+		if !g.wait(ctx) {
+			return false
+		}
+
+		pausedFor := g.pausedFor()
+		if !sleepWithContext(ctx, time.Until(start.Add(offset+pausedFor))) {
+			return false
+		}
+
+		// If we were paused while sleeping, then the deadline has moved; go around again.
+		if paused, _ := g.isPaused(); !paused && g.pausedFor() == pausedFor {
+			return true
+		}
+	}
+}
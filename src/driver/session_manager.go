@@ -0,0 +1,266 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/jupyter"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+)
+
+var (
+	ErrUnknownSession  = errors.New("unknown session")
+	ErrNoKernelManager = errors.New("cannot create sessions when spoofing the cluster without a connection to the Cluster Gateway")
+)
+
+// Return a session manager that uses the Jupyter Server or, if we're spoofing the cluster, one that creates and
+// terminates the kernels via the (fake) Cluster Gateway. The kernel manager is only used in the latter case, and may be
+// nil if the session manager is only used to execute code. The latency of each of its operations is recorded by the recorder.
+func NewSessionManager(opts *config.Configuration, kernels domain.KernelManager, recorder *metrics.Recorder) domain.SessionManager {
+	var sessionManager domain.SessionManager
+	if opts.SpoofCluster {
+		sessionManager = newGatewaySessionManager(kernels, opts.Seed)
+	} else {
+		sessionManager = newJupyterSessionManager(jupyter.NewClient(opts.JupyterServerAddress, &jupyter.ClientOptions{Token: opts.JupyterServerToken}))
+	}
+
+	return newInstrumentedSessionManager(sessionManager, recorder)
+}
+
+// Creates sessions and executes code via the Jupyter Server's REST and websocket APIs.
+type jupyterSessionManager struct {
+	client       *jupyter.Client       // Creates and deletes the sessions.
+	kernelClient *jupyter.KernelClient // Executes code on the kernels.
+
+	sessionsMutex sync.Mutex
+	sessions      map[string]string // Map from our session IDs to the IDs assigned to them by the Jupyter Server.
+}
+
+func newJupyterSessionManager(client *jupyter.Client) *jupyterSessionManager {
+	return &jupyterSessionManager{
+		client:       client,
+		kernelClient: jupyter.NewKernelClient(client),
+		sessions:     make(map[string]string),
+	}
+}
+
+func (m *jupyterSessionManager) CreateSession(ctx context.Context, sessionId string, kernelSpec string, resources *gateway.ResourceSpec) (string, error) {
+	req := &jupyter.CreateSessionRequest{
+		Path:       sessionId,
+		Name:       sessionId,
+		KernelName: kernelSpec,
+	}
+
+	// The gateway provisioner creates the kernel's replicas with the requested resources.
+	if resources != nil {
+		req.Resources = &jupyter.ResourceSpec{Cpu: resources.Cpu, Memory: resources.Memory, Gpu: resources.Gpu}
+	}
+
+	session, err := m.client.CreateSession(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create session %s: %w", sessionId, err)
+	}
+
+	if session.Kernel == nil {
+		return "", fmt.Errorf("%w: session %s was created without a kernel", jupyter.ErrUnexpectedStatus, sessionId)
+	}
+
+	m.sessionsMutex.Lock()
+	m.sessions[sessionId] = session.Id
+	m.sessionsMutex.Unlock()
+
+	return session.Kernel.Id, nil
+}
+
+// Submit an "execute_request" over the kernel's shell channel and wait for the matching "execute_reply".
+func (m *jupyterSessionManager) ExecuteCode(ctx context.Context, kernelId string, code string) error {
+	reply, err := m.Execute(ctx, &domain.ExecuteRequest{KernelId: kernelId, Code: code}, nil)
+	if err != nil {
+		return err
+	}
+
+	return reply.Err()
+}
+
+func (m *jupyterSessionManager) Execute(ctx context.Context, req *domain.ExecuteRequest, onOutput func(*domain.ExecutionOutput)) (*domain.ExecuteReply, error) {
+	return m.kernelClient.Execute(ctx, req, onOutput)
+}
+
+func (m *jupyterSessionManager) StopSession(ctx context.Context, sessionId string) error {
+	m.sessionsMutex.Lock()
+	jupyterSessionId, ok := m.sessions[sessionId]
+	delete(m.sessions, sessionId)
+	m.sessionsMutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSession, sessionId)
+	}
+
+	if err := m.client.DeleteSession(ctx, jupyterSessionId); err != nil {
+		return fmt.Errorf("failed to stop session %s: %w", sessionId, err)
+	}
+
+	return nil
+}
+
+// Used when spoofing the cluster. Each session's kernel is created and terminated via the fake Cluster Gateway, which
+// has no RPC for executing code, so executions are simulated with some delay.
+type gatewaySessionManager struct {
+	kernels domain.KernelManager // Nil if the session manager is only used to execute code.
+
+	randMutex      sync.Mutex
+	rand           *rand.Rand
+	executionCount int // Number of simulated executions, also synchronized by randMutex.
+
+	sessionsMutex sync.Mutex
+	sessions      map[string]string // Map from session ID to kernel ID.
+}
+
+func newGatewaySessionManager(kernels domain.KernelManager, seed int64) *gatewaySessionManager {
+	return &gatewaySessionManager{
+		kernels:  kernels,
+		rand:     rand.New(rand.NewSource(seed)),
+		sessions: make(map[string]string),
+	}
+}
+
+// Sleep for a random amount of time in the interval [0, max), or until the context is done.
+func (m *gatewaySessionManager) simulateDelay(ctx context.Context, max time.Duration) error {
+	m.randMutex.Lock()
+	delay := time.Duration(m.rand.Int63n(int64(max)))
+	m.randMutex.Unlock()
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *gatewaySessionManager) CreateSession(ctx context.Context, sessionId string, kernelSpec string, resources *gateway.ResourceSpec) (string, error) {
+	if m.kernels == nil {
+		return "", ErrNoKernelManager
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	kernelId, _, err := m.kernels.CreateKernel(&domain.KernelSpec{Name: kernelSpec}, resources)
+	if err != nil {
+		return "", fmt.Errorf("failed to create session %s: %w", sessionId, err)
+	}
+
+	m.sessionsMutex.Lock()
+	m.sessions[sessionId] = kernelId
+	m.sessionsMutex.Unlock()
+
+	return kernelId, nil
+}
+
+func (m *gatewaySessionManager) ExecuteCode(ctx context.Context, kernelId string, code string) error {
+	reply, err := m.Execute(ctx, &domain.ExecuteRequest{KernelId: kernelId, Code: code}, nil)
+	if err != nil {
+		return err
+	}
+
+	return reply.Err()
+}
+
+// Simulate the execution of the code, which echoes the code to stdout and always succeeds.
+// Like a real Cluster Gateway, the fake one cannot execute code on a single replica.
+func (m *gatewaySessionManager) Execute(ctx context.Context, req *domain.ExecuteRequest, onOutput func(*domain.ExecutionOutput)) (*domain.ExecuteReply, error) {
+	if req.ReplicaId != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrReplicaExecutionUnsupported, req)
+	}
+
+	if err := m.simulateDelay(ctx, time.Millisecond*500); err != nil {
+		return nil, err
+	}
+
+	if onOutput != nil {
+		onOutput(&domain.ExecutionOutput{
+			Type: domain.ExecutionOutputStream,
+			Name: "stdout",
+			Text: fmt.Sprintf("Spoofed execution on %s:\n%s\n", req, req.Code),
+		})
+	}
+
+	m.randMutex.Lock()
+	m.executionCount++
+	executionCount := m.executionCount
+	m.randMutex.Unlock()
+
+	return &domain.ExecuteReply{Status: "ok", ExecutionCount: executionCount}, nil
+}
+
+func (m *gatewaySessionManager) StopSession(ctx context.Context, sessionId string) error {
+	m.sessionsMutex.Lock()
+	kernelId, ok := m.sessions[sessionId]
+	delete(m.sessions, sessionId)
+	m.sessionsMutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSession, sessionId)
+	}
+
+	if err := m.kernels.TerminateKernel(kernelId); err != nil {
+		return fmt.Errorf("failed to stop session %s: %w", sessionId, err)
+	}
+
+	return nil
+}
+
+// Wraps a SessionManager, recording the latency of each of its operations.
+type instrumentedSessionManager struct {
+	domain.SessionManager
+
+	recorder *metrics.Recorder
+}
+
+func newInstrumentedSessionManager(sessionManager domain.SessionManager, recorder *metrics.Recorder) *instrumentedSessionManager {
+	return &instrumentedSessionManager{SessionManager: sessionManager, recorder: recorder}
+}
+
+func (m *instrumentedSessionManager) CreateSession(ctx context.Context, sessionId string, kernelSpec string, resources *gateway.ResourceSpec) (string, error) {
+	start := time.Now()
+	kernelId, err := m.SessionManager.CreateSession(ctx, sessionId, kernelSpec, resources)
+	m.recorder.Observe(metrics.OpCreateSession, kernelId, start, err)
+	return kernelId, err
+}
+
+func (m *instrumentedSessionManager) ExecuteCode(ctx context.Context, kernelId string, code string) error {
+	start := time.Now()
+	err := m.SessionManager.ExecuteCode(ctx, kernelId, code)
+	m.recorder.Observe(metrics.OpExecuteCode, kernelId, start, err)
+	return err
+}
+
+func (m *instrumentedSessionManager) Execute(ctx context.Context, req *domain.ExecuteRequest, onOutput func(*domain.ExecutionOutput)) (*domain.ExecuteReply, error) {
+	start := time.Now()
+	reply, err := m.SessionManager.Execute(ctx, req, onOutput)
+
+	// Code that raised an exception counts as a failed execution.
+	observed := err
+	if err == nil {
+		observed = reply.Err()
+	}
+	m.recorder.Observe(metrics.OpExecuteCode, req.KernelId, start, observed)
+
+	return reply, err
+}
+
+func (m *instrumentedSessionManager) StopSession(ctx context.Context, sessionId string) error {
+	start := time.Now()
+	err := m.SessionManager.StopSession(ctx, sessionId)
+	m.recorder.Observe(metrics.OpStopSession, "", start, err)
+	return err
+}
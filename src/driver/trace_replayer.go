@@ -0,0 +1,178 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+const (
+	// Value of WorkloadEvent.Tenant for events generated while replaying a trace.
+	traceReplayTenant = "trace"
+)
+
+// Replays a recorded trace: each trace session is mapped to a new session (and kernel), and each of its
+// cells is submitted at the time it was submitted in the trace, optionally compressed by a speedup factor.
+// Every event records how late it occurred relative to the (scaled) trace timestamp.
+type traceReplayer struct {
+	eventLog
+
+	workload       *domain.Workload
+	sessionManager domain.SessionManager
+	errorHandler   domain.ErrorHandler
+	gate           *pauseGate
+	speedup        float64
+}
+
+func newTraceReplayer(workload *domain.Workload, sessionManager domain.SessionManager, errorHandler domain.ErrorHandler, gate *pauseGate) *traceReplayer {
+	speedup := workload.Trace.Speedup
+	if speedup <= 0 {
+		speedup = 1
+	}
+
+	return &traceReplayer{
+		workload:       workload,
+		sessionManager: sessionManager,
+		errorHandler:   errorHandler,
+		gate:           gate,
+		speedup:        speedup,
+	}
+}
+
+// Convert a time from the trace's timeline to the replay's timeline.
+func (r *traceReplayer) scale(t time.Duration) time.Duration {
+	return time.Duration(float64(t) / r.speedup)
+}
+
+// Return the wall-clock time at which something that occurred at time t of the trace is scheduled, accounting for time spent paused.
+func (r *traceReplayer) scheduledAt(start time.Time, t time.Duration) time.Time {
+	return start.Add(r.scale(t) + r.gate.pausedFor())
+}
+
+// Sleep until the given time of the trace. Returns false if the context was cancelled first.
+func (r *traceReplayer) sleepUntil(ctx context.Context, start time.Time, t time.Duration) bool {
+	return r.gate.sleepUntil(ctx, start, r.scale(t))
+}
+
+// Record an event along with how late it occurred relative to the time at which it was scheduled.
+func (r *traceReplayer) recordScheduledEvent(eventType domain.WorkloadEventType, sessionId string, kernelId string, cellIndex int, scheduled time.Time, err error) {
+	now := time.Now()
+	drift := now.Sub(scheduled)
+
+	r.record(&domain.WorkloadEvent{
+		Timestamp: now,
+		Type:      eventType,
+		Tenant:    traceReplayTenant,
+		SessionId: sessionId,
+		KernelId:  kernelId,
+		CellIndex: cellIndex,
+		Drift:     &drift,
+	}, err)
+}
+
+// Replay the trace to completion. This blocks until every session has been torn down or the context is cancelled.
+func (r *traceReplayer) Run(ctx context.Context) error {
+	trace := r.workload.Trace.Trace
+	if trace == nil {
+		return fmt.Errorf("the trace of workload \"%s\" has not been loaded", r.workload.Name)
+	}
+
+	app.Logf("Replaying trace of workload \"%s\": %d session(s) spanning %v at %.2fx speed.", r.workload.Name, len(trace.Sessions), trace.Duration(), r.speedup)
+
+	if r.workload.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.workload.Duration)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	var errsMutex sync.Mutex
+	errs := make([]error, 0)
+
+	for _, session := range trace.Sessions {
+		wg.Add(1)
+		go func(session *domain.TraceSession) {
+			defer wg.Done()
+
+			if err := r.replaySession(ctx, start, session); err != nil {
+				errsMutex.Lock()
+				errs = append(errs, err)
+				errsMutex.Unlock()
+			}
+		}(session)
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+
+	app.Logf("Finished replaying trace of workload \"%s\". Number of errors: %d. Drift: %v.", r.workload.Name, len(errs), domain.SummarizeDrift(r.Events()))
+
+	return errors.Join(errs...)
+}
+
+func (r *traceReplayer) replaySession(ctx context.Context, start time.Time, session *domain.TraceSession) error {
+	sessionId := fmt.Sprintf("%s-%s", traceReplayTenant, session.Id)
+
+	if !r.sleepUntil(ctx, start, session.Start) {
+		return nil
+	}
+	scheduled := r.scheduledAt(start, session.Start)
+
+	kernelId, err := r.sessionManager.CreateSession(ctx, sessionId, r.workload.Trace.KernelSpec, session.PeakResources())
+	if err != nil {
+		r.recordScheduledEvent(domain.WorkloadEventError, sessionId, "", -1, scheduled, err)
+		r.errorHandler.HandleError(err, fmt.Sprintf("Failed to create session %s.", sessionId))
+		return err
+	}
+	r.recordScheduledEvent(domain.WorkloadEventSessionCreated, sessionId, kernelId, -1, scheduled, nil)
+
+	// Always tear the session down, even if the replay is aborted.
+	defer func() {
+		scheduled := r.scheduledAt(start, session.End)
+
+		teardownCtx, cancel := context.WithTimeout(context.Background(), sessionTeardownTimeout)
+		defer cancel()
+
+		err := r.sessionManager.StopSession(teardownCtx, sessionId)
+		if err != nil {
+			r.errorHandler.HandleError(err, fmt.Sprintf("Failed to stop session %s.", sessionId))
+		}
+		r.recordScheduledEvent(domain.WorkloadEventSessionStopped, sessionId, kernelId, -1, scheduled, err)
+	}()
+
+	for cellIdx, cell := range session.Cells {
+		// If a previous cell (or the creation of the kernel) ran late, then we submit this cell immediately.
+		if !r.sleepUntil(ctx, start, cell.Start) {
+			return ctx.Err()
+		}
+		scheduled := r.scheduledAt(start, cell.Start)
+
+		r.recordScheduledEvent(domain.WorkloadEventCellSubmitted, sessionId, kernelId, cellIdx, scheduled, nil)
+
+		code := getCellCode(&domain.WorkloadCell{Duration: r.scale(cell.End - cell.Start)})
+		err := r.sessionManager.ExecuteCode(ctx, kernelId, code)
+		if err != nil {
+			r.recordScheduledEvent(domain.WorkloadEventError, sessionId, kernelId, cellIdx, r.scheduledAt(start, cell.End), err)
+			r.errorHandler.HandleError(err, fmt.Sprintf("Failed to execute cell %d of session %s.", cellIdx, sessionId))
+			return err
+		}
+		r.recordScheduledEvent(domain.WorkloadEventCellCompleted, sessionId, kernelId, cellIdx, r.scheduledAt(start, cell.End), nil)
+	}
+
+	// Hold the session until it ended in the trace.
+	if !r.sleepUntil(ctx, start, session.End) {
+		return ctx.Err()
+	}
+
+	return nil
+}
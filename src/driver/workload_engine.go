@@ -0,0 +1,250 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/arrival"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+const (
+	// How long we'll wait for a session to be torn down once its cells have been executed (or the workload has been aborted).
+	sessionTeardownTimeout = time.Second * 30
+)
+
+// Drives a workload to completion. Implemented by the synthetic workloadEngine and by the traceReplayer.
+type workloadRunner interface {
+	Run(ctx context.Context) error   // Drive the workload, blocking until it has finished or the context is cancelled.
+	Events() []*domain.WorkloadEvent // Return a copy of the events recorded so far.
+}
+
+// Records the events that occur while driving a workload. Safe for concurrent use.
+type eventLog struct {
+	eventsMutex sync.Mutex
+	events      []*domain.WorkloadEvent
+}
+
+// Return a copy of the events recorded so far.
+func (l *eventLog) Events() []*domain.WorkloadEvent {
+	l.eventsMutex.Lock()
+	defer l.eventsMutex.Unlock()
+
+	events := make([]*domain.WorkloadEvent, len(l.events))
+	copy(events, l.events)
+	return events
+}
+
+func (l *eventLog) recordEvent(eventType domain.WorkloadEventType, tenant string, sessionId string, kernelId string, cellIndex int, err error) {
+	l.record(&domain.WorkloadEvent{
+		Timestamp: time.Now(),
+		Type:      eventType,
+		Tenant:    tenant,
+		SessionId: sessionId,
+		KernelId:  kernelId,
+		CellIndex: cellIndex,
+	}, err)
+}
+
+func (l *eventLog) record(event *domain.WorkloadEvent, err error) {
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	l.eventsMutex.Lock()
+	l.events = append(l.events, event)
+	l.eventsMutex.Unlock()
+}
+
+// Drives a single workload: creates the sessions described by the workload, submits their cells, waits
+// for the configured think times, and finally tears the sessions down again.
+type workloadEngine struct {
+	eventLog
+
+	workload       *domain.Workload
+	sessionManager domain.SessionManager
+	errorHandler   domain.ErrorHandler
+	gate           *pauseGate
+}
+
+func newWorkloadEngine(workload *domain.Workload, sessionManager domain.SessionManager, errorHandler domain.ErrorHandler, gate *pauseGate) *workloadEngine {
+	return &workloadEngine{
+		workload:       workload,
+		sessionManager: sessionManager,
+		errorHandler:   errorHandler,
+		gate:           gate,
+	}
+}
+
+// Run the workload to completion. This blocks until every session has been torn down or the context is cancelled.
+// Returns the errors encountered by the individual sessions, joined together.
+func (e *workloadEngine) Run(ctx context.Context) error {
+	app.Logf("Starting workload \"%s\" with %d tenant(s) and %d session(s).", e.workload.Name, len(e.workload.Tenants), e.workload.NumSessions())
+
+	if e.workload.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.workload.Duration)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	var errsMutex sync.Mutex
+	errs := make([]error, 0)
+
+	for _, tenant := range e.workload.Tenants {
+		arrivals, err := e.getArrivalOffsets(tenant)
+		if err != nil {
+			// The sessions of earlier tenants may already be recording their errors.
+			errsMutex.Lock()
+			errs = append(errs, err)
+			errsMutex.Unlock()
+			continue
+		}
+
+		for i := 0; i < tenant.NumSessions; i++ {
+			if arrivals[i] == arrival.Never {
+				app.Logf("The arrival process of tenant \"%s\" stopped after %d of %d session(s).", tenant.Name, i, tenant.NumSessions)
+				break
+			}
+
+			wg.Add(1)
+			go func(tenant *domain.WorkloadTenant, idx int, arrival time.Duration) {
+				defer wg.Done()
+
+				// Wait for the session's turn to arrive. Time spent paused doesn't count.
+				if !e.gate.sleepUntil(ctx, start, arrival) {
+					return
+				}
+
+				if err := e.runSession(ctx, tenant, idx); err != nil {
+					errsMutex.Lock()
+					errs = append(errs, err)
+					errsMutex.Unlock()
+				}
+			}(tenant, i, arrivals[i])
+		}
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+
+	app.Logf("Workload \"%s\" has finished. Number of errors: %d.", e.workload.Name, len(errs))
+
+	return errors.Join(errs...)
+}
+
+// Drive one session of the given tenant from creation to teardown.
+func (e *workloadEngine) runSession(ctx context.Context, tenant *domain.WorkloadTenant, idx int) error {
+	sessionId := fmt.Sprintf("%s-%d-%s", tenant.Name, idx, uuid.New().String()[0:8])
+
+	kernelId, err := e.sessionManager.CreateSession(ctx, sessionId, tenant.KernelSpec, tenant.PeakResources())
+	if err != nil {
+		e.recordEvent(domain.WorkloadEventError, tenant.Name, sessionId, "", -1, err)
+		e.errorHandler.HandleError(err, fmt.Sprintf("Failed to create session %s.", sessionId))
+		return err
+	}
+	e.recordEvent(domain.WorkloadEventSessionCreated, tenant.Name, sessionId, kernelId, -1, nil)
+
+	// Always tear the session down, even if the workload is aborted. We use a fresh context for this so that cancelling the workload doesn't prevent the teardown.
+	defer func() {
+		teardownCtx, cancel := context.WithTimeout(context.Background(), sessionTeardownTimeout)
+		defer cancel()
+
+		err := e.sessionManager.StopSession(teardownCtx, sessionId)
+		if err != nil {
+			e.errorHandler.HandleError(err, fmt.Sprintf("Failed to stop session %s.", sessionId))
+		}
+		e.recordEvent(domain.WorkloadEventSessionStopped, tenant.Name, sessionId, kernelId, -1, err)
+	}()
+
+	// If the tenant has a cell arrival process, then it determines the gaps between cells rather than the cells' think times.
+	var cellArrivals arrival.Process
+	if tenant.CellArrival != nil {
+		cellArrivals, err = arrival.New(tenant.CellArrival, arrival.DeriveSeed(e.workload.Seed, tenant.Name, "cells", strconv.Itoa(idx)))
+		if err != nil {
+			return err
+		}
+	}
+
+	for cellIdx, cell := range tenant.Cells {
+		if !e.gate.wait(ctx) {
+			return ctx.Err()
+		}
+
+		e.recordEvent(domain.WorkloadEventCellSubmitted, tenant.Name, sessionId, kernelId, cellIdx, nil)
+
+		err := e.sessionManager.ExecuteCode(ctx, kernelId, getCellCode(cell))
+		if err != nil {
+			e.recordEvent(domain.WorkloadEventError, tenant.Name, sessionId, kernelId, cellIdx, err)
+			e.errorHandler.HandleError(err, fmt.Sprintf("Failed to execute cell %d of session %s.", cellIdx, sessionId))
+			return err
+		}
+		e.recordEvent(domain.WorkloadEventCellCompleted, tenant.Name, sessionId, kernelId, cellIdx, nil)
+
+		// Hold the session for the think time before moving on to the next cell.
+		thinkTime := cell.ThinkTime
+		if cellArrivals != nil && cellIdx+1 < len(tenant.Cells) {
+			if thinkTime = cellArrivals.Next(); thinkTime == arrival.Never {
+				return nil
+			}
+		}
+
+		if !sleepWithContext(ctx, thinkTime) {
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// Return the offsets, relative to the start of the workload, at which each of the tenant's sessions arrives.
+// Each tenant's arrival process is seeded independently, so adding or removing a tenant doesn't change the arrivals of the others.
+func (e *workloadEngine) getArrivalOffsets(tenant *domain.WorkloadTenant) ([]time.Duration, error) {
+	if tenant.Arrival == nil {
+		return make([]time.Duration, tenant.NumSessions), nil
+	}
+
+	process, err := arrival.New(tenant.Arrival, arrival.DeriveSeed(e.workload.Seed, tenant.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return arrival.Offsets(process, tenant.NumSessions), nil
+}
+
+// Return the code to execute for a cell. Cells without code simply sleep for their duration.
+func getCellCode(cell *domain.WorkloadCell) string {
+	if cell.Code != "" {
+		return cell.Code
+	}
+
+	return fmt.Sprintf("import time\ntime.sleep(%f)", cell.Duration.Seconds())
+}
+
+// Sleep for the specified duration. Returns false if the context was cancelled before the duration elapsed.
+func sleepWithContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,246 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+)
+
+// Owns the lifecycle of workload runs: starting, pausing, resuming, and stopping them, and reporting their status.
+// At most one run is active at a time. Used both by the WorkloadDriver and by the backend's workload endpoint.
+type WorkloadManager struct {
+	sessionManager domain.SessionManager // Creates, drives, and tears down the sessions used by workloads.
+	errorHandler   domain.ErrorHandler
+	recorder       *metrics.Recorder // Records the latency of every operation issued during a run. Reset at the start of each run.
+
+	mutex    sync.Mutex          // Synchronizes access to the fields below.
+	run      *domain.WorkloadRun // Status of the current (or most recent) run. Events-derived fields are filled in on demand.
+	runner   workloadRunner      // Drives the current (or most recent) run.
+	gate     *pauseGate          // Pauses the current run.
+	cancel   context.CancelFunc  // Cancels the current run.
+	done     chan struct{}       // Closed once the current run has completed.
+	err      error               // The error returned by the most recent run, if any.
+	aborting bool                // True if the current run was stopped.
+}
+
+func NewWorkloadManager(opts *config.Configuration, kernels domain.KernelManager, errorHandler domain.ErrorHandler, recorder *metrics.Recorder) *WorkloadManager {
+	return &WorkloadManager{
+		sessionManager: NewSessionManager(opts, kernels, recorder),
+		errorHandler:   errorHandler,
+		recorder:       recorder,
+	}
+}
+
+// Return true if there's a run that hasn't completed yet. Must be called with the mutex held.
+func (m *WorkloadManager) active() bool {
+	if m.done == nil {
+		return false
+	}
+
+	select {
+	case <-m.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Begin driving the given workload in the background.
+// Returns ErrWorkloadAlreadyRunning if a workload is already active.
+func (m *WorkloadManager) Start(workload *domain.Workload) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.active() {
+		return domain.ErrWorkloadAlreadyRunning
+	}
+
+	gate := newPauseGate()
+
+	var runner workloadRunner
+	if workload.Trace != nil {
+		runner = newTraceReplayer(workload, m.sessionManager, m.errorHandler, gate)
+	} else {
+		runner = newWorkloadEngine(workload, m.sessionManager, m.errorHandler, gate)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// The run is running as soon as Start returns, so that it can be paused right away. If it's paused before the
+	// runner gets going, then the runner waits at the gate before creating any sessions.
+	now := time.Now()
+	m.run = &domain.WorkloadRun{
+		Id:          uuid.New().String(),
+		Workload:    workload.Name,
+		State:       domain.WorkloadRunRunning,
+		NumSessions: workload.NumSessions(),
+		CreatedAt:   now,
+		StartedAt:   &now,
+		Valid:       true,
+	}
+	m.runner = runner
+	m.gate = gate
+	m.cancel = cancel
+	m.done = done
+	m.err = nil
+	m.aborting = false
+	m.recorder.Reset()
+
+	run := m.run
+	go func() {
+		err := runner.Run(ctx)
+
+		m.mutex.Lock()
+		finishedAt := time.Now()
+		run.FinishedAt = &finishedAt
+		run.PausedAt = nil
+		if m.aborting {
+			run.State = domain.WorkloadRunAborted
+		} else {
+			run.State = domain.WorkloadRunFinished
+		}
+		if err != nil {
+			run.Error = err.Error()
+		}
+		m.err = err
+		m.mutex.Unlock()
+
+		app.Logf("Workload run %s (%s) is now %s. Latencies:\n%s", run.Id, run.Workload, run.State, m.recorder.Report())
+
+		cancel()
+		close(done)
+	}()
+
+	return nil
+}
+
+// Pause the active run. Cells that are already executing are allowed to complete.
+func (m *WorkloadManager) Pause() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.active() {
+		return domain.ErrNoActiveWorkload
+	}
+
+	if m.run.State != domain.WorkloadRunRunning || !m.gate.pause() {
+		return domain.ErrWorkloadNotRunning
+	}
+
+	_, pausedAt := m.gate.isPaused()
+	m.run.State = domain.WorkloadRunPaused
+	m.run.PausedAt = &pausedAt
+
+	return nil
+}
+
+// Resume the paused run.
+func (m *WorkloadManager) Resume() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.active() {
+		return domain.ErrNoActiveWorkload
+	}
+
+	if m.run.State != domain.WorkloadRunPaused || !m.gate.resume() {
+		return domain.ErrWorkloadNotPaused
+	}
+
+	m.run.State = domain.WorkloadRunRunning
+	m.run.PausedAt = nil
+
+	return nil
+}
+
+// Abort the active run without waiting for its sessions to be torn down.
+func (m *WorkloadManager) Abort() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.active() {
+		return domain.ErrNoActiveWorkload
+	}
+
+	m.aborting = true
+	m.cancel()
+
+	return nil
+}
+
+// Abort the active run. Sessions created by the run are torn down before this returns.
+func (m *WorkloadManager) Stop() error {
+	if err := m.Abort(); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	done := m.done
+	m.mutex.Unlock()
+
+	<-done
+
+	return nil
+}
+
+// Block until the most recent run completes, returning any error it encountered.
+func (m *WorkloadManager) Wait() error {
+	m.mutex.Lock()
+	done := m.done
+	m.mutex.Unlock()
+
+	if done == nil {
+		return domain.ErrNoActiveWorkload
+	}
+
+	<-done
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.err
+}
+
+// Return the events recorded by the most recent run.
+func (m *WorkloadManager) Events() []*domain.WorkloadEvent {
+	m.mutex.Lock()
+	runner := m.runner
+	m.mutex.Unlock()
+
+	if runner == nil {
+		return []*domain.WorkloadEvent{}
+	}
+
+	return runner.Events()
+}
+
+// Return a snapshot of the status of the most recent run, or nil if no workload has been started.
+func (m *WorkloadManager) Run() *domain.WorkloadRun {
+	m.mutex.Lock()
+	if m.run == nil {
+		m.mutex.Unlock()
+		return nil
+	}
+	run := *m.run
+	runner := m.runner
+	m.mutex.Unlock()
+
+	for _, event := range runner.Events() {
+		switch event.Type {
+		case domain.WorkloadEventSessionStopped:
+			run.SessionsCompleted++
+		case domain.WorkloadEventCellCompleted:
+			run.CellsCompleted++
+		case domain.WorkloadEventError:
+			run.NumErrors++
+		}
+	}
+
+	return &run
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+)
+
+// Identifies the columnar format, so that readers can detect incompatible changes.
+const ColumnarFormat = "djn-columnar/v1"
+
+// A table stored column by column, in the spirit of Parquet: each column holds all of its values contiguously,
+// which compresses well and lets readers load only the columns they need, e.g., pandas.DataFrame(column["values"]).
+// Written as gzip-compressed JSON.
+type ColumnarTable struct {
+	Format  string            `json:"format"`
+	Table   string            `json:"table"`
+	NumRows int               `json:"num_rows"`
+	Columns []*ColumnarColumn `json:"columns"`
+}
+
+type ColumnarColumn struct {
+	Name   string        `json:"name"`
+	Type   ColumnType    `json:"type"`
+	Values []interface{} `json:"values"` // Timestamps are nanoseconds since the Unix epoch. Missing values are null.
+}
+
+// Write the table in the columnar format.
+func (t *table) writeColumnar(w io.Writer) error {
+	columnar := &ColumnarTable{
+		Format:  ColumnarFormat,
+		Table:   t.name,
+		NumRows: len(t.rows),
+		Columns: make([]*ColumnarColumn, len(t.columns)),
+	}
+
+	for i, col := range t.columns {
+		values := make([]interface{}, len(t.rows))
+		for j, row := range t.rows {
+			values[j] = jsonValue(row[i], true)
+		}
+
+		columnar.Columns[i] = &ColumnarColumn{
+			Name:   col.name,
+			Type:   col.columnType,
+			Values: values,
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(columnar); err != nil {
+		gz.Close()
+		return err
+	}
+
+	return gz.Close()
+}
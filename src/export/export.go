@@ -0,0 +1,280 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+)
+
+const (
+	// The current version of the layout of the output directory.
+	ManifestVersion = "v1"
+
+	ManifestFile = "manifest.json"
+	SummaryFile  = "summary.json"
+
+	FormatCSV      = "csv"
+	FormatJSONL    = "jsonl"
+	FormatColumnar = "columnar" // See ColumnarTable.
+	FormatJSON     = "json"
+
+	TableEvents   = "events"   // One row per WorkloadEvent.
+	TableKernels  = "kernels"  // One row per KernelTimeline.
+	TableNodes    = "nodes"    // One row per NodeSample.
+	TableRequests = "requests" // One row per metrics.Sample.
+)
+
+// File extension of each format.
+var extensions = map[string]string{
+	FormatCSV:      ".csv",
+	FormatJSONL:    ".jsonl",
+	FormatColumnar: ".columnar.json.gz",
+}
+
+// Everything recorded during a run that should be exported.
+type Results struct {
+	Run       *domain.WorkloadRun
+	Events    []*domain.WorkloadEvent
+	Samples   []*metrics.Sample // Latency of every request issued during the run.
+	Latencies metrics.Report
+	Nodes     []*NodeSample // Nil if the nodes weren't sampled, in which case the nodes table is omitted.
+
+	Extra map[string]interface{} // Additional documents, keyed by name, each written as <name>.json alongside the summary.
+}
+
+// Describes the contents of an output directory, and how they were produced.
+type Manifest struct {
+	Version      string           `json:"version"`
+	CreatedAt    time.Time        `json:"created_at"`
+	Workload     string           `json:"workload"`
+	WorkloadHash string           `json:"workload_hash"` // See domain.Workload.Hash.
+	Config       json.RawMessage  `json:"config"`        // The configuration of the driver, as rendered by config.Configuration.String.
+	Files        []*ManifestEntry `json:"files"`
+}
+
+// A single file in an output directory.
+type ManifestEntry struct {
+	Path   string `json:"path"` // Relative to the output directory.
+	Table  string `json:"table,omitempty"`
+	Format string `json:"format"`
+	Rows   int    `json:"rows,omitempty"`
+	SHA256 string `json:"sha256"`
+}
+
+// Write the results of a run to the output directory, which is created if it does not already exist.
+// Every table is written as CSV, JSON Lines, and in the columnar format, alongside a summary and a manifest.
+func Write(dir string, conf *config.Configuration, results *Results) (*Manifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Version:   ManifestVersion,
+		CreatedAt: time.Now(),
+		Config:    json.RawMessage(conf.String()),
+		Files:     make([]*ManifestEntry, 0),
+	}
+
+	if conf.Workload != nil {
+		manifest.Workload = conf.Workload.Name
+		manifest.WorkloadHash = conf.Workload.Hash()
+	}
+
+	timelines := KernelTimelines(results.Events)
+	tables := []*table{eventsTable(results.Events), kernelsTable(timelines)}
+	if results.Nodes != nil {
+		tables = append(tables, nodesTable(results.Nodes))
+	}
+	tables = append(tables, requestsTable(results.Samples))
+
+	for _, t := range tables {
+		for _, format := range []string{FormatCSV, FormatJSONL, FormatColumnar} {
+			entry, err := writeFile(dir, t.name+extensions[format], format, func(w io.Writer) error {
+				switch format {
+				case FormatCSV:
+					return t.writeCSV(w)
+				case FormatJSONL:
+					return t.writeJSONL(w)
+				default:
+					return t.writeColumnar(w)
+				}
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to write table \"%s\" as %s: %w", t.name, format, err)
+			}
+
+			entry.Table = t.name
+			entry.Rows = len(t.rows)
+			manifest.Files = append(manifest.Files, entry)
+		}
+	}
+
+	entry, err := writeFile(dir, SummaryFile, FormatJSON, func(w io.Writer) error {
+		return writeJSON(w, summarize(results, timelines))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write summary: %w", err)
+	}
+	manifest.Files = append(manifest.Files, entry)
+
+	names := make([]string, 0, len(results.Extra))
+	for name := range results.Extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		entry, err := writeFile(dir, name+".json", FormatJSON, func(w io.Writer) error {
+			return writeJSON(w, results.Extra[name])
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+
+	// The manifest describes the other files, so it is written last.
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// Create the named file in the output directory and write its contents, recording its hash.
+func writeFile(dir string, name string, format string, write func(io.Writer) error) (*ManifestEntry, error) {
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if err := write(io.MultiWriter(file, hash)); err != nil {
+		return nil, err
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	return &ManifestEntry{
+		Path:   name,
+		Format: format,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func eventsTable(events []*domain.WorkloadEvent) *table {
+	t := newTable(TableEvents,
+		column{"timestamp", ColumnTimestamp},
+		column{"type", ColumnString},
+		column{"tenant", ColumnString},
+		column{"session_id", ColumnString},
+		column{"kernel_id", ColumnString},
+		column{"cell_index", ColumnInt64},
+		column{"error", ColumnString},
+		column{"drift_ns", ColumnDuration},
+	)
+
+	for _, event := range events {
+		t.append(event.Timestamp, string(event.Type), event.Tenant, event.SessionId, event.KernelId, event.CellIndex, event.Error, event.Drift)
+	}
+
+	return t
+}
+
+// Return the time, or nil if it is zero.
+func optionalTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t
+}
+
+func kernelsTable(timelines []*KernelTimeline) *table {
+	t := newTable(TableKernels,
+		column{"kernel_id", ColumnString},
+		column{"session_id", ColumnString},
+		column{"tenant", ColumnString},
+		column{"first_event_at", ColumnTimestamp},
+		column{"created_at", ColumnTimestamp},
+		column{"first_cell_at", ColumnTimestamp},
+		column{"last_cell_at", ColumnTimestamp},
+		column{"stopped_at", ColumnTimestamp},
+		column{"cells_submitted", ColumnInt64},
+		column{"cells_completed", ColumnInt64},
+		column{"num_errors", ColumnInt64},
+		column{"busy_ns", ColumnDuration},
+		column{"lifetime_ns", ColumnDuration},
+	)
+
+	for _, timeline := range timelines {
+		t.append(timeline.KernelId, timeline.SessionId, timeline.Tenant, timeline.FirstEventAt, optionalTime(timeline.CreatedAt),
+			optionalTime(timeline.FirstCellAt), optionalTime(timeline.LastCellAt), optionalTime(timeline.StoppedAt),
+			timeline.CellsSubmitted, timeline.CellsCompleted, timeline.NumErrors, timeline.BusyTime, timeline.Lifetime())
+	}
+
+	return t
+}
+
+func nodesTable(samples []*NodeSample) *table {
+	t := newTable(TableNodes,
+		column{"timestamp", ColumnTimestamp},
+		column{"node_id", ColumnString},
+		column{"num_pods", ColumnInt64},
+		column{"capacity_cpu", ColumnFloat64},
+		column{"capacity_memory", ColumnFloat64},
+		column{"capacity_gpus", ColumnFloat64},
+		column{"capacity_vgpus", ColumnFloat64},
+		column{"allocated_cpu", ColumnFloat64},
+		column{"allocated_memory", ColumnFloat64},
+		column{"allocated_gpus", ColumnFloat64},
+		column{"allocated_vgpus", ColumnFloat64},
+	)
+
+	for _, s := range samples {
+		t.append(s.Timestamp, s.NodeId, s.NumPods, s.CapacityCPU, s.CapacityMemory, s.CapacityGPUs, s.CapacityVGPUs,
+			s.AllocatedCPU, s.AllocatedMemory, s.AllocatedGPUs, s.AllocatedVGPUs)
+	}
+
+	return t
+}
+
+func requestsTable(samples []*metrics.Sample) *table {
+	t := newTable(TableRequests,
+		column{"timestamp", ColumnTimestamp},
+		column{"operation", ColumnString},
+		column{"kernel_id", ColumnString},
+		column{"outcome", ColumnString},
+		column{"latency_ns", ColumnDuration},
+	)
+
+	for _, s := range samples {
+		t.append(s.Timestamp, s.Operation, s.KernelId, s.Outcome, s.Latency)
+	}
+
+	return t
+}
@@ -0,0 +1,138 @@
+package export
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+// The utilization of a single Kubernetes node at a point in time.
+type NodeSample struct {
+	Timestamp       time.Time `json:"timestamp"`
+	NodeId          string    `json:"node_id"`
+	NumPods         int       `json:"num_pods"`
+	CapacityCPU     float64   `json:"capacity_cpu"`
+	CapacityMemory  float64   `json:"capacity_memory"`
+	CapacityGPUs    float64   `json:"capacity_gpus"`
+	CapacityVGPUs   float64   `json:"capacity_vgpus"`
+	AllocatedCPU    float64   `json:"allocated_cpu"`
+	AllocatedMemory float64   `json:"allocated_memory"`
+	AllocatedGPUs   float64   `json:"allocated_gpus"`
+	AllocatedVGPUs  float64   `json:"allocated_vgpus"`
+}
+
+// Record the utilization of the node at the given time.
+func NewNodeSample(timestamp time.Time, node *domain.KubernetesNode) *NodeSample {
+	return &NodeSample{
+		Timestamp:       timestamp,
+		NodeId:          node.NodeId,
+		NumPods:         len(node.Pods),
+		CapacityCPU:     node.CapacityCPU,
+		CapacityMemory:  node.CapacityMemory,
+		CapacityGPUs:    node.CapacityGPUs,
+		CapacityVGPUs:   node.CapacityVGPUs,
+		AllocatedCPU:    node.AllocatedCPU,
+		AllocatedMemory: node.AllocatedMemory,
+		AllocatedGPUs:   node.AllocatedGPUs,
+		AllocatedVGPUs:  node.AllocatedVGPUs,
+	}
+}
+
+// Samples the utilization of every node, either each time the NodeProvider refreshes its nodes, or periodically.
+type NodeSampler struct {
+	id       string
+	provider domain.NodeProvider // Nil if the nodes are polled instead.
+
+	poll     func() []*domain.KubernetesNode // Returns the current nodes. Nil if they come from the provider.
+	interval time.Duration                   // How often the nodes are polled.
+	stop     chan struct{}                   // Closed to stop polling.
+	stopped  chan struct{}                   // Closed once polling has stopped.
+
+	mutex   sync.Mutex
+	samples []*NodeSample
+}
+
+func NewNodeSampler(provider domain.NodeProvider) *NodeSampler {
+	return &NodeSampler{
+		id:       uuid.New().String(),
+		provider: provider,
+		samples:  make([]*NodeSample, 0),
+	}
+}
+
+// Sample the nodes returned by poll every interval, e.g., the hosts of an in-process fake cluster.
+func NewPollingNodeSampler(poll func() []*domain.KubernetesNode, interval time.Duration) *NodeSampler {
+	return &NodeSampler{
+		id:       uuid.New().String(),
+		poll:     poll,
+		interval: interval,
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		samples:  make([]*NodeSample, 0),
+	}
+}
+
+// Begin sampling. If the nodes come from a provider, then samples are taken whenever it refreshes, so the provider
+// must have been started. Otherwise, the nodes are sampled immediately, and then every interval.
+func (s *NodeSampler) Start() {
+	if s.provider != nil {
+		s.provider.SubscribeToRefreshes(s.id, s.handleNodesRefreshed)
+		return
+	}
+
+	go s.pollNodes()
+}
+
+// Stop sampling. Polling samples the nodes one last time before it stops.
+func (s *NodeSampler) Stop() {
+	if s.provider != nil {
+		s.provider.UnsubscribeFromRefreshes(s.id)
+		return
+	}
+
+	close(s.stop)
+	<-s.stopped
+}
+
+func (s *NodeSampler) pollNodes() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.handleNodesRefreshed(s.poll())
+
+		select {
+		case <-ticker.C:
+		case <-s.stop:
+			s.handleNodesRefreshed(s.poll())
+			return
+		}
+	}
+}
+
+func (s *NodeSampler) handleNodesRefreshed(nodes []*domain.KubernetesNode) bool {
+	now := time.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, node := range nodes {
+		s.samples = append(s.samples, NewNodeSample(now, node))
+	}
+
+	return true
+}
+
+// Return the samples taken so far.
+func (s *NodeSampler) Samples() []*NodeSample {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	samples := make([]*NodeSample, len(s.samples))
+	copy(samples, s.samples)
+	return samples
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"sort"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+)
+
+// Summary statistics of a run.
+type Summary struct {
+	Run       *domain.WorkloadRun `json:"run"`
+	Duration  time.Duration       `json:"duration"` // From when the run started until it finished.
+	Tenants   []*TenantSummary    `json:"tenants"`
+	Latencies metrics.Report      `json:"latencies"` // Latencies of the requests issued during the run.
+}
+
+// Summary statistics of a single tenant's sessions.
+type TenantSummary struct {
+	Tenant          string        `json:"tenant"`
+	Sessions        int           `json:"sessions"`
+	SessionsStopped int           `json:"sessions_stopped"`
+	CellsSubmitted  int           `json:"cells_submitted"`
+	CellsCompleted  int           `json:"cells_completed"`
+	NumErrors       int           `json:"num_errors"`
+	MeanLifetime    time.Duration `json:"mean_lifetime"` // Mean lifetime of the tenant's kernels that were both created and stopped.
+	CellLatencyMean time.Duration `json:"cell_latency_mean"`
+	CellLatencyP50  time.Duration `json:"cell_latency_p50"` // Time from submitting a cell until it completed.
+	CellLatencyP90  time.Duration `json:"cell_latency_p90"`
+	CellLatencyP99  time.Duration `json:"cell_latency_p99"`
+	CellLatencyMax  time.Duration `json:"cell_latency_max"`
+}
+
+func summarize(results *Results, timelines []*KernelTimeline) *Summary {
+	summary := &Summary{
+		Run:       results.Run,
+		Tenants:   make([]*TenantSummary, 0),
+		Latencies: results.Latencies,
+	}
+
+	if results.Run != nil && results.Run.StartedAt != nil && results.Run.FinishedAt != nil {
+		summary.Duration = results.Run.FinishedAt.Sub(*results.Run.StartedAt)
+	}
+
+	tenants := make(map[string]*TenantSummary)
+	lifetimes := make(map[string][]time.Duration)
+	for _, timeline := range timelines {
+		tenant, ok := tenants[timeline.Tenant]
+		if !ok {
+			tenant = &TenantSummary{Tenant: timeline.Tenant}
+			tenants[timeline.Tenant] = tenant
+			summary.Tenants = append(summary.Tenants, tenant)
+		}
+
+		tenant.Sessions++
+		tenant.CellsSubmitted += timeline.CellsSubmitted
+		tenant.CellsCompleted += timeline.CellsCompleted
+		tenant.NumErrors += timeline.NumErrors
+
+		if !timeline.StoppedAt.IsZero() {
+			tenant.SessionsStopped++
+		}
+
+		if lifetime := timeline.Lifetime(); lifetime > 0 {
+			lifetimes[timeline.Tenant] = append(lifetimes[timeline.Tenant], lifetime)
+		}
+	}
+
+	for tenantName, latencies := range cellLatencies(results.Events) {
+		tenant, ok := tenants[tenantName]
+		if !ok {
+			continue
+		}
+
+		histogram := metrics.NewHistogram()
+		for _, latency := range latencies {
+			histogram.Record(latency)
+		}
+
+		tenant.CellLatencyMean = histogram.Mean()
+		tenant.CellLatencyP50 = histogram.Percentile(50)
+		tenant.CellLatencyP90 = histogram.Percentile(90)
+		tenant.CellLatencyP99 = histogram.Percentile(99)
+		tenant.CellLatencyMax = histogram.Max()
+	}
+
+	for tenantName, durations := range lifetimes {
+		var total time.Duration
+		for _, lifetime := range durations {
+			total += lifetime
+		}
+		tenants[tenantName].MeanLifetime = total / time.Duration(len(durations))
+	}
+
+	sort.Slice(summary.Tenants, func(i, j int) bool {
+		return summary.Tenants[i].Tenant < summary.Tenants[j].Tenant
+	})
+
+	return summary
+}
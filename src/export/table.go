@@ -0,0 +1,146 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// The type of the values in a column.
+type ColumnType string
+
+const (
+	ColumnString    ColumnType = "string"
+	ColumnInt64     ColumnType = "int64"
+	ColumnFloat64   ColumnType = "float64"
+	ColumnTimestamp ColumnType = "timestamp[ns]" // A time.Time. Written as RFC 3339 to CSV and JSON Lines, and as nanoseconds since the Unix epoch to the columnar format.
+	ColumnDuration  ColumnType = "duration[ns]"  // A time.Duration, in nanoseconds.
+)
+
+type column struct {
+	name       string
+	columnType ColumnType
+}
+
+// A table of results. Each row has one value per column; a nil value is written as an empty CSV field or a JSON null.
+type table struct {
+	name    string
+	columns []column
+	rows    [][]interface{}
+}
+
+func newTable(name string, columns ...column) *table {
+	return &table{
+		name:    name,
+		columns: columns,
+		rows:    make([][]interface{}, 0),
+	}
+}
+
+func (t *table) append(row ...interface{}) {
+	if len(row) != len(t.columns) {
+		panic(fmt.Sprintf("row of table \"%s\" has %d values, but the table has %d columns", t.name, len(row), len(t.columns)))
+	}
+
+	t.rows = append(t.rows, row)
+}
+
+// Return the value in the given form for the JSON-based formats.
+func jsonValue(value interface{}, columnar bool) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		if columnar {
+			return v.UnixNano()
+		}
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return int64(v)
+	case *time.Duration:
+		if v == nil {
+			return nil
+		}
+		return int64(*v)
+	default:
+		return v
+	}
+}
+
+// Return the value as a CSV field.
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return strconv.FormatInt(int64(v), 10)
+	case *time.Duration:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatInt(int64(*v), 10)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Write the table as CSV, with a header row.
+func (t *table) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		header[i] = col.name
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(t.columns))
+	for _, row := range t.rows {
+		for i, value := range row {
+			record[i] = csvValue(value)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Write the table as JSON Lines, with one object per row.
+func (t *table) writeJSONL(w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+
+	for _, row := range t.rows {
+		object := make(map[string]interface{}, len(t.columns))
+		for i, col := range t.columns {
+			object[col.name] = jsonValue(row[i], false)
+		}
+
+		if err := encoder.Encode(object); err != nil {
+			return err
+		}
+	}
+
+	return buffered.Flush()
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"sort"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+// The lifetime of a single session's kernel over the course of a run, reconstructed from the run's events.
+type KernelTimeline struct {
+	KernelId       string        `json:"kernel_id"` // Empty if the kernel could not be created.
+	SessionId      string        `json:"session_id"`
+	Tenant         string        `json:"tenant"`
+	FirstEventAt   time.Time     `json:"first_event_at"`
+	CreatedAt      time.Time     `json:"created_at"` // Zero if the kernel was never created.
+	FirstCellAt    time.Time     `json:"first_cell_at"`
+	LastCellAt     time.Time     `json:"last_cell_at"` // When the last cell completed.
+	StoppedAt      time.Time     `json:"stopped_at"`   // Zero if the session was never stopped.
+	CellsSubmitted int           `json:"cells_submitted"`
+	CellsCompleted int           `json:"cells_completed"`
+	NumErrors      int           `json:"num_errors"`
+	BusyTime       time.Duration `json:"busy_time"` // Total time spent executing cells.
+}
+
+// How long the kernel was alive, or zero if it was never created or never stopped.
+func (t *KernelTimeline) Lifetime() time.Duration {
+	if t.CreatedAt.IsZero() || t.StoppedAt.IsZero() {
+		return 0
+	}
+
+	return t.StoppedAt.Sub(t.CreatedAt)
+}
+
+// Reconstruct the timeline of every session's kernel from the events of a run. Timelines are sorted by their first event.
+func KernelTimelines(events []*domain.WorkloadEvent) []*KernelTimeline {
+	sorted := make([]*domain.WorkloadEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	timelines := make(map[string]*KernelTimeline)
+	order := make([]*KernelTimeline, 0)
+	submittedAt := make(map[string]map[int]time.Time) // Session ID -> cell index -> submission time.
+
+	for _, event := range sorted {
+		timeline, ok := timelines[event.SessionId]
+		if !ok {
+			timeline = &KernelTimeline{
+				SessionId:    event.SessionId,
+				Tenant:       event.Tenant,
+				FirstEventAt: event.Timestamp,
+			}
+			timelines[event.SessionId] = timeline
+			order = append(order, timeline)
+			submittedAt[event.SessionId] = make(map[int]time.Time)
+		}
+
+		if timeline.KernelId == "" {
+			timeline.KernelId = event.KernelId
+		}
+
+		switch event.Type {
+		case domain.WorkloadEventSessionCreated:
+			timeline.CreatedAt = event.Timestamp
+		case domain.WorkloadEventCellSubmitted:
+			timeline.CellsSubmitted++
+			if timeline.FirstCellAt.IsZero() {
+				timeline.FirstCellAt = event.Timestamp
+			}
+			submittedAt[event.SessionId][event.CellIndex] = event.Timestamp
+		case domain.WorkloadEventCellCompleted:
+			timeline.CellsCompleted++
+			timeline.LastCellAt = event.Timestamp
+			if submitted, ok := submittedAt[event.SessionId][event.CellIndex]; ok {
+				timeline.BusyTime += event.Timestamp.Sub(submitted)
+			}
+		case domain.WorkloadEventSessionStopped:
+			timeline.StoppedAt = event.Timestamp
+		case domain.WorkloadEventError:
+			timeline.NumErrors++
+		}
+	}
+
+	return order
+}
+
+// Return the time at which the given cell was submitted and completed, for every cell that completed.
+func cellLatencies(events []*domain.WorkloadEvent) map[string][]time.Duration {
+	type cellKey struct {
+		sessionId string
+		cellIndex int
+	}
+
+	submittedAt := make(map[cellKey]time.Time)
+	latencies := make(map[string][]time.Duration) // Tenant -> latencies.
+	for _, event := range events {
+		key := cellKey{event.SessionId, event.CellIndex}
+		switch event.Type {
+		case domain.WorkloadEventCellSubmitted:
+			submittedAt[key] = event.Timestamp
+		case domain.WorkloadEventCellCompleted:
+			if submitted, ok := submittedAt[key]; ok {
+				latencies[event.Tenant] = append(latencies[event.Tenant], event.Timestamp.Sub(submitted))
+			}
+		}
+	}
+
+	return latencies
+}
@@ -0,0 +1,494 @@
+package jupyter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+// Endpoints of the Jupyter Server's REST API.
+const (
+	versionEndpoint     = "/api"
+	kernelSpecsEndpoint = "/api/kernelspecs"
+	kernelsEndpoint     = "/api/kernels"
+	sessionsEndpoint    = "/api/sessions"
+	contentsEndpoint    = "/api/contents"
+)
+
+const (
+	defaultRequestTimeout = time.Second * 30
+	defaultMaxRetries     = 3
+	defaultRetryInterval  = time.Millisecond * 500
+)
+
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrUnexpectedStatus = errors.New("unexpected response from the Jupyter Server")
+)
+
+type ClientOptions struct {
+	Token         string        // If set, then requests are authenticated with this token.
+	Timeout       time.Duration // Timeout of each attempt of a request. Zero means defaultRequestTimeout.
+	MaxRetries    int           // Number of times a failed idempotent request is retried. Negative means never.
+	RetryInterval time.Duration // Delay before the first retry, which doubles with each subsequent retry.
+	HttpClient    *http.Client  // Defaults to http.DefaultClient.
+}
+
+// Typed client of the Jupyter Server's REST API.
+//
+// Requests that fail due to a network error or a 5xx/429 response are retried with exponential backoff, unless they
+// would not be safe to repeat (i.e., POSTs).
+type Client struct {
+	address       string // Address of the Jupyter Server, including the scheme (e.g., "http://localhost:8888").
+	token         string
+	timeout       time.Duration
+	maxRetries    int
+	retryInterval time.Duration
+	httpClient    *http.Client
+}
+
+// Create a client of the Jupyter Server at the given address. The options may be nil.
+func NewClient(address string, opts *ClientOptions) *Client {
+	client := &Client{
+		address:       strings.TrimSuffix(address, "/"),
+		timeout:       defaultRequestTimeout,
+		maxRetries:    defaultMaxRetries,
+		retryInterval: defaultRetryInterval,
+		httpClient:    http.DefaultClient,
+	}
+
+	if opts == nil {
+		return client
+	}
+
+	client.token = opts.Token
+	if opts.Timeout > 0 {
+		client.timeout = opts.Timeout
+	}
+	if opts.MaxRetries != 0 {
+		client.maxRetries = max(opts.MaxRetries, 0)
+	}
+	if opts.RetryInterval > 0 {
+		client.retryInterval = opts.RetryInterval
+	}
+	if opts.HttpClient != nil {
+		client.httpClient = opts.HttpClient
+	}
+
+	return client
+}
+
+func (c *Client) Address() string {
+	return c.address
+}
+
+// Return the headers with which to authenticate with the Jupyter Server.
+func (c *Client) authHeader() http.Header {
+	header := make(http.Header)
+	if c.token != "" {
+		header.Set("Authorization", "token "+c.token)
+	}
+
+	return header
+}
+
+// A kernel running on the Jupyter Server.
+type Kernel struct {
+	Id             string    `json:"id"`
+	Name           string    `json:"name"` // Name of the kernel spec.
+	LastActivity   time.Time `json:"last_activity"`
+	ExecutionState string    `json:"execution_state"`
+	Connections    int       `json:"connections"`
+}
+
+// A session associates a notebook (or other document) with a kernel.
+type Session struct {
+	Id     string  `json:"id"`
+	Path   string  `json:"path"`
+	Name   string  `json:"name"`
+	Type   string  `json:"type"`
+	Kernel *Kernel `json:"kernel"`
+}
+
+type CreateSessionRequest struct {
+	Path       string
+	Name       string
+	Type       string        // E.g., "notebook". Defaults to "notebook".
+	KernelName string        // Name of the kernel spec of the session's kernel.
+	Resources  *ResourceSpec // Resources of each of the kernel's replicas, if the kernel is distributed. Nil to use the kernel spec's defaults.
+}
+
+// The resources with which the gateway provisioner creates each replica of a distributed kernel. Sent alongside the
+// kernel in the body of the request that creates the session, in the units used by the Cluster Gateway.
+type ResourceSpec struct {
+	Cpu    int32 `json:"cpu"`    // In 1/100 core.
+	Memory int32 `json:"memory"` // In MB.
+	Gpu    int32 `json:"gpu"`    // In 1/100 GPU.
+}
+
+// A file or directory managed by the Jupyter Server.
+type Contents struct {
+	Name         string          `json:"name"`
+	Path         string          `json:"path"`
+	Type         string          `json:"type"`   // One of "directory", "file", or "notebook".
+	Format       string          `json:"format"` // One of "json", "text", or "base64". Empty if the content wasn't requested.
+	Mimetype     string          `json:"mimetype"`
+	Writable     bool            `json:"writable"`
+	Created      time.Time       `json:"created"`
+	LastModified time.Time       `json:"last_modified"`
+	Size         *int64          `json:"size"`
+	Content      json.RawMessage `json:"content"` // For directories, a list of Contents without their content.
+}
+
+// Decode the entries of a directory.
+func (c *Contents) Entries() ([]*Contents, error) {
+	if c.Type != "directory" {
+		return nil, fmt.Errorf("%s is a %s, not a directory", c.Path, c.Type)
+	}
+
+	var entries []*Contents
+	if err := json.Unmarshal(c.Content, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// The JSON representation of a kernel spec used by the /api/kernelspecs endpoint.
+type kernelSpecModel struct {
+	Name string `json:"name"`
+	Spec struct {
+		ArgV          []string `json:"argv"`
+		DisplayName   string   `json:"display_name"`
+		Language      string   `json:"language"`
+		InterruptMode string   `json:"interrupt_mode"`
+		Metadata      struct {
+			KernelProvisioner *struct {
+				Name   string `json:"provisioner_name"`
+				Config struct {
+					Gateway string `json:"gateway"`
+				} `json:"config"`
+			} `json:"kernel_provisioner"`
+		} `json:"metadata"`
+	} `json:"spec"`
+}
+
+func (m *kernelSpecModel) toKernelSpec() *domain.KernelSpec {
+	spec := &domain.KernelSpec{
+		Name:          m.Name,
+		DisplayName:   m.Spec.DisplayName,
+		Language:      m.Spec.Language,
+		InterruptMode: m.Spec.InterruptMode,
+		ArgV:          m.Spec.ArgV,
+	}
+
+	// Jupyter defaults to interrupting kernels with a signal.
+	if spec.InterruptMode == "" {
+		spec.InterruptMode = "signal"
+	}
+
+	if provisioner := m.Spec.Metadata.KernelProvisioner; provisioner != nil {
+		spec.KernelProvisioner = &domain.KernelProvisioner{
+			Name:    provisioner.Name,
+			Gateway: provisioner.Config.Gateway,
+		}
+	}
+
+	return spec
+}
+
+// Return the version of the Jupyter Server.
+func (c *Client) Version(ctx context.Context) (string, error) {
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err := c.do(ctx, http.MethodGet, versionEndpoint, nil, &version); err != nil {
+		return "", err
+	}
+
+	if version.Version == "" {
+		return "", fmt.Errorf("%w: the response to GET %s did not include a version", ErrUnexpectedStatus, versionEndpoint)
+	}
+
+	return version.Version, nil
+}
+
+// Return the kernel specs available on the Jupyter Server, sorted by name, along with the name of the default one.
+func (c *Client) KernelSpecs(ctx context.Context) ([]*domain.KernelSpec, string, error) {
+	var resp struct {
+		Default     string                      `json:"default"`
+		KernelSpecs map[string]*kernelSpecModel `json:"kernelspecs"`
+	}
+	if err := c.do(ctx, http.MethodGet, kernelSpecsEndpoint, nil, &resp); err != nil {
+		return nil, "", err
+	}
+
+	specs := make([]*domain.KernelSpec, 0, len(resp.KernelSpecs))
+	for name, model := range resp.KernelSpecs {
+		if model.Name == "" {
+			model.Name = name
+		}
+		specs = append(specs, model.toKernelSpec())
+	}
+
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+
+	return specs, resp.Default, nil
+}
+
+func (c *Client) ListKernels(ctx context.Context) ([]*Kernel, error) {
+	kernels := make([]*Kernel, 0)
+	if err := c.do(ctx, http.MethodGet, kernelsEndpoint, nil, &kernels); err != nil {
+		return nil, err
+	}
+
+	return kernels, nil
+}
+
+func (c *Client) GetKernel(ctx context.Context, kernelId string) (*Kernel, error) {
+	var kernel Kernel
+	if err := c.do(ctx, http.MethodGet, kernelsEndpoint+"/"+url.PathEscape(kernelId), nil, &kernel); err != nil {
+		return nil, err
+	}
+
+	return &kernel, nil
+}
+
+// Start a kernel of the specified kernel spec. If the kernel spec is empty, then the default kernel spec is used.
+func (c *Client) StartKernel(ctx context.Context, kernelSpec string) (*Kernel, error) {
+	body := map[string]interface{}{}
+	if kernelSpec != "" {
+		body["name"] = kernelSpec
+	}
+
+	var kernel Kernel
+	if err := c.do(ctx, http.MethodPost, kernelsEndpoint, body, &kernel); err != nil {
+		return nil, err
+	}
+
+	return &kernel, nil
+}
+
+func (c *Client) DeleteKernel(ctx context.Context, kernelId string) error {
+	return c.do(ctx, http.MethodDelete, kernelsEndpoint+"/"+url.PathEscape(kernelId), nil, nil)
+}
+
+func (c *Client) InterruptKernel(ctx context.Context, kernelId string) error {
+	return c.do(ctx, http.MethodPost, kernelsEndpoint+"/"+url.PathEscape(kernelId)+"/interrupt", nil, nil)
+}
+
+func (c *Client) RestartKernel(ctx context.Context, kernelId string) (*Kernel, error) {
+	var kernel Kernel
+	if err := c.do(ctx, http.MethodPost, kernelsEndpoint+"/"+url.PathEscape(kernelId)+"/restart", nil, &kernel); err != nil {
+		return nil, err
+	}
+
+	return &kernel, nil
+}
+
+func (c *Client) ListSessions(ctx context.Context) ([]*Session, error) {
+	sessions := make([]*Session, 0)
+	if err := c.do(ctx, http.MethodGet, sessionsEndpoint, nil, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (c *Client) GetSession(ctx context.Context, sessionId string) (*Session, error) {
+	var session Session
+	if err := c.do(ctx, http.MethodGet, sessionsEndpoint+"/"+url.PathEscape(sessionId), nil, &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Create a session, starting a new kernel for it. If a session already exists for the path, then it is returned instead.
+func (c *Client) CreateSession(ctx context.Context, req *CreateSessionRequest) (*Session, error) {
+	sessionType := req.Type
+	if sessionType == "" {
+		sessionType = "notebook"
+	}
+
+	body := map[string]interface{}{
+		"path": req.Path,
+		"name": req.Name,
+		"type": sessionType,
+		"kernel": map[string]interface{}{
+			"name": req.KernelName,
+		},
+	}
+	if req.Resources != nil {
+		body["resource_spec"] = req.Resources
+	}
+
+	var session Session
+	if err := c.do(ctx, http.MethodPost, sessionsEndpoint, body, &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Delete the session, shutting down its kernel.
+func (c *Client) DeleteSession(ctx context.Context, sessionId string) error {
+	return c.do(ctx, http.MethodDelete, sessionsEndpoint+"/"+url.PathEscape(sessionId), nil, nil)
+}
+
+// Return the file or directory at the path, which is relative to the Jupyter Server's root directory.
+// If withContent is false, then only the model is returned (e.g., to check that a file exists).
+func (c *Client) GetContents(ctx context.Context, path string, withContent bool) (*Contents, error) {
+	endpoint := contentsEndpoint + "/" + escapePath(path)
+	if !withContent {
+		endpoint += "?content=0"
+	}
+
+	var contents Contents
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &contents); err != nil {
+		return nil, err
+	}
+
+	return &contents, nil
+}
+
+// Create or overwrite the file or notebook at the path. The content must be of the given format
+// (i.e., a JSON notebook, or a text or base64 string).
+func (c *Client) SaveContents(ctx context.Context, path string, contentType string, format string, content interface{}) (*Contents, error) {
+	body := map[string]interface{}{
+		"type":    contentType,
+		"format":  format,
+		"content": content,
+	}
+
+	var contents Contents
+	if err := c.do(ctx, http.MethodPut, contentsEndpoint+"/"+escapePath(path), body, &contents); err != nil {
+		return nil, err
+	}
+
+	return &contents, nil
+}
+
+func (c *Client) DeleteContents(ctx context.Context, path string) error {
+	return c.do(ctx, http.MethodDelete, contentsEndpoint+"/"+escapePath(path), nil, nil)
+}
+
+// Escape each segment of a slash-separated path.
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// Issue a request, retrying it if it fails transiently and is safe to repeat. If out is non-nil, then the response is decoded into it.
+func (c *Client) do(ctx context.Context, method string, endpoint string, body interface{}, out interface{}) error {
+	var encoded []byte
+	if body != nil {
+		var err error
+		if encoded, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	retries := 0
+	if method != http.MethodPost {
+		retries = c.maxRetries
+	}
+
+	backoff := c.retryInterval
+	for attempt := 0; ; attempt++ {
+		retryable, err := c.attempt(ctx, method, endpoint, encoded, out)
+
+		// Only the timeout of an attempt may expire; if the caller's context is done, then we give up.
+		if err == nil || !retryable || attempt >= retries || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		}
+	}
+}
+
+// Issue a request once, bounded by the client's timeout. Returns whether the request may succeed if retried.
+func (c *Client) attempt(ctx context.Context, method string, endpoint string, body []byte, out interface{}) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.address+endpoint, reader)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header = c.authHeader()
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("%s %s: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true, fmt.Errorf("%s %s: %w", method, endpoint, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, fmt.Errorf("%w: %s %s", ErrNotFound, method, endpoint)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retryable, fmt.Errorf("%w: %s %s returned %d: %s", ErrUnexpectedStatus, method, endpoint, resp.StatusCode, errorMessage(respBody))
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return false, fmt.Errorf("%s %s: failed to decode response: %w", method, endpoint, err)
+	}
+
+	return false, nil
+}
+
+// Extract the message from the body of an error response, which the Jupyter Server sends as JSON when it can.
+func errorMessage(body []byte) string {
+	var resp struct {
+		Message string `json:"message"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && (resp.Message != "" || resp.Reason != "") {
+		return strings.TrimSpace(resp.Reason + " " + resp.Message)
+	}
+
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,406 @@
+package jupyter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Start a Jupyter Server that responds to every request with the handler, and a client of it that retries quickly.
+func newTestClient(t *testing.T, token string, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewClient(server.URL+"/", &ClientOptions{
+		Token:         token,
+		MaxRetries:    3,
+		RetryInterval: time.Millisecond,
+		Timeout:       time.Second * 5,
+	})
+}
+
+func TestClientAuthHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+	}{
+		{name: "token", token: "secret", header: "token secret"},
+		{name: "no token", token: "", header: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var header string
+			client := newTestClient(t, test.token, func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Get("Authorization")
+				w.Write([]byte(`{"version": "2.14.0"}`))
+			})
+
+			version, err := client.Version(context.Background())
+			if err != nil {
+				t.Fatalf("Version returned an error: %v", err)
+			}
+			if version != "2.14.0" {
+				t.Errorf("Version returned %q, expected %q", version, "2.14.0")
+			}
+			if header != test.header {
+				t.Errorf("Authorization header was %q, expected %q", header, test.header)
+			}
+		})
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "notebook.ipynb", expected: "notebook.ipynb"},
+		{path: "/dir/notebook.ipynb/", expected: "dir/notebook.ipynb"},
+		{path: "my dir/a?b#c.ipynb", expected: "my%20dir/a%3Fb%23c.ipynb"},
+		{path: "100%/x", expected: "100%25/x"},
+	}
+
+	for _, test := range tests {
+		if escaped := escapePath(test.path); escaped != test.expected {
+			t.Errorf("escapePath(%q) returned %q, expected %q", test.path, escaped, test.expected)
+		}
+	}
+}
+
+func TestClientEscapesPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(c *Client) error
+		expected string // The escaped path of the request.
+	}{
+		{
+			name:     "kernel ID",
+			call:     func(c *Client) error { _, err := c.GetKernel(context.Background(), "a/b c"); return err },
+			expected: "/api/kernels/a%2Fb%20c",
+		},
+		{
+			name:     "session ID",
+			call:     func(c *Client) error { return c.DeleteSession(context.Background(), "a?b") },
+			expected: "/api/sessions/a%3Fb",
+		},
+		{
+			name: "contents path",
+			call: func(c *Client) error {
+				_, err := c.GetContents(context.Background(), "my dir/a#b.ipynb", true)
+				return err
+			},
+			expected: "/api/contents/my%20dir/a%23b.ipynb",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var path string
+			client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.EscapedPath()
+				w.Write([]byte(`{}`))
+			})
+
+			if err := test.call(client); err != nil {
+				t.Fatalf("Request returned an error: %v", err)
+			}
+			if path != test.expected {
+				t.Errorf("Requested path %q, expected %q", path, test.expected)
+			}
+		})
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		status           int
+		expectedErr      error
+		expectedAttempts int32
+	}{
+		{name: "not found", method: http.MethodGet, status: http.StatusNotFound, expectedErr: ErrNotFound, expectedAttempts: 1},
+		{name: "server error", method: http.MethodGet, status: http.StatusInternalServerError, expectedErr: ErrUnexpectedStatus, expectedAttempts: 4},
+		{name: "too many requests", method: http.MethodGet, status: http.StatusTooManyRequests, expectedErr: ErrUnexpectedStatus, expectedAttempts: 4},
+		{name: "bad request", method: http.MethodGet, status: http.StatusBadRequest, expectedErr: ErrUnexpectedStatus, expectedAttempts: 1},
+		{name: "delete server error", method: http.MethodDelete, status: http.StatusServiceUnavailable, expectedErr: ErrUnexpectedStatus, expectedAttempts: 4},
+		{name: "post server error", method: http.MethodPost, status: http.StatusInternalServerError, expectedErr: ErrUnexpectedStatus, expectedAttempts: 1},
+		{name: "post too many requests", method: http.MethodPost, status: http.StatusTooManyRequests, expectedErr: ErrUnexpectedStatus, expectedAttempts: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				if r.Method != test.method {
+					t.Errorf("Received %s request, expected %s", r.Method, test.method)
+				}
+				w.WriteHeader(test.status)
+			})
+
+			err := client.do(context.Background(), test.method, kernelsEndpoint, nil, nil)
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("Request returned %v, expected %v", err, test.expectedErr)
+			}
+			if n := attempts.Load(); n != test.expectedAttempts {
+				t.Errorf("Made %d attempts, expected %d", n, test.expectedAttempts)
+			}
+		})
+	}
+}
+
+func TestClientRetrySucceeds(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[{"id": "k1", "name": "python3"}]`))
+	})
+
+	kernels, err := client.ListKernels(context.Background())
+	if err != nil {
+		t.Fatalf("ListKernels returned an error: %v", err)
+	}
+	if len(kernels) != 1 || kernels[0].Id != "k1" {
+		t.Errorf("ListKernels returned %+v, expected kernel k1", kernels)
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Errorf("Made %d attempts, expected 3", n)
+	}
+}
+
+func TestClientRetryBacksOff(t *testing.T) {
+	var attempts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts = append(attempts, time.Now())
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	interval := time.Millisecond * 20
+	client := NewClient(server.URL, &ClientOptions{MaxRetries: 2, RetryInterval: interval})
+
+	if _, err := client.ListKernels(context.Background()); !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("ListKernels returned %v, expected %v", err, ErrUnexpectedStatus)
+	}
+
+	if len(attempts) != 3 {
+		t.Fatalf("Made %d attempts, expected 3", len(attempts))
+	}
+
+	// The delay before each retry doubles.
+	for i, expected := range []time.Duration{interval, interval * 2} {
+		if delay := attempts[i+1].Sub(attempts[i]); delay < expected {
+			t.Errorf("Retry %d was made after %v, expected at least %v", i+1, delay, expected)
+		}
+	}
+}
+
+func TestClientCancellationStopsRetries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		cancel()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, &ClientOptions{MaxRetries: 5, RetryInterval: time.Hour})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.ListSessions(ctx)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, ErrUnexpectedStatus) {
+			t.Errorf("ListSessions returned %v, expected the request to be cancelled", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("ListSessions kept retrying after its context was cancelled")
+	}
+
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("Made %d attempts, expected 1", n)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{name: "message", body: `{"message": "Kernel does not exist"}`, expected: "Kernel does not exist"},
+		{name: "reason and message", body: `{"reason": "Forbidden", "message": "Invalid token"}`, expected: "Forbidden Invalid token"},
+		{name: "reason", body: `{"reason": "Not Found"}`, expected: "Not Found"},
+		{name: "json without message", body: `{"code": 500}`, expected: `{"code": 500}`},
+		{name: "plain text", body: "  Internal Server Error\n", expected: "Internal Server Error"},
+		{name: "empty", body: "", expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if message := errorMessage([]byte(test.body)); message != test.expected {
+				t.Errorf("errorMessage(%q) returned %q, expected %q", test.body, message, test.expected)
+			}
+		})
+	}
+}
+
+func TestClientErrorIncludesMessage(t *testing.T) {
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message": "No such kernel spec: foo"}`))
+	})
+
+	_, err := client.StartKernel(context.Background(), "foo")
+	if !errors.Is(err, ErrUnexpectedStatus) {
+		t.Fatalf("StartKernel returned %v, expected %v", err, ErrUnexpectedStatus)
+	}
+
+	expected := "unexpected response from the Jupyter Server: POST /api/kernels returned 400: No such kernel spec: foo"
+	if err.Error() != expected {
+		t.Errorf("StartKernel returned %q, expected %q", err.Error(), expected)
+	}
+}
+
+func TestClientKernelSpecs(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		expectedNames   []string
+		expectedDefault string
+	}{
+		{
+			name: "sorted by name",
+			body: `{"default": "python3", "kernelspecs": {
+				"python3": {"name": "python3", "spec": {"display_name": "Python 3", "language": "python"}},
+				"distributed": {"name": "distributed", "spec": {"display_name": "Distributed Python 3", "language": "python"}},
+				"ir": {"name": "ir", "spec": {"display_name": "R", "language": "R"}}
+			}}`,
+			expectedNames:   []string{"distributed", "ir", "python3"},
+			expectedDefault: "python3",
+		},
+		{
+			name:            "name from key",
+			body:            `{"default": "b", "kernelspecs": {"b": {"spec": {}}, "a": {"spec": {}}}}`,
+			expectedNames:   []string{"a", "b"},
+			expectedDefault: "b",
+		},
+		{
+			name:            "none",
+			body:            `{"kernelspecs": {}}`,
+			expectedNames:   []string{},
+			expectedDefault: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != kernelSpecsEndpoint {
+					t.Errorf("Requested %s, expected %s", r.URL.Path, kernelSpecsEndpoint)
+				}
+				w.Write([]byte(test.body))
+			})
+
+			specs, defaultSpec, err := client.KernelSpecs(context.Background())
+			if err != nil {
+				t.Fatalf("KernelSpecs returned an error: %v", err)
+			}
+
+			if defaultSpec != test.expectedDefault {
+				t.Errorf("KernelSpecs returned default %q, expected %q", defaultSpec, test.expectedDefault)
+			}
+
+			if len(specs) != len(test.expectedNames) {
+				t.Fatalf("KernelSpecs returned %d specs, expected %d", len(specs), len(test.expectedNames))
+			}
+			for i, spec := range specs {
+				if spec.Name != test.expectedNames[i] {
+					t.Errorf("Spec %d is %q, expected %q", i, spec.Name, test.expectedNames[i])
+				}
+				if spec.InterruptMode != "signal" {
+					t.Errorf("Spec %q has interrupt mode %q, expected the default of \"signal\"", spec.Name, spec.InterruptMode)
+				}
+			}
+		})
+	}
+}
+
+func TestClientKernelSpecProvisioner(t *testing.T) {
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"default": "distributed", "kernelspecs": {"distributed": {"name": "distributed", "spec": {
+			"interrupt_mode": "message",
+			"metadata": {"kernel_provisioner": {"provisioner_name": "gateway-provisioner", "config": {"gateway": "gateway:8080"}}}
+		}}}}`))
+	})
+
+	specs, _, err := client.KernelSpecs(context.Background())
+	if err != nil {
+		t.Fatalf("KernelSpecs returned an error: %v", err)
+	}
+	if len(specs) != 1 {
+		t.Fatalf("KernelSpecs returned %d specs, expected 1", len(specs))
+	}
+
+	spec := specs[0]
+	if spec.InterruptMode != "message" {
+		t.Errorf("Spec has interrupt mode %q, expected \"message\"", spec.InterruptMode)
+	}
+	if spec.KernelProvisioner == nil || spec.KernelProvisioner.Name != "gateway-provisioner" || spec.KernelProvisioner.Gateway != "gateway:8080" {
+		t.Errorf("Spec has provisioner %+v, expected gateway-provisioner at gateway:8080", spec.KernelProvisioner)
+	}
+}
+
+func TestClientCreateSessionResources(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources *ResourceSpec
+		expected  string
+	}{
+		{name: "no resources", resources: nil, expected: ""},
+		{name: "resources", resources: &ResourceSpec{Cpu: 150, Memory: 2048, Gpu: 100}, expected: `{"cpu":150,"memory":2048,"gpu":100}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var body map[string]json.RawMessage
+			client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+				w.Write([]byte(`{"id": "session", "kernel": {"id": "kernel", "name": "distributed"}}`))
+			})
+
+			if _, err := client.CreateSession(context.Background(), &CreateSessionRequest{Path: "session", KernelName: "distributed", Resources: test.resources}); err != nil {
+				t.Fatalf("CreateSession returned an error: %v", err)
+			}
+
+			if resources := string(body["resource_spec"]); resources != test.expected {
+				t.Errorf("Session was created with resource spec %q, expected %q", resources, test.expected)
+			}
+			if kernel := string(body["kernel"]); kernel != `{"name":"distributed"}` {
+				t.Errorf("Session was created with kernel %s, expected the distributed kernel", kernel)
+			}
+		})
+	}
+}
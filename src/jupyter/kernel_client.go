@@ -0,0 +1,170 @@
+package jupyter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+const (
+	// Jupyter Server websocket endpoint for communicating with a kernel. Must be formatted with the kernel's ID.
+	kernelChannelsEndpoint = "/api/kernels/%s/channels"
+
+	// Name with which we sign the messages that we send.
+	username = "workload-driver"
+
+	// Maximum size of a single message received from a kernel. Rich outputs, such as images, can be large.
+	messageReadLimit = 1 << 24
+)
+
+var (
+	ErrChannelClosed = errors.New("the kernel's channels were closed before the execution completed")
+)
+
+// Executes code on kernels via the Jupyter Server's kernel websocket, over which the kernel's shell and IOPub channels are multiplexed.
+type KernelClient struct {
+	client *Client // Provides the address of the Jupyter Server and the credentials with which to authenticate.
+}
+
+func NewKernelClient(client *Client) *KernelClient {
+	return &KernelClient{
+		client: client,
+	}
+}
+
+// Submit an execute_request over the kernel's shell channel, passing the outputs published on its IOPub channel to
+// the handler. Returns once the kernel has replied and has finished publishing outputs (i.e., has become idle).
+func (k *KernelClient) Execute(ctx context.Context, req *domain.ExecuteRequest, onOutput func(*domain.ExecutionOutput)) (*domain.ExecuteReply, error) {
+	if req.ReplicaId != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrReplicaExecutionUnsupported, req)
+	}
+
+	session := uuid.New().String()
+	url := strings.Replace(k.client.Address(), "http", "ws", 1) + fmt.Sprintf(kernelChannelsEndpoint, req.KernelId) + "?session_id=" + session
+
+	// Websockets can't carry an Authorization header when compiled to WebAssembly, so we authenticate with the query instead.
+	if k.client.token != "" {
+		url += "&token=" + neturl.QueryEscape(k.client.token)
+	}
+
+	c, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer c.CloseNow()
+
+	c.SetReadLimit(messageReadLimit)
+
+	request := newMessage(MsgExecuteRequest, ChannelShell, session, username, map[string]interface{}{
+		"code":             req.Code,
+		"silent":           false,
+		"store_history":    true,
+		"user_expressions": map[string]interface{}{},
+		"allow_stdin":      false,
+		"stop_on_error":    true,
+	})
+
+	if err := wsjson.Write(ctx, c, request); err != nil {
+		return nil, err
+	}
+
+	var reply *domain.ExecuteReply
+	idle := false
+
+	for reply == nil || !idle {
+		var msg Message
+		if err := wsjson.Read(ctx, c, &msg); err != nil {
+			if websocket.CloseStatus(err) != -1 {
+				return nil, fmt.Errorf("%w: %v", ErrChannelClosed, err)
+			}
+
+			return nil, err
+		}
+
+		// Other clients of the kernel may be executing code too.
+		if msg.ParentHeader.MsgId != request.Header.MsgId {
+			continue
+		}
+
+		switch {
+		case msg.Channel == ChannelShell && msg.Header.MsgType == MsgExecuteReply:
+			if reply, err = parseExecuteReply(&msg); err != nil {
+				return nil, err
+			}
+		case msg.Channel == ChannelIOPub && msg.Header.MsgType == MsgStatus:
+			idle = msg.contentString("execution_state") == "idle"
+		case msg.Channel == ChannelIOPub:
+			if output := parseOutput(&msg); output != nil && onOutput != nil {
+				onOutput(output)
+			}
+		}
+	}
+
+	c.Close(websocket.StatusNormalClosure, "")
+
+	return reply, nil
+}
+
+func parseExecuteReply(msg *Message) (*domain.ExecuteReply, error) {
+	content, err := json.Marshal(msg.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply domain.ExecuteReply
+	if err := json.Unmarshal(content, &reply); err != nil {
+		return nil, err
+	}
+
+	return &reply, nil
+}
+
+// Convert an IOPub message to an output. Returns nil if the message isn't an output (e.g., it is an execute_input).
+func parseOutput(msg *Message) *domain.ExecutionOutput {
+	switch msg.Header.MsgType {
+	case MsgStream:
+		return &domain.ExecutionOutput{
+			Type: domain.ExecutionOutputStream,
+			Name: msg.contentString("name"),
+			Text: msg.contentString("text"),
+		}
+	case MsgExecuteResult, MsgDisplayData:
+		data, _ := msg.Content["data"].(map[string]interface{})
+		text, _ := data["text/plain"].(string)
+
+		return &domain.ExecutionOutput{
+			Type: domain.ExecutionOutputType(msg.Header.MsgType),
+			Text: text,
+			Data: data,
+		}
+	case MsgError:
+		lines := make([]string, 0)
+		if traceback, ok := msg.Content["traceback"].([]interface{}); ok {
+			for _, line := range traceback {
+				if s, ok := line.(string); ok {
+					lines = append(lines, s)
+				}
+			}
+		}
+
+		if len(lines) == 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", msg.contentString("ename"), msg.contentString("evalue")))
+		}
+
+		return &domain.ExecutionOutput{
+			Type: domain.ExecutionOutputError,
+			Name: msg.contentString("ename"),
+			Text: strings.Join(lines, "\n"),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,27 @@
+package jupyter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+func TestKernelClientRejectsReplicaExecution(t *testing.T) {
+	connected := false
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		connected = true
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	replicaId := int32(1)
+	_, err := NewKernelClient(client).Execute(context.Background(), &domain.ExecuteRequest{KernelId: "kernel", ReplicaId: &replicaId, Code: "1 + 1"}, nil)
+	if !errors.Is(err, domain.ErrReplicaExecutionUnsupported) {
+		t.Errorf("Execute returned %v, expected %v", err, domain.ErrReplicaExecutionUnsupported)
+	}
+	if connected {
+		t.Error("Execute connected to the kernel, rather than rejecting the request up front.")
+	}
+}
@@ -0,0 +1,70 @@
+package jupyter
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Version of the Jupyter messaging protocol that we speak.
+const protocolVersion = "5.3"
+
+// Channels over which messages are exchanged with a kernel.
+const (
+	ChannelShell = "shell"
+	ChannelIOPub = "iopub"
+)
+
+// Types of the messages exchanged with a kernel while it executes code.
+const (
+	MsgExecuteRequest = "execute_request"
+	MsgExecuteReply   = "execute_reply"
+	MsgExecuteInput   = "execute_input"
+	MsgExecuteResult  = "execute_result"
+	MsgStream         = "stream"
+	MsgDisplayData    = "display_data"
+	MsgError          = "error"
+	MsgStatus         = "status"
+)
+
+// The header of a message. Every field is omitted if empty, so that an empty parent header is sent as {}.
+type Header struct {
+	MsgId    string `json:"msg_id,omitempty"`
+	MsgType  string `json:"msg_type,omitempty"`
+	Username string `json:"username,omitempty"`
+	Session  string `json:"session,omitempty"`
+	Date     string `json:"date,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+// A message of the Jupyter messaging protocol, in the JSON form used by the Jupyter Server's kernel websocket.
+type Message struct {
+	Header       Header                 `json:"header"`
+	ParentHeader Header                 `json:"parent_header"`
+	Metadata     map[string]interface{} `json:"metadata"`
+	Content      map[string]interface{} `json:"content"`
+	Channel      string                 `json:"channel"`
+}
+
+// Create a message of the given type, to be sent over the given channel as part of the given session.
+func newMessage(msgType string, channel string, session string, username string, content map[string]interface{}) *Message {
+	return &Message{
+		Header: Header{
+			MsgId:    uuid.New().String(),
+			MsgType:  msgType,
+			Username: username,
+			Session:  session,
+			Date:     time.Now().UTC().Format(time.RFC3339Nano),
+			Version:  protocolVersion,
+		},
+		Metadata: make(map[string]interface{}),
+		Content:  content,
+		Channel:  channel,
+	}
+}
+
+// Return the string-valued field of the message's content, or the empty string if there isn't one.
+func (m *Message) contentString(key string) string {
+	value, _ := m.Content[key].(string)
+	return value
+}
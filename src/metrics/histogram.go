@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+const (
+	// Values below subBucketCount are recorded exactly. Above that, each power of two is split into subBucketHalfCount
+	// linear buckets, so every value is recorded with a relative error of less than 1/subBucketHalfCount (~0.1%),
+	// i.e., three significant digits, in the style of an HDR histogram.
+	subBucketBits      = 11
+	subBucketCount     = 1 << subBucketBits
+	subBucketHalfCount = subBucketCount / 2
+)
+
+// A log-linear histogram of latencies with three significant digits of precision.
+// Memory grows with the logarithm of the largest recorded value. Not safe for concurrent use.
+type Histogram struct {
+	counts []int64
+	count  int64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+func NewHistogram() *Histogram {
+	return &Histogram{min: math.MaxInt64}
+}
+
+// Return the index of the bucket in which the given (non-negative) value is counted.
+func bucketIndex(v int64) int {
+	if v < subBucketCount {
+		return int(v)
+	}
+
+	// Shift the value down until it fits in [subBucketHalfCount, subBucketCount).
+	shift := bits.Len64(uint64(v)) - subBucketBits
+	sub := v >> shift
+	return subBucketCount + (shift-1)*subBucketHalfCount + int(sub-subBucketHalfCount)
+}
+
+// Return the largest value that is counted in the bucket with the given index.
+func bucketUpperBound(idx int) int64 {
+	if idx < subBucketCount {
+		return int64(idx)
+	}
+
+	k := idx - subBucketCount
+	shift := k/subBucketHalfCount + 1
+	sub := int64(k%subBucketHalfCount + subBucketHalfCount)
+	return ((sub + 1) << shift) - 1
+}
+
+// Record a single latency. Negative latencies are recorded as zero.
+func (h *Histogram) Record(latency time.Duration) {
+	if latency < 0 {
+		latency = 0
+	}
+
+	idx := bucketIndex(int64(latency))
+	if idx >= len(h.counts) {
+		counts := make([]int64, idx+1)
+		copy(counts, h.counts)
+		h.counts = counts
+	}
+
+	h.counts[idx]++
+	h.count++
+	h.sum += latency
+	h.min = min(h.min, latency)
+	h.max = max(h.max, latency)
+}
+
+// Return the number of recorded latencies.
+func (h *Histogram) Count() int64 {
+	return h.count
+}
+
+// Return the smallest recorded latency, or zero if nothing has been recorded.
+func (h *Histogram) Min() time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	return h.min
+}
+
+// Return the largest recorded latency.
+func (h *Histogram) Max() time.Duration {
+	return h.max
+}
+
+// Return the mean of the recorded latencies.
+func (h *Histogram) Mean() time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	return h.sum / time.Duration(h.count)
+}
+
+// Return the latency at the given percentile (e.g., 99.9), to within the histogram's precision.
+func (h *Histogram) Percentile(percentile float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	target := int64(math.Ceil(percentile / 100 * float64(h.count)))
+	target = max(1, min(target, h.count))
+
+	var cumulative int64
+	for idx, count := range h.counts {
+		cumulative += count
+		if cumulative >= target {
+			return min(time.Duration(bucketUpperBound(idx)), h.max)
+		}
+	}
+
+	return h.max
+}
+
+// Add the latencies recorded by another histogram to this one.
+func (h *Histogram) Merge(other *Histogram) {
+	if other.count == 0 {
+		return
+	}
+
+	if len(other.counts) > len(h.counts) {
+		counts := make([]int64, len(other.counts))
+		copy(counts, h.counts)
+		h.counts = counts
+	}
+
+	for idx, count := range other.counts {
+		h.counts[idx] += count
+	}
+
+	h.count += other.count
+	h.sum += other.sum
+	h.min = min(h.min, other.min)
+	h.max = max(h.max, other.max)
+}
@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Operations whose latencies are recorded.
+const (
+	OpMigrateKernelReplica = "MigrateKernelReplica"
+	OpListKernels          = "ListKernels"
+	OpStartKernel          = "StartKernel"
+	OpKillKernel           = "KillKernel"
+	OpInterruptKernel      = "InterruptKernel"
+	OpRemoveHost           = "RemoveHost"
+	OpCordonNode           = "CordonNode"    // Cordoning or uncordoning a Kubernetes node via the backend.
+	OpCreateSession        = "CreateSession" // Creating a session, and thereby its kernel.
+	OpExecuteCode          = "ExecuteCode"   // Executing a single cell.
+	OpStopSession          = "StopSession"
+	OpFetchNodes           = "FetchNodes"       // Fetching the Kubernetes nodes from the backend.
+	OpFetchKernelSpecs     = "FetchKernelSpecs" // Fetching the Jupyter kernel specs from the backend.
+)
+
+// Outcomes of an operation.
+const (
+	OutcomeSuccess   = "success"
+	OutcomeError     = "error"
+	OutcomeTimeout   = "timeout"
+	OutcomeCancelled = "cancelled"
+)
+
+// Return the outcome corresponding to the error returned by an operation.
+func OutcomeOf(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, context.DeadlineExceeded):
+		return OutcomeTimeout
+	case errors.Is(err, context.Canceled):
+		return OutcomeCancelled
+	default:
+		return OutcomeError
+	}
+}
+
+// A single latency measurement.
+type Sample struct {
+	Timestamp time.Time     `json:"timestamp"` // When the operation was issued.
+	Operation string        `json:"operation"`
+	KernelId  string        `json:"kernel_id,omitempty"` // The kernel targeted by the operation, if any.
+	Outcome   string        `json:"outcome"`
+	Latency   time.Duration `json:"latency"`
+}
+
+// Records the latency of every operation issued by the driver. Safe for concurrent use.
+type Recorder struct {
+	mutex      sync.Mutex
+	samples    []*Sample
+	histograms map[string]map[string]*Histogram // Operation -> Outcome -> Histogram.
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		samples:    make([]*Sample, 0),
+		histograms: make(map[string]map[string]*Histogram),
+	}
+}
+
+// Record the latency of an operation that was issued at the given time and has just completed with the given error.
+func (r *Recorder) Observe(operation string, kernelId string, start time.Time, err error) {
+	r.Record(&Sample{
+		Timestamp: start,
+		Operation: operation,
+		KernelId:  kernelId,
+		Outcome:   OutcomeOf(err),
+		Latency:   time.Since(start),
+	})
+}
+
+// Record a single sample.
+func (r *Recorder) Record(sample *Sample) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.samples = append(r.samples, sample)
+
+	byOutcome, ok := r.histograms[sample.Operation]
+	if !ok {
+		byOutcome = make(map[string]*Histogram)
+		r.histograms[sample.Operation] = byOutcome
+	}
+
+	histogram, ok := byOutcome[sample.Outcome]
+	if !ok {
+		histogram = NewHistogram()
+		byOutcome[sample.Outcome] = histogram
+	}
+
+	histogram.Record(sample.Latency)
+}
+
+// Discard everything that has been recorded so far.
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.samples = make([]*Sample, 0)
+	r.histograms = make(map[string]map[string]*Histogram)
+}
+
+// Return a copy of the samples recorded so far.
+func (r *Recorder) Samples() []*Sample {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	samples := make([]*Sample, len(r.samples))
+	copy(samples, r.samples)
+	return samples
+}
+
+// Summarizes the latencies of a single operation.
+type OperationReport struct {
+	Operation string           `json:"operation"`
+	Count     int64            `json:"count"`
+	Outcomes  map[string]int64 `json:"outcomes"` // Number of samples with each outcome.
+	Min       time.Duration    `json:"min"`
+	Mean      time.Duration    `json:"mean"`
+	P50       time.Duration    `json:"p50"`
+	P90       time.Duration    `json:"p90"`
+	P99       time.Duration    `json:"p99"`
+	P999      time.Duration    `json:"p99.9"`
+	Max       time.Duration    `json:"max"`
+}
+
+// Summaries of every operation, sorted by operation.
+type Report []*OperationReport
+
+// Summarize the latencies of each operation across all outcomes.
+func (r *Recorder) Report() Report {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	report := make(Report, 0, len(r.histograms))
+	for operation, byOutcome := range r.histograms {
+		merged := NewHistogram()
+		outcomes := make(map[string]int64, len(byOutcome))
+		for outcome, histogram := range byOutcome {
+			merged.Merge(histogram)
+			outcomes[outcome] = histogram.Count()
+		}
+
+		report = append(report, &OperationReport{
+			Operation: operation,
+			Count:     merged.Count(),
+			Outcomes:  outcomes,
+			Min:       merged.Min(),
+			Mean:      merged.Mean(),
+			P50:       merged.Percentile(50),
+			P90:       merged.Percentile(90),
+			P99:       merged.Percentile(99),
+			P999:      merged.Percentile(99.9),
+			Max:       merged.Max(),
+		})
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Operation < report[j].Operation
+	})
+
+	return report
+}
+
+// Render the report as a table.
+func (r Report) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "OPERATION\tCOUNT\tERRORS\tP50\tP90\tP99\tP99.9\tMAX")
+	for _, op := range r {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%v\t%v\t%v\t%v\t%v\n", op.Operation, op.Count, op.Count-op.Outcomes[OutcomeSuccess], op.P50, op.P90, op.P99, op.P999, op.Max)
+	}
+	w.Flush()
+
+	return buf.String()
+}
@@ -0,0 +1,290 @@
+package providers
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/proxy"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/security"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+const (
+	// Address of the backend, which serves the frontend along with the endpoints in domain, if not configured otherwise.
+	DefaultBackendAddress = "localhost:8000"
+
+	// Maximum size of a single response from the backend. Rich outputs of executed code, such as images, can be large.
+	backendReadLimit = 1 << 24
+
+	// How many responses to a single request are buffered before the connection stops reading further responses.
+	responseBufferSize = 16
+)
+
+var (
+	ErrBackendClosed = errors.New("the connection to the backend was closed")
+)
+
+// How requests are issued to the backend.
+type BackendOptions struct {
+	Address     string               // The host and port of the backend. DefaultBackendAddress if empty.
+	Secure      bool                 // Connect via wss:// rather than ws://.
+	TLSConfig   *tls.Config          // Used when Secure is true. Nil to use the defaults. Ignored in the browser.
+	Credentials security.Credentials // Sent with each request, if the backend requires authentication.
+}
+
+var (
+	backendMutex   sync.RWMutex
+	backendOptions BackendOptions
+)
+
+// Set how every subsequent request to the backend is issued, whether by the driver or by the providers.
+func ConfigureBackend(opts BackendOptions) {
+	backendMutex.Lock()
+	defer backendMutex.Unlock()
+
+	backendOptions = opts
+}
+
+// Return the options with which the frontend reaches the backend from which it was loaded, given the page's URL:
+// the same host and port, via wss:// if the page was served over https://. If the backend requires a token, then
+// the token is passed to the page as the "token" query parameter (e.g., https://host:8000/?token=...), as with Jupyter.
+func BackendOptionsFromPage(page *url.URL) BackendOptions {
+	return BackendOptions{
+		Address:     page.Host,
+		Secure:      page.Scheme == "https",
+		Credentials: security.Credentials{Token: page.Query().Get("token")},
+	}
+}
+
+// Return the address at which a backend listening at the given address (e.g., ":8000") can be reached from the same host.
+func LocalBackendAddress(listenAddress string) string {
+	host, port, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return DefaultBackendAddress
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
+// Open a websocket to the given endpoint of the backend, authenticating with the configured credentials, if any.
+func DialBackend(ctx context.Context, endpoint string) (*websocket.Conn, error) {
+	backendMutex.RLock()
+	opts := backendOptions
+	backendMutex.RUnlock()
+
+	u := &url.URL{Scheme: "ws", Host: opts.Address, Path: endpoint}
+	if u.Host == "" {
+		u.Host = DefaultBackendAddress
+	}
+	if opts.Secure {
+		u.Scheme = "wss"
+	}
+
+	var header http.Header
+	if proxy.HeadersSupported {
+		header = make(http.Header)
+	}
+	opts.Credentials.Apply(http.MethodGet, u, header, time.Now())
+
+	return proxy.DialWebSocket(ctx, u.String(), opts.TLSConfig, header)
+}
+
+// A websocket to one of the backend's endpoints, over which any number of requests may be issued concurrently.
+type BackendClient struct {
+	conn   *websocket.Conn
+	nextId atomic.Int64
+
+	mu      sync.Mutex
+	pending map[string]*pendingRequest // The requests that are awaiting responses, keyed by ID.
+	err     error                      // Why the connection was closed. Nil while it's open.
+
+	done chan struct{} // Closed once the connection has been closed.
+}
+
+type pendingRequest struct {
+	responses chan *domain.Response
+	abandoned chan struct{} // Closed if the caller stops waiting for the responses.
+}
+
+// Connect to the given endpoint of the backend. The client must be closed once it's no longer needed.
+func NewBackendClient(ctx context.Context, endpoint string) (*BackendClient, error) {
+	conn, err := DialBackend(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadLimit(backendReadLimit)
+
+	client := &BackendClient{
+		conn:    conn,
+		pending: make(map[string]*pendingRequest),
+		done:    make(chan struct{}),
+	}
+
+	go client.readResponses()
+
+	return client, nil
+}
+
+// Close the connection. Requests that are still awaiting responses fail with ErrBackendClosed.
+func (c *BackendClient) Close() error {
+	return c.conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// Issue a request, and unmarshal the payload of its response into result, unless result is nil.
+// If the backend responds with an error, then the *domain.ResponseError is returned.
+func (c *BackendClient) Do(ctx context.Context, op string, payload interface{}, result interface{}) error {
+	return c.Stream(ctx, op, payload, nil, result)
+}
+
+// Issue a request to a streaming operation. The payload of each intermediate response is passed to the handler, and
+// that of the final response is unmarshalled into result, unless result is nil. If the handler returns an error, then
+// it is returned without waiting for the rest of the responses.
+func (c *BackendClient) Stream(ctx context.Context, op string, payload interface{}, onMessage func(json.RawMessage) error, result interface{}) error {
+	req := &domain.Request{
+		Version: domain.ProtocolVersion,
+		Id:      strconv.FormatInt(c.nextId.Add(1), 10),
+		Op:      op,
+	}
+
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		req.Payload = data
+	}
+
+	pending, err := c.register(req.Id)
+	if err != nil {
+		return err
+	}
+	defer c.unregister(req.Id, pending)
+
+	if err := wsjson.Write(ctx, c.conn, req); err != nil {
+		return err
+	}
+
+	for {
+		var resp *domain.Response
+		select {
+		case resp = <-pending.responses:
+		case <-c.done:
+			return c.closedErr()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if resp.Error != nil {
+			return resp.Error
+		}
+
+		if !resp.Final {
+			if onMessage != nil {
+				if err := onMessage(resp.Payload); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if result == nil || len(resp.Payload) == 0 {
+			return nil
+		}
+
+		return json.Unmarshal(resp.Payload, result)
+	}
+}
+
+func (c *BackendClient) register(id string) (*pendingRequest, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	pending := &pendingRequest{
+		responses: make(chan *domain.Response, responseBufferSize),
+		abandoned: make(chan struct{}),
+	}
+	c.pending[id] = pending
+
+	return pending, nil
+}
+
+func (c *BackendClient) unregister(id string, pending *pendingRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, id)
+	close(pending.abandoned)
+}
+
+func (c *BackendClient) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Pass each response to the request to which it responds, until the connection is closed.
+func (c *BackendClient) readResponses() {
+	for {
+		var resp domain.Response
+		if err := wsjson.Read(context.Background(), c.conn, &resp); err != nil {
+			c.mu.Lock()
+			c.err = fmt.Errorf("%w: %v", ErrBackendClosed, err)
+			c.mu.Unlock()
+
+			close(c.done)
+			return
+		}
+
+		c.mu.Lock()
+		pending, ok := c.pending[resp.Id]
+		c.mu.Unlock()
+
+		// Responses to requests whose callers have stopped waiting are dropped.
+		if !ok {
+			continue
+		}
+
+		select {
+		case pending.responses <- &resp:
+		case <-pending.abandoned:
+		}
+	}
+}
+
+// Issue a single request to the given endpoint over a new connection, and unmarshal the payload of its response into
+// result, unless result is nil.
+func RequestBackend(ctx context.Context, endpoint string, op string, payload interface{}, result interface{}) error {
+	return StreamBackend(ctx, endpoint, op, payload, nil, result)
+}
+
+// Issue a single request to a streaming operation of the given endpoint over a new connection. See BackendClient.Stream.
+func StreamBackend(ctx context.Context, endpoint string, op string, payload interface{}, onMessage func(json.RawMessage) error, result interface{}) error {
+	client, err := NewBackendClient(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.Stream(ctx, op, payload, onMessage, result)
+}
@@ -0,0 +1,384 @@
+package providers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	cmap "github.com/orcaman/concurrent-map/v2"
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// How frequently the Cluster Gateway is probed, if not configured otherwise.
+	DefaultHealthCheckInterval = time.Second * 5
+
+	// The longest we wait between reconnection attempts, if not configured otherwise.
+	DefaultMaxReconnectBackoff = time.Second * 30
+
+	// How long to wait before the first reconnection attempt. Doubled after each failed attempt.
+	initialReconnectBackoff = time.Second
+
+	// How long a health check (or the check made when connecting) may take before the Cluster Gateway is deemed unreachable.
+	healthCheckTimeout = time.Second * 10
+)
+
+var (
+	ErrGatewayDisconnected = errors.New("not connected to the Cluster Gateway")
+)
+
+type GatewayConnectionOptions struct {
+	HealthCheckInterval time.Duration // How frequently to probe the Cluster Gateway with the ID RPC.
+	MaxReconnectBackoff time.Duration // The longest to wait between reconnection attempts.
+	Secure              bool          // Connect via wss:// rather than ws://.
+	TLSConfig           *tls.Config   // Used when Secure is true. Nil to use the defaults. Ignored in the browser.
+}
+
+// Owns the single gRPC connection to the Cluster Gateway, which is shared by the driver and its providers.
+// Once connected, the Cluster Gateway is probed periodically. If a probe fails, then the connection is closed and
+// re-established with exponential backoff. Each change to the state of the connection is published to the subscribers.
+type GatewayConnection struct {
+	opts GatewayConnectionOptions
+
+	connectMutex sync.Mutex // Serializes calls to Connect and Close.
+
+	mu        sync.Mutex
+	address   string                         // Address of the Cluster Gateway.
+	conn      *grpc.ClientConn               // Nil unless connected.
+	client    gateway.ClusterGatewayClient   // Nil unless connected.
+	connected chan struct{}                  // Closed while connected.
+	stop      chan struct{}                  // Closed to stop monitoring the current connection. Nil if there isn't one.
+	event     *domain.GatewayConnectionEvent // The most recent connection event.
+
+	subscribers  *cmap.ConcurrentMap[string, func(*domain.GatewayConnectionEvent) bool]
+	publishMutex sync.Mutex // Held while publishing, so that the subscribers receive the events in order.
+}
+
+func NewGatewayConnection(opts GatewayConnectionOptions) *GatewayConnection {
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+
+	if opts.MaxReconnectBackoff <= 0 {
+		opts.MaxReconnectBackoff = DefaultMaxReconnectBackoff
+	}
+
+	subscribers := cmap.New[func(*domain.GatewayConnectionEvent) bool]()
+
+	return &GatewayConnection{
+		opts:        opts,
+		connected:   make(chan struct{}),
+		subscribers: &subscribers,
+		event: &domain.GatewayConnectionEvent{
+			State:     domain.GatewayDisconnected,
+			Timestamp: time.Now(),
+		},
+	}
+}
+
+// Connect to the Cluster Gateway at the given address, replacing the current connection, if any.
+// Returns an error if the Cluster Gateway could not be reached, in which case no reconnection is attempted.
+// This should NOT be called from the UI goroutine.
+func (c *GatewayConnection) Connect(address string) error {
+	if address == "" {
+		return domain.ErrEmptyGatewayAddr
+	}
+
+	c.connectMutex.Lock()
+	defer c.connectMutex.Unlock()
+
+	c.mu.Lock()
+	c.stopMonitoringLocked()
+	c.address = address
+	event := c.setStateLocked(domain.GatewayConnecting, 0, 0, nil)
+	c.mu.Unlock()
+	c.publish(event)
+
+	app.Logf("Attempting to dial Gateway gRPC server now. Address: %s\n", address)
+
+	conn, client, err := c.dial(address)
+
+	c.mu.Lock()
+	if err != nil {
+		event = c.setStateLocked(domain.GatewayDisconnected, 0, 0, err)
+		c.mu.Unlock()
+		c.publish(event)
+
+		app.Logf("Failed to dial Gateway gRPC server. Address: %s. Error: %v.\n", address, err)
+		return err
+	}
+
+	c.useLocked(conn, client)
+	event = c.setStateLocked(domain.GatewayConnected, 0, 0, nil)
+	stop := make(chan struct{})
+	c.stop = stop
+	c.mu.Unlock()
+	c.publish(event)
+
+	app.Logf("Successfully dialed Cluster Gateway at address %s.\n", address)
+
+	go c.monitor(address, stop)
+
+	return nil
+}
+
+// Close the connection, and stop monitoring it.
+func (c *GatewayConnection) Close() {
+	c.connectMutex.Lock()
+	defer c.connectMutex.Unlock()
+
+	c.mu.Lock()
+	c.stopMonitoringLocked()
+	event := c.setStateLocked(domain.GatewayDisconnected, 0, 0, nil)
+	c.mu.Unlock()
+	c.publish(event)
+}
+
+// Return the client of the Cluster Gateway, or nil if we're not currently connected to it.
+func (c *GatewayConnection) Client() gateway.ClusterGatewayClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.client
+}
+
+// Return the address of the Cluster Gateway most recently connected to.
+func (c *GatewayConnection) Address() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.address
+}
+
+// Block until we're connected to the Cluster Gateway, or the context is cancelled.
+func (c *GatewayConnection) WaitForConnected(ctx context.Context) error {
+	c.mu.Lock()
+	connected := c.connected
+	c.mu.Unlock()
+
+	select {
+	case <-connected:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Return the most recent connection event.
+func (c *GatewayConnection) ConnectionState() *domain.GatewayConnectionEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	event := *c.event
+	return &event
+}
+
+// Subscribe to connection events.
+func (c *GatewayConnection) SubscribeToConnectionEvents(id string, handler func(*domain.GatewayConnectionEvent) bool) {
+	c.subscribers.Set(id, handler)
+}
+
+// Unsubscribe from connection events.
+func (c *GatewayConnection) UnsubscribeFromConnectionEvents(id string) {
+	c.subscribers.Remove(id)
+}
+
+// Probe the Cluster Gateway periodically until the connection is replaced or closed, reconnecting whenever a probe fails.
+// This should be called from its own goroutine.
+func (c *GatewayConnection) monitor(address string, stop chan struct{}) {
+	ticker := time.NewTicker(c.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+
+		client := c.Client()
+		if client == nil {
+			continue
+		}
+
+		err := probe(client)
+		if err == nil {
+			continue
+		}
+
+		app.Logf("[WARNING] Health check of the Cluster Gateway at %s failed: %v. Reconnecting.", address, err)
+
+		if !c.reconnect(address, stop, err) {
+			return
+		}
+	}
+}
+
+// Close the connection, and re-establish it with exponential backoff.
+// Returns false if monitoring was stopped before the connection could be re-established.
+func (c *GatewayConnection) reconnect(address string, stop chan struct{}, cause error) bool {
+	backoff := initialReconnectBackoff
+
+	c.mu.Lock()
+	if stopped(stop) {
+		c.mu.Unlock()
+		return false
+	}
+	c.closeLocked()
+	event := c.setStateLocked(domain.GatewayReconnecting, 0, backoff, cause)
+	c.mu.Unlock()
+	c.publish(event)
+
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-stop:
+			return false
+		}
+
+		conn, client, err := c.dial(address)
+
+		c.mu.Lock()
+		if stopped(stop) {
+			c.mu.Unlock()
+			if conn != nil {
+				conn.Close()
+			}
+			return false
+		}
+
+		if err == nil {
+			c.useLocked(conn, client)
+			event = c.setStateLocked(domain.GatewayConnected, 0, 0, nil)
+			c.mu.Unlock()
+			c.publish(event)
+
+			app.Logf("Reconnected to the Cluster Gateway at %s after %d attempt(s).", address, attempt)
+			return true
+		}
+
+		backoff = min(backoff*2, c.opts.MaxReconnectBackoff)
+		event = c.setStateLocked(domain.GatewayReconnecting, attempt, backoff, err)
+		c.mu.Unlock()
+		c.publish(event)
+
+		app.Logf("[WARNING] Reconnection attempt %d to the Cluster Gateway at %s failed: %v. Retrying in %v.", attempt, address, err, backoff)
+	}
+}
+
+// Begin using the given connection. Must be called with the mutex held, and while not connected.
+func (c *GatewayConnection) useLocked(conn *grpc.ClientConn, client gateway.ClusterGatewayClient) {
+	c.conn = conn
+	c.client = client
+	close(c.connected)
+}
+
+// Close the current connection, if any. Must be called with the mutex held.
+func (c *GatewayConnection) closeLocked() {
+	if c.conn == nil {
+		return
+	}
+
+	c.conn.Close()
+	c.conn = nil
+	c.client = nil
+	c.connected = make(chan struct{})
+}
+
+// Stop monitoring the current connection, and close it. Must be called with the mutex held.
+func (c *GatewayConnection) stopMonitoringLocked() {
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+
+	c.closeLocked()
+}
+
+// Record a new connection event, returning it so that it can be published once the mutex is released.
+// Must be called with the mutex held.
+func (c *GatewayConnection) setStateLocked(state domain.GatewayConnectionState, attempt int, retryIn time.Duration, err error) *domain.GatewayConnectionEvent {
+	c.event = &domain.GatewayConnectionEvent{
+		State:     state,
+		Address:   c.address,
+		Attempt:   attempt,
+		RetryIn:   retryIn,
+		Timestamp: time.Now(),
+	}
+
+	if err != nil {
+		c.event.Error = err.Error()
+	}
+
+	event := *c.event
+	return &event
+}
+
+// Pass the event to the subscribers. Must NOT be called with the mutex held, as the subscribers may query the connection.
+func (c *GatewayConnection) publish(event *domain.GatewayConnectionEvent) {
+	c.publishMutex.Lock()
+	defer c.publishMutex.Unlock()
+
+	unsubscribeThese := make([]string, 0)
+
+	for kv := range c.subscribers.IterBuffered() {
+		handler := kv.Val
+		subscribed := handler(event)
+
+		if !subscribed {
+			unsubscribeThese = append(unsubscribeThese, kv.Key)
+		}
+	}
+
+	for _, id := range unsubscribeThese {
+		c.UnsubscribeFromConnectionEvents(id)
+	}
+}
+
+// Dial the Cluster Gateway, and check that it is reachable.
+func (c *GatewayConnection) dial(address string) (*grpc.ClientConn, gateway.ClusterGatewayClient, error) {
+	scheme := "ws://"
+	if c.opts.Secure {
+		scheme = "wss://"
+	}
+
+	// The websocket itself is secured, so the gRPC connection that is tunneled through it doesn't need to be.
+	webSocketProxyClient := proxy.NewWebSocketProxyClient(time.Minute).WithTLSConfig(c.opts.TLSConfig)
+	conn, err := grpc.Dial(scheme+address, grpc.WithContextDialer(webSocketProxyClient.Dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := gateway.NewClusterGatewayClient(conn)
+	if err := probe(client); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, client, nil
+}
+
+// Check that the Cluster Gateway is reachable by issuing an ID RPC.
+func probe(client gateway.ClusterGatewayClient) error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	_, err := client.ID(ctx, &gateway.Void{})
+	return err
+}
+
+func stopped(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,114 @@
+package providers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+// Return the changes that turn the previous resources into the current ones, in order of ID.
+// Fields named in ignored (e.g., ages, which change on every refresh) don't count as changes.
+func diffResources[Resource any](previous map[string]Resource, current map[string]Resource, ignored map[string]struct{}) []*domain.ResourceChange[Resource] {
+	ids := make([]string, 0, len(current))
+	for id := range current {
+		ids = append(ids, id)
+	}
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	changes := make([]*domain.ResourceChange[Resource], 0)
+	for _, id := range ids {
+		before, existed := previous[id]
+		after, exists := current[id]
+
+		change := &domain.ResourceChange[Resource]{Id: id, Previous: before, Current: after}
+		switch {
+		case exists && !existed:
+			change.Type = domain.ResourceAdded
+		case existed && !exists:
+			change.Type = domain.ResourceRemoved
+		default:
+			change.Fields = diffFields(before, after, ignored)
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.Type = domain.ResourceUpdated
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// Return the fields that differ between two values of the same type, e.g., "Replicas[1].NodeId".
+// Unexported fields, such as the internal state of protobuf messages, are skipped.
+func diffFields(previous interface{}, current interface{}, ignored map[string]struct{}) []*domain.FieldChange {
+	changes := make([]*domain.FieldChange, 0)
+	diffValues("", reflect.ValueOf(previous), reflect.ValueOf(current), ignored, &changes)
+	return changes
+}
+
+func diffValues(path string, a reflect.Value, b reflect.Value, ignored map[string]struct{}, changes *[]*domain.FieldChange) {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			*changes = append(*changes, &domain.FieldChange{Field: path, Previous: interfaceOf(a), Current: interfaceOf(b)})
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*changes = append(*changes, &domain.FieldChange{Field: path, Previous: a.Interface(), Current: b.Interface()})
+			}
+			return
+		}
+
+		diffValues(path, a.Elem(), b.Elem(), ignored, changes)
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if _, skip := ignored[field.Name]; skip || !field.IsExported() {
+				continue
+			}
+
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+
+			diffValues(fieldPath, a.Field(i), b.Field(i), ignored, changes)
+		}
+	case reflect.Slice, reflect.Array:
+		// If elements were added or removed, then we can't tell which of them changed, so we report the whole slice.
+		if a.Len() != b.Len() {
+			*changes = append(*changes, &domain.FieldChange{Field: path, Previous: a.Interface(), Current: b.Interface()})
+			return
+		}
+
+		for i := 0; i < a.Len(); i++ {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i), ignored, changes)
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*changes = append(*changes, &domain.FieldChange{Field: path, Previous: a.Interface(), Current: b.Interface()})
+		}
+	}
+}
+
+func interfaceOf(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	return v.Interface()
+}
@@ -7,15 +7,16 @@ import (
 	"github.com/maxence-charriere/go-app/v9/pkg/app"
 	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
 )
 
 type BaseKernelProvider struct {
 	*BaseProvider[*gateway.DistributedJupyterKernel]
 }
 
-func NewKernelProvider(kernelQueryInterval time.Duration, errorHandler domain.ErrorHandler) domain.KernelProvider {
+func NewKernelProvider(kernelQueryInterval time.Duration, errorHandler domain.ErrorHandler, recorder *metrics.Recorder, connection *GatewayConnection) domain.KernelProvider {
 	// Create the base provider that provides implementations to methods common to all types of resource providers.
-	baseProvider := newBaseProvider[*gateway.DistributedJupyterKernel](kernelQueryInterval, errorHandler, true)
+	baseProvider := newBaseProvider[*gateway.DistributedJupyterKernel](kernelQueryInterval, errorHandler, recorder, connection)
 
 	// Create the KernelProvider.
 	provider := &BaseKernelProvider{
@@ -24,7 +25,7 @@ func NewKernelProvider(kernelQueryInterval time.Duration, errorHandler domain.Er
 
 	provider.ResourceProvider = provider
 
-	app.Logf("Will be querying and refreshing kernels every %v", kernelQueryInterval)
+	app.Logf("Will be watching kernels, or else querying and refreshing them every %v", kernelQueryInterval)
 
 	return provider
 }
@@ -42,8 +43,16 @@ func (p *BaseKernelProvider) RefreshResources() {
 	}
 	defer p.refreshMutex.Unlock()
 
+	rpcClient := p.connection.Client()
+	if rpcClient == nil {
+		app.Log("[WARNING] Disconnected from Gateway; cannot refresh kernels.")
+		return
+	}
+
 	app.Log("Kernel Querier is refreshing kernels now.")
-	resp, err := p.rpcClient.ListKernels(context.TODO(), &gateway.Void{})
+	start := time.Now()
+	resp, err := rpcClient.ListKernels(context.TODO(), &gateway.Void{})
+	p.recorder.Observe(metrics.OpListKernels, "", start, err)
 	if err != nil || resp == nil {
 		app.Logf("[ERROR] Failed to fetch list of active kernels from the Cluster Gateway: %v.", err)
 		p.errorHandler.HandleError(err, "Failed to fetch list of active kernels from the Cluster Gateway.")
@@ -59,3 +68,33 @@ func (p *BaseKernelProvider) RefreshResources() {
 
 	p.RefreshOccurred()
 }
+
+// Watch the Cluster Gateway for changes to the kernels until the watch ends or the context is cancelled.
+func (p *BaseKernelProvider) Watch(ctx context.Context) error {
+	rpcClient := p.connection.Client()
+	if rpcClient == nil {
+		return ErrGatewayDisconnected
+	}
+
+	stream, err := rpcClient.WatchKernels(ctx, &gateway.Void{})
+	if err != nil {
+		return err
+	}
+
+	app.Log("Watching the Cluster Gateway for changes to the kernels.")
+
+	applier := newWatchApplier(p.BaseProvider)
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		var kernelId string
+		if event.Kernel != nil {
+			kernelId = event.Kernel.KernelId
+		}
+
+		applier.apply(domain.ResourceEventType(event.Type), kernelId, event.Kernel)
+	}
+}
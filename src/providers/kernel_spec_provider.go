@@ -7,16 +7,15 @@ import (
 
 	"github.com/maxence-charriere/go-app/v9/pkg/app"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
-	"nhooyr.io/websocket"
-	"nhooyr.io/websocket/wsjson"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
 )
 
 type BaseKernelSpecProvider struct {
 	*BaseProvider[*domain.KernelSpec]
 }
 
-func NewBaseKernelSpecProvider(kernelSpecQueryInterval time.Duration, errorHandler domain.ErrorHandler) *BaseKernelSpecProvider {
-	provider := &BaseKernelSpecProvider{BaseProvider: newBaseProvider[*domain.KernelSpec](kernelSpecQueryInterval, errorHandler, false)}
+func NewBaseKernelSpecProvider(kernelSpecQueryInterval time.Duration, errorHandler domain.ErrorHandler, recorder *metrics.Recorder) *BaseKernelSpecProvider {
+	provider := &BaseKernelSpecProvider{BaseProvider: newBaseProvider[*domain.KernelSpec](kernelSpecQueryInterval, errorHandler, recorder, nil)}
 	provider.ResourceProvider = provider
 	return provider
 }
@@ -33,35 +32,10 @@ func (p *BaseKernelSpecProvider) RefreshResources() {
 
 	app.Log("KernelSpec Querier is refreshing kernel specs now.")
 
-	ctxConnect, cancelConnect := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancelConnect()
-	c, _, err := websocket.Dial(ctxConnect, "ws://localhost:8000"+domain.KERNEL_SPEC_ENDPOINT, nil)
+	start := time.Now()
+	kernelSpecs, err := p.fetchKernelSpecs()
+	p.recorder.Observe(metrics.OpFetchKernelSpecs, "", start, err)
 	if err != nil {
-		app.Logf("Failed to connect to backend while trying to refresh k8s kernel specs: %v", err)
-		p.errorHandler.HandleError(err, "Failed to fetch list of active kernel specs from the Cluster Gateway. Could not connect to the backend.")
-		return
-	}
-	defer c.CloseNow()
-
-	msg := map[string]interface{}{
-		"op": "request-kernel-specs",
-	}
-
-	ctxWrite, cancelWrite := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancelWrite()
-	err = wsjson.Write(ctxWrite, c, msg)
-	if err != nil {
-		p.errorHandler.HandleError(err, "Failed to fetch list of active kernel specs from the Cluster Gateway.")
-		return
-	}
-
-	ctxRead, cancelRead := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancelRead()
-	var kernelSpecs []*domain.KernelSpec
-	err = wsjson.Read(ctxRead, c, &kernelSpecs)
-	c.Close(websocket.StatusNormalClosure, "")
-	if err != nil {
-		app.Logf("Error encountered while reading kernel specs from backend: %v", err)
 		return
 	}
 
@@ -79,3 +53,18 @@ func (p *BaseKernelSpecProvider) RefreshResources() {
 
 	p.RefreshOccurred()
 }
+
+// Fetch the current Jupyter kernel specs from the backend.
+func (p *BaseKernelSpecProvider) fetchKernelSpecs() ([]*domain.KernelSpec, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	var kernelSpecs []*domain.KernelSpec
+	if err := RequestBackend(ctx, domain.KERNEL_SPEC_ENDPOINT, domain.KernelSpecOpRequest, nil, &kernelSpecs); err != nil {
+		app.Logf("Failed to fetch kernel specs from backend: %v", err)
+		p.errorHandler.HandleError(err, "Failed to fetch list of active kernel specs from the Cluster Gateway.")
+		return nil, err
+	}
+
+	return kernelSpecs, nil
+}
@@ -2,26 +2,28 @@ package providers
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/maxence-charriere/go-app/v9/pkg/app"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
-	"nhooyr.io/websocket"
-	"nhooyr.io/websocket/wsjson"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
 )
 
 type BaseNodeProvider struct {
 	*BaseProvider[*domain.KubernetesNode]
 }
 
-func NewNodeProvider(nodeQueryInterval time.Duration, errorHandler domain.ErrorHandler, spoofCluster bool) domain.NodeProvider {
-	app.Logf("Will be querying and refreshing nodes every %v", nodeQueryInterval)
-
-	// If we're spoofing the cluster, then don't connect to the Gateway.
-	var doConnectToGateway bool = !spoofCluster
+func NewNodeProvider(nodeQueryInterval time.Duration, errorHandler domain.ErrorHandler, recorder *metrics.Recorder) domain.NodeProvider {
+	app.Logf("Will be watching nodes, or else querying and refreshing them every %v", nodeQueryInterval)
 
 	// Create the base provider that provides implementations to methods common to all types of resource providers.
-	baseProvider := newBaseProvider[*domain.KubernetesNode](nodeQueryInterval, errorHandler, doConnectToGateway)
+	// The nodes come from the backend rather than from the Cluster Gateway, so the provider doesn't need a connection to the latter.
+	baseProvider := newBaseProvider[*domain.KubernetesNode](nodeQueryInterval, errorHandler, recorder, nil)
+
+	// The ages of nodes and their pods change every time the nodes are refreshed.
+	baseProvider.ignoredFields["Age"] = struct{}{}
+	baseProvider.ignoredFields["PodAge"] = struct{}{}
 
 	// Create the NodeProvider.
 	nodeProvider := &BaseNodeProvider{
@@ -47,35 +49,10 @@ func (p *BaseNodeProvider) RefreshResources() {
 
 	app.Log("Node Querier is refreshing nodes now.")
 
-	ctxConnect, cancelConnect := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancelConnect()
-	c, _, err := websocket.Dial(ctxConnect, "ws://localhost:8000"+domain.KUBERNETES_NODES_ENDPOINT, nil)
+	start := time.Now()
+	nodes, err := p.fetchNodes()
+	p.recorder.Observe(metrics.OpFetchNodes, "", start, err)
 	if err != nil {
-		app.Logf("Failed to connect to backend while trying to refresh k8s nodes: %v", err)
-		p.errorHandler.HandleError(err, "Failed to fetch list of active nodes from the Cluster Gateway. Could not connect to the backend.")
-		return
-	}
-	defer c.CloseNow()
-
-	msg := map[string]interface{}{
-		"op": "request-nodes",
-	}
-
-	ctxWrite, cancelWrite := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancelWrite()
-	err = wsjson.Write(ctxWrite, c, msg)
-	if err != nil {
-		p.errorHandler.HandleError(err, "Failed to fetch list of active nodes from the Cluster Gateway.")
-		return
-	}
-
-	ctxRead, cancelRead := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancelRead()
-	var nodes map[string]*domain.KubernetesNode
-	err = wsjson.Read(ctxRead, c, &nodes)
-	c.Close(websocket.StatusNormalClosure, "")
-	if err != nil {
-		app.Logf("Error encountered while reading nodes from backend: %v", err)
 		return
 	}
 
@@ -89,3 +66,34 @@ func (p *BaseNodeProvider) RefreshResources() {
 
 	p.RefreshOccurred()
 }
+
+// Fetch the current Kubernetes nodes from the backend.
+func (p *BaseNodeProvider) fetchNodes() (map[string]*domain.KubernetesNode, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	var nodes map[string]*domain.KubernetesNode
+	if err := RequestBackend(ctx, domain.KUBERNETES_NODES_ENDPOINT, domain.NodeOpRequest, nil, &nodes); err != nil {
+		app.Logf("Failed to fetch nodes from backend: %v", err)
+		p.errorHandler.HandleError(err, "Failed to fetch list of active nodes from the Cluster Gateway.")
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// Watch the backend for changes to the nodes until the watch ends or the context is cancelled.
+func (p *BaseNodeProvider) Watch(ctx context.Context) error {
+	app.Log("Watching the backend for changes to the nodes.")
+
+	applier := newWatchApplier(p.BaseProvider)
+	return StreamBackend(ctx, domain.KUBERNETES_NODES_ENDPOINT, domain.NodeOpWatch, nil, func(data json.RawMessage) error {
+		var event domain.NodeEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return err
+		}
+
+		applier.apply(event.Type, event.NodeId, event.Node)
+		return nil
+	}, nil)
+}
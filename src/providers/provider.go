@@ -6,43 +6,48 @@ import (
 
 	"github.com/maxence-charriere/go-app/v9/pkg/app"
 	cmap "github.com/orcaman/concurrent-map/v2"
-	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
-	"github.com/scusemua/djn-workload-driver/m/v2/src/proxy"
-	"go.uber.org/zap"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
 )
 
 type BaseProvider[Resource any] struct {
 	domain.ResourceProvider[Resource]
 
-	rpcClient           gateway.ClusterGatewayClient          // gRPC client to the Cluster Gateway.
+	connection          *GatewayConnection                    // Connection to the Cluster Gateway, shared with the driver. Nil if the provider doesn't need one.
 	resources           *cmap.ConcurrentMap[string, Resource] // Latest resources.
 	lastRefresh         time.Time                             // The last time we refreshed the resources.
 	lastRefreshMutex    sync.Mutex                            // Sychronizes access to the lastResourceRefresh variable.
 	refreshMutex        sync.Mutex                            // Sychrnoize resource refreshes.
 	resourceQueryTicker *time.Ticker                          // Sends ticks to retrieve updates on the resources.
 	quitQueryChannel    chan struct{}                         // Used to tell the resource querier (a goroutine) to stop querying.
-	queryInterval       time.Duration                         // How frequently to query the Gateway for resource updates. TODO(Ben): Eventually, the Gateway will publish this info to us proactively.
+	queryInterval       time.Duration                         // How frequently to query the Gateway for resource updates, if the provider can't watch them instead.
 	errorHandler        domain.ErrorHandler                   // Pass errors here to be displayed to the user.
-	connectedToGateway  bool                                  // Indicates whether or not we're connected to the Cluster Gateway
 	gatewayAddress      string                                // Address of the Cluster Gateway.
-	doConnectToGateway  bool                                  // True if this provider should actually attempt to connect to the gateway. Some providers don't need to.
+	recorder            *metrics.Recorder                     // Records the latency of the requests issued by the provider.
 
-	subscribers *cmap.ConcurrentMap[string, func([]Resource) bool]
+	subscribers       *cmap.ConcurrentMap[string, func([]Resource) bool]
+	changeSubscribers *cmap.ConcurrentMap[string, func([]*domain.ResourceChange[Resource]) bool]
+
+	snapshot      map[string]Resource // The resources as of the last refresh, against which the next refresh is diffed.
+	snapshotMutex sync.Mutex          // Synchronizes diffing the resources against the snapshot.
+	ignoredFields map[string]struct{} // Fields that change on every refresh (e.g., ages), and so aren't reported as changes.
 }
 
-func newBaseProvider[Resource any](queryInterval time.Duration, errorHandler domain.ErrorHandler, doConnectToGateway bool) *BaseProvider[Resource] {
+func newBaseProvider[Resource any](queryInterval time.Duration, errorHandler domain.ErrorHandler, recorder *metrics.Recorder, connection *GatewayConnection) *BaseProvider[Resource] {
 	resources := cmap.New[Resource]()
 	subscribers := cmap.New[func([]Resource) bool]()
+	changeSubscribers := cmap.New[func([]*domain.ResourceChange[Resource]) bool]()
 
 	provider := &BaseProvider[Resource]{
-		doConnectToGateway:  doConnectToGateway,
+		connection:          connection,
 		resources:           &resources,
 		subscribers:         &subscribers,
+		changeSubscribers:   &changeSubscribers,
+		snapshot:            make(map[string]Resource),
+		ignoredFields:       make(map[string]struct{}),
 		queryInterval:       queryInterval,
 		errorHandler:        errorHandler,
+		recorder:            recorder,
 		resourceQueryTicker: time.NewTicker(queryInterval),
 		quitQueryChannel:    make(chan struct{}),
 	}
@@ -86,6 +91,38 @@ func (p *BaseProvider[Resource]) RefreshOccurred() {
 	for _, componentId := range unsubscribeThese {
 		p.UnsubscribeFromRefreshes(componentId)
 	}
+
+	p.notifyChanges()
+}
+
+// Diff the resources against those of the last refresh and pass the changes, if any, to the change subscribers.
+func (p *BaseProvider[Resource]) notifyChanges() {
+	// Held while notifying, so that subscribers receive the changes of successive refreshes in order.
+	p.snapshotMutex.Lock()
+	defer p.snapshotMutex.Unlock()
+
+	current := p.resources.Items()
+	changes := diffResources(p.snapshot, current, p.ignoredFields)
+	p.snapshot = current
+
+	if len(changes) == 0 {
+		return
+	}
+
+	unsubscribeThese := make([]string, 0)
+
+	for kv := range p.changeSubscribers.IterBuffered() {
+		handler := kv.Val
+		mounted := handler(changes)
+
+		if !mounted {
+			unsubscribeThese = append(unsubscribeThese, kv.Key)
+		}
+	}
+
+	for _, componentId := range unsubscribeThese {
+		p.UnsubscribeFromChanges(componentId)
+	}
 }
 
 // Periodically query the Gateway for an update of the current active kernels.
@@ -103,9 +140,10 @@ func (p *BaseProvider[Resource]) QueryResources() {
 			}
 			p.lastRefreshMutex.Unlock()
 
-			if !p.connectedToGateway {
-				app.Log("[WARNING] Disconnected from Gateway; cannot query for kernel updates.")
-				return
+			// Keep querying while disconnected, so that we resume once the connection has been re-established.
+			if !p.connected() {
+				app.Log("[WARNING] Disconnected from Gateway; skipping this query for resource updates.")
+				continue
 			}
 
 			p.ResourceProvider.RefreshResources()
@@ -121,16 +159,15 @@ func (p *BaseProvider[Resource]) RefreshResources() {
 	p.ResourceProvider.RefreshResources()
 }
 
-// Start querying for resources periodically.
+// Start watching for changes to the resources, if the provider supports it, or else querying for them periodically.
 func (p *BaseProvider[Resource]) Start(addr string) error {
-	err := p.ResourceProvider.DialGatewayGRPC(addr)
-	if err != nil {
-		return err
-	}
-
 	p.gatewayAddress = addr
 
-	go p.ResourceProvider.QueryResources()
+	if watcher, ok := p.ResourceProvider.(resourceWatcher); ok {
+		go p.watchResources(watcher)
+	} else {
+		go p.ResourceProvider.QueryResources()
+	}
 
 	return nil
 }
@@ -145,35 +182,17 @@ func (p *BaseProvider[Resource]) UnsubscribeFromRefreshes(id string) {
 	p.subscribers.Remove(id)
 }
 
-// Attempt to connect to the Cluster Gateway's gRPC server using the provided address. Returns an error if connection failed, or nil on success. This should NOT be called from the UI goroutine.
-func (p *BaseProvider[Resource]) DialGatewayGRPC(gatewayAddress string) error {
-	// Return immediately if we're not supposed to connect.
-	if !p.doConnectToGateway {
-		p.connectedToGateway = true
-		p.gatewayAddress = gatewayAddress
-		return nil
-	}
-
-	if gatewayAddress == "" {
-		return domain.ErrEmptyGatewayAddr
-	}
-
-	// p.logger.Info(fmt.Sprintf("Attempting to dial Gateway gRPC server now. Address: %s\n", p.gatewayAddress))
-	app.Logf("Attempting to dial Gateway gRPC server now. Address: %s\n", gatewayAddress)
-
-	webSocketProxyClient := proxy.NewWebSocketProxyClient(time.Minute)
-	conn, err := grpc.Dial("ws://"+gatewayAddress, grpc.WithContextDialer(webSocketProxyClient.Dialer), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
-	if err != nil {
-		// p.logger.Error(fmt.Sprintf("Failed to dial Gateway gRPC server. Address: %s. Error: %v.\n", p.gatewayAddress, zap.Error(err)))
-		app.Logf("Failed to dial Gateway gRPC server. Address: %s. Error: %v.\n", gatewayAddress, zap.Error(err))
-		return err
-	}
+// Subscribe to the changes between successive refreshes. The handler is only called if something changed.
+func (p *BaseProvider[Resource]) SubscribeToChanges(id string, handler func([]*domain.ResourceChange[Resource]) bool) {
+	p.changeSubscribers.Set(id, handler)
+}
 
-	// p.logger.Info(fmt.Sprintf("Successfully dialed Cluster Gateway at address %s.\n", p.gatewayAddress))
-	app.Logf("Successfully dialed Cluster Gateway at address %s.\n", gatewayAddress)
-	p.rpcClient = gateway.NewClusterGatewayClient(conn)
-	p.connectedToGateway = true
-	p.gatewayAddress = gatewayAddress
+// Unsubscribe from changes.
+func (p *BaseProvider[Resource]) UnsubscribeFromChanges(id string) {
+	p.changeSubscribers.Remove(id)
+}
 
-	return nil
+// Return true if the provider is connected to the Cluster Gateway, or doesn't need to be.
+func (p *BaseProvider[Resource]) connected() bool {
+	return p.connection == nil || p.connection.Client() != nil
 }
@@ -0,0 +1,109 @@
+package providers
+
+import (
+	"context"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// How long to wait before re-opening a watch that ended unexpectedly.
+	watchRetryInterval = time.Second * 5
+)
+
+// Implemented by providers whose source can push changes to the resources to us, rather than being polled.
+type resourceWatcher interface {
+	// Open a watch and apply its events until the watch ends or the context is cancelled.
+	Watch(ctx context.Context) error
+}
+
+// Applies the events of a single watch to a provider's resources.
+// A watch begins by listing every current resource, followed by a domain.ResourceSynced event. Resources that weren't
+// listed are removed at that point, and the subscribers are notified. Thereafter, they're notified of every change.
+type watchApplier[Resource any] struct {
+	provider *BaseProvider[Resource]
+	synced   bool
+	listed   map[string]struct{} // Resources listed before the watch was synced.
+}
+
+func newWatchApplier[Resource any](provider *BaseProvider[Resource]) *watchApplier[Resource] {
+	return &watchApplier[Resource]{
+		provider: provider,
+		listed:   make(map[string]struct{}),
+	}
+}
+
+func (a *watchApplier[Resource]) apply(eventType domain.ResourceEventType, id string, resource Resource) {
+	switch eventType {
+	case domain.ResourceAdded, domain.ResourceUpdated:
+		a.provider.resources.Set(id, resource)
+		if !a.synced {
+			a.listed[id] = struct{}{}
+		}
+	case domain.ResourceRemoved:
+		a.provider.resources.Remove(id)
+	case domain.ResourceSynced:
+		for _, existing := range a.provider.resources.Keys() {
+			if _, ok := a.listed[existing]; !ok {
+				a.provider.resources.Remove(existing)
+			}
+		}
+		a.synced = true
+		a.listed = nil
+	default:
+		app.Logf("[WARNING] Ignoring watch event of unknown type \"%s\".", eventType)
+		return
+	}
+
+	if a.synced {
+		a.provider.RefreshOccurred()
+	}
+}
+
+// Keep a watch open, re-opening it whenever it ends (once connected again, if the connection was lost), until the provider is stopped.
+// If the source doesn't support watches, then fall back to polling it.
+// This should be called from its own goroutine.
+func (p *BaseProvider[Resource]) watchResources(watcher resourceWatcher) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-p.quitQueryChannel
+		cancel()
+	}()
+
+	for {
+		// There's no point in opening a watch while we're disconnected from the Cluster Gateway.
+		if p.connection != nil {
+			if err := p.connection.WaitForConnected(ctx); err != nil {
+				app.Log("Ceasing to watch for resource updates.")
+				return
+			}
+		}
+
+		err := watcher.Watch(ctx)
+
+		if ctx.Err() != nil {
+			app.Log("Ceasing to watch for resource updates.")
+			return
+		}
+
+		if status.Code(err) == codes.Unimplemented || domain.ErrorCodeOf(err) == domain.ErrCodeUnknownOp {
+			app.Logf("The source of the resources does not support watches. Polling every %v instead.", p.queryInterval)
+			p.ResourceProvider.QueryResources()
+			return
+		}
+
+		app.Logf("[WARNING] Watch ended unexpectedly: %v. Re-opening it in %v.", err, watchRetryInterval)
+
+		select {
+		case <-time.After(watchRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
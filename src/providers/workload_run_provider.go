@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+)
+
+// Provides the status of the workload run managed by the backend, and forwards the user's start/pause/resume/stop requests to it.
+// There is at most one run at a time, so there is at most one resource.
+type BaseWorkloadRunProvider struct {
+	*BaseProvider[*domain.WorkloadRun]
+}
+
+func NewWorkloadRunProvider(workloadQueryInterval time.Duration, errorHandler domain.ErrorHandler, recorder *metrics.Recorder) *BaseWorkloadRunProvider {
+	provider := &BaseWorkloadRunProvider{BaseProvider: newBaseProvider[*domain.WorkloadRun](workloadQueryInterval, errorHandler, recorder, nil)}
+	provider.ResourceProvider = provider
+	return provider
+}
+
+// Send an operation to the backend's workload endpoint and return the resulting status of the run.
+func (p *BaseWorkloadRunProvider) issueOperation(op string) (*domain.WorkloadRun, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	var run domain.WorkloadRun
+	if err := RequestBackend(ctx, domain.WORKLOAD_ENDPOINT, op, nil, &run); err != nil {
+		app.Logf("Failed to issue workload operation \"%s\" to backend: %v", op, err)
+		return nil, err
+	}
+
+	// Replace the current run.
+	p.resources.Clear()
+	p.resources.Set(run.Id, &run)
+
+	return &run, nil
+}
+
+// Issue a control operation and inform the subscribers of the new state of the run.
+func (p *BaseWorkloadRunProvider) control(op string, action string) error {
+	run, err := p.issueOperation(op)
+	if err != nil {
+		p.errorHandler.HandleError(err, fmt.Sprintf("Failed to %s the workload.", action))
+		return err
+	}
+
+	app.Logf("Workload run %s is now %s.", run.Id, run.State)
+	p.RefreshOccurred()
+	return nil
+}
+
+// Ask the backend to start the workload run.
+func (p *BaseWorkloadRunProvider) StartRun() error {
+	return p.control(domain.WorkloadOpStart, "start")
+}
+
+// Ask the backend to pause the workload run.
+func (p *BaseWorkloadRunProvider) PauseRun() error {
+	return p.control(domain.WorkloadOpPause, "pause")
+}
+
+// Ask the backend to resume the workload run.
+func (p *BaseWorkloadRunProvider) ResumeRun() error {
+	return p.control(domain.WorkloadOpResume, "resume")
+}
+
+// Ask the backend to abort the workload run.
+func (p *BaseWorkloadRunProvider) StopRun() error {
+	return p.control(domain.WorkloadOpStop, "stop")
+}
+
+// Manually/explicitly refresh the status of the workload run from the backend.
+func (p *BaseWorkloadRunProvider) RefreshResources() {
+	locked := p.refreshMutex.TryLock()
+	if !locked {
+		// If we did not acquire the lock, then there's already an active refresh occurring. We'll just return.
+		app.Log("There is already an active refresh operation being performed. Please wait for it to complete.")
+		return
+	}
+	defer p.refreshMutex.Unlock()
+
+	if _, err := p.issueOperation(domain.WorkloadOpRequest); err != nil {
+		app.Logf("Failed to refresh the workload run: %v", err)
+		return
+	}
+
+	p.RefreshOccurred()
+}
@@ -0,0 +1,26 @@
+//go:build !js
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"nhooyr.io/websocket"
+)
+
+// Headers passed to DialWebSocket are sent with the handshake.
+const HeadersSupported = true
+
+// Open a websocket, sending the given headers with the handshake, and using the given TLS configuration for wss:// URLs.
+// Either may be nil.
+func DialWebSocket(ctx context.Context, url string, tlsConfig *tls.Config, header http.Header) (*websocket.Conn, error) {
+	opts := &websocket.DialOptions{HTTPHeader: header}
+	if tlsConfig != nil {
+		opts.HTTPClient = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	}
+
+	conn, _, err := websocket.Dial(ctx, url, opts)
+	return conn, err
+}
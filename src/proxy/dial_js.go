@@ -0,0 +1,18 @@
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"nhooyr.io/websocket"
+)
+
+// Browsers cannot set headers when opening websockets, so headers passed to DialWebSocket are ignored.
+const HeadersSupported = false
+
+// Open a websocket. The browser's own TLS configuration is used for wss:// URLs, so tlsConfig and header are ignored.
+func DialWebSocket(ctx context.Context, url string, tlsConfig *tls.Config, header http.Header) (*websocket.Conn, error) {
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	return conn, err
+}
@@ -0,0 +1,222 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+const (
+	// Maximum size of a single websocket message. gRPC frames are at most 16KB by default, but may be larger.
+	maxMessageSize = 4 * 1024 * 1024
+)
+
+type WebSocketProxyListenerOptions struct {
+	MaxConnections int           // Maximum number of connections open at once. Further connections are refused. Zero means unlimited.
+	IdleTimeout    time.Duration // Connections on which nothing is read or written for this long are closed. Zero means never.
+	OriginPatterns []string      // Hosts (filepath.Match patterns) of the cross-origin pages that may connect. Same-origin connections are always allowed.
+}
+
+// The server-side counterpart of WebSocketProxyClient. Accepts websocket connections over HTTP and hands them
+// to a gRPC server (or anything else that serves a net.Listener) as ordinary network connections.
+//
+// /* Begin Example: */
+//
+// listener := websocketproxy.NewWebSocketProxyListener(tcpListener.Addr())
+//
+// go http.Serve(tcpListener, listener)
+// go grpcServer.Serve(listener)
+//
+// /* End Example */
+type WebSocketProxyListener struct {
+	addr      net.Addr
+	opts      WebSocketProxyListenerOptions
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	slots  chan struct{} // Holds one entry per open connection, if the number of connections is limited.
+	active atomic.Int32  // The number of open connections.
+}
+
+func NewWebSocketProxyListener(addr net.Addr) *WebSocketProxyListener {
+	return NewWebSocketProxyListenerWithOptions(addr, WebSocketProxyListenerOptions{})
+}
+
+func NewWebSocketProxyListenerWithOptions(addr net.Addr, opts WebSocketProxyListenerOptions) *WebSocketProxyListener {
+	listener := &WebSocketProxyListener{
+		addr:   addr,
+		opts:   opts,
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+
+	if opts.MaxConnections > 0 {
+		listener.slots = make(chan struct{}, opts.MaxConnections)
+	}
+
+	return listener
+}
+
+// Upgrade the request to a websocket connection and queue it to be accepted.
+// The request is refused with 503 Service Unavailable if the maximum number of connections are already open, and
+// with 403 Forbidden if it was sent by a page of an origin that isn't allowed.
+func (l *WebSocketProxyListener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			http.Error(w, "too many connections", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	// The frontend is usually served from a different origin than the one we're listening on, so its origin must be
+	// among the patterns. Requests from other origins are refused with 403 Forbidden.
+	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: l.opts.OriginPatterns})
+	if err != nil {
+		l.release()
+		return
+	}
+	c.SetReadLimit(maxMessageSize)
+
+	// The connection outlives this request, so it must not be bound to the request's context.
+	conn := l.track(websocket.NetConn(context.Background(), c, websocket.MessageBinary))
+
+	select {
+	case l.conns <- conn:
+	case <-l.closed:
+		c.Close(websocket.StatusGoingAway, "listener closed")
+		conn.Close()
+	}
+}
+
+// Wait for and return the next connection.
+func (l *WebSocketProxyListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Stop accepting connections. Connections that have already been accepted are unaffected.
+func (l *WebSocketProxyListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+
+	return nil
+}
+
+// Return the address on which the underlying HTTP server is listening.
+func (l *WebSocketProxyListener) Addr() net.Addr {
+	return l.addr
+}
+
+// Return the number of connections that are currently open, including those not yet accepted.
+func (l *WebSocketProxyListener) NumConnections() int {
+	return int(l.active.Load())
+}
+
+// Wrap the connection so that it is counted until it is closed, and closed if it is idle for too long.
+func (l *WebSocketProxyListener) track(conn net.Conn) net.Conn {
+	l.active.Add(1)
+
+	tracked := &trackedConn{Conn: conn, onClose: func() {
+		l.active.Add(-1)
+		l.release()
+	}}
+	tracked.touch()
+
+	if l.opts.IdleTimeout > 0 {
+		tracked.watchIdle(l.opts.IdleTimeout)
+	}
+
+	return tracked
+}
+
+func (l *WebSocketProxyListener) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// A connection accepted by a WebSocketProxyListener. Records when it was last used, and releases its slot once closed.
+type trackedConn struct {
+	net.Conn
+
+	lastActive atomic.Int64 // When the connection was last read from or written to, in nanoseconds since the epoch.
+	onClose    func()
+	closeOnce  sync.Once
+	closed     atomic.Bool
+	idleTimer  *time.Timer
+	timerMutex sync.Mutex // Synchronizes access to idleTimer, which is re-armed by its own callback.
+}
+
+func (c *trackedConn) touch() {
+	c.lastActive.Store(time.Now().UnixNano())
+}
+
+func (c *trackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.touch()
+	}
+	return n, err
+}
+
+func (c *trackedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.touch()
+	}
+	return n, err
+}
+
+// The connection stops counting towards the limit immediately, rather than once the websocket's close handshake is done.
+func (c *trackedConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+
+		c.timerMutex.Lock()
+		if c.idleTimer != nil {
+			c.idleTimer.Stop()
+		}
+		c.timerMutex.Unlock()
+
+		c.onClose()
+	})
+
+	return c.Conn.Close()
+}
+
+// Close the connection once nothing has been read from or written to it for the given duration.
+func (c *trackedConn) watchIdle(timeout time.Duration) {
+	var check func()
+	check = func() {
+		if c.closed.Load() {
+			return
+		}
+
+		idle := time.Since(time.Unix(0, c.lastActive.Load()))
+		if idle >= timeout {
+			c.Close()
+			return
+		}
+
+		c.timerMutex.Lock()
+		c.idleTimer = time.AfterFunc(timeout-idle, check)
+		c.timerMutex.Unlock()
+	}
+
+	c.timerMutex.Lock()
+	c.idleTimer = time.AfterFunc(timeout, check)
+	c.timerMutex.Unlock()
+}
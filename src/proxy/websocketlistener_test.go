@@ -0,0 +1,233 @@
+//go:build !js
+
+package proxy_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/cluster"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"nhooyr.io/websocket"
+)
+
+// Serve a fake Cluster Gateway via a WebSocketProxyListener with the given options. Returns the listener, and the
+// address of the HTTP server through which it accepts connections.
+func startListener(t *testing.T, opts proxy.WebSocketProxyListenerOptions) (*proxy.WebSocketProxyListener, string) {
+	t.Helper()
+
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	listener := proxy.NewWebSocketProxyListenerWithOptions(tcpListener.Addr(), opts)
+
+	httpServer := &http.Server{Handler: listener}
+	go httpServer.Serve(tcpListener)
+
+	grpcServer := grpc.NewServer()
+	gateway.RegisterClusterGatewayServer(grpcServer, cluster.NewFakeCluster(time.Hour, 0))
+	go grpcServer.Serve(listener)
+
+	t.Cleanup(func() {
+		grpcServer.Stop()
+		httpServer.Close()
+	})
+
+	return listener, tcpListener.Addr().String()
+}
+
+// Connect to the fake Cluster Gateway via a WebSocketProxyClient, and check that it responds.
+func dialGateway(t *testing.T, addr string) *grpc.ClientConn {
+	t.Helper()
+
+	client := proxy.NewWebSocketProxyClient(time.Second * 5)
+	conn, err := grpc.Dial("ws://"+addr, grpc.WithContextDialer(client.Dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial %s: %v", addr, err)
+	}
+
+	callGateway(t, conn)
+
+	return conn
+}
+
+func callGateway(t *testing.T, conn *grpc.ClientConn) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if _, err := gateway.NewClusterGatewayClient(conn).ID(ctx, &gateway.Void{}); err != nil {
+		t.Fatalf("ID RPC failed: %v", err)
+	}
+}
+
+// Open a websocket to the listener directly, as if from a page of the given origin, if any. Returns the status of the
+// handshake, and the websocket if it succeeded.
+func dialWebSocket(t *testing.T, addr string, origin string) (*websocket.Conn, int) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	header := make(http.Header)
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+
+	c, resp, err := websocket.Dial(ctx, "ws://"+addr, &websocket.DialOptions{HTTPHeader: header})
+	if resp == nil {
+		t.Fatalf("Failed to dial %s: %v", addr, err)
+	}
+
+	return c, resp.StatusCode
+}
+
+// Wait until the listener has the expected number of open connections.
+func expectConnections(t *testing.T, listener *proxy.WebSocketProxyListener, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second * 5)
+	for listener.NumConnections() != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("Listener has %d open connections, expected %d", listener.NumConnections(), expected)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+func TestWebSocketProxyListenerServesGRPC(t *testing.T) {
+	listener, addr := startListener(t, proxy.WebSocketProxyListenerOptions{})
+	expectConnections(t, listener, 0)
+
+	conn := dialGateway(t, addr)
+	expectConnections(t, listener, 1)
+
+	callGateway(t, conn)
+
+	conn.Close()
+	expectConnections(t, listener, 0)
+}
+
+func TestWebSocketProxyListenerMaxConnections(t *testing.T) {
+	listener, addr := startListener(t, proxy.WebSocketProxyListenerOptions{MaxConnections: 1})
+
+	conn := dialGateway(t, addr)
+	expectConnections(t, listener, 1)
+
+	// Further connections are refused while the only slot is taken.
+	if c, status := dialWebSocket(t, addr, ""); status != http.StatusServiceUnavailable {
+		if c != nil {
+			c.CloseNow()
+		}
+		t.Fatalf("Connection past the limit got status %d, expected %d", status, http.StatusServiceUnavailable)
+	}
+	expectConnections(t, listener, 1)
+
+	// The refused connection didn't disturb the one that was accepted.
+	callGateway(t, conn)
+
+	// Closing the connection frees its slot.
+	conn.Close()
+	expectConnections(t, listener, 0)
+
+	c, status := dialWebSocket(t, addr, "")
+	if status != http.StatusSwitchingProtocols {
+		t.Fatalf("Connection after the slot was freed got status %d, expected %d", status, http.StatusSwitchingProtocols)
+	}
+	expectConnections(t, listener, 1)
+
+	c.Close(websocket.StatusNormalClosure, "")
+	expectConnections(t, listener, 0)
+}
+
+func TestWebSocketProxyListenerIdleTimeout(t *testing.T) {
+	idleTimeout := time.Millisecond * 300
+	listener, addr := startListener(t, proxy.WebSocketProxyListenerOptions{MaxConnections: 1, IdleTimeout: idleTimeout})
+
+	// A connection that's in use isn't closed, however long it stays open.
+	conn := dialGateway(t, addr)
+	for start := time.Now(); time.Since(start) < idleTimeout*3; time.Sleep(idleTimeout / 5) {
+		callGateway(t, conn)
+		if n := listener.NumConnections(); n != 1 {
+			t.Fatalf("Listener has %d open connections while the connection is in use, expected 1", n)
+		}
+	}
+	conn.Close()
+	expectConnections(t, listener, 0)
+
+	// A connection on which nothing is sent is closed once it has been idle for the timeout.
+	c, status := dialWebSocket(t, addr, "")
+	if status != http.StatusSwitchingProtocols {
+		t.Fatalf("Connection got status %d, expected %d", status, http.StatusSwitchingProtocols)
+	}
+	defer c.CloseNow()
+
+	opened := time.Now()
+	expectConnections(t, listener, 1)
+
+	// Read until the listener closes the connection, discarding whatever the gRPC server sent when it was accepted.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	for {
+		if _, _, err := c.Read(ctx); err != nil {
+			if ctx.Err() != nil {
+				t.Fatal("Idle connection wasn't closed.")
+			}
+			break
+		}
+	}
+
+	if idle := time.Since(opened); idle < idleTimeout {
+		t.Errorf("Connection was closed after %v, before the idle timeout of %v", idle, idleTimeout)
+	}
+	expectConnections(t, listener, 0)
+
+	// The idle connection's slot was freed.
+	c2, status := dialWebSocket(t, addr, "")
+	if status != http.StatusSwitchingProtocols {
+		t.Fatalf("Connection after the idle connection was closed got status %d, expected %d", status, http.StatusSwitchingProtocols)
+	}
+	c2.CloseNow()
+}
+
+func TestWebSocketProxyListenerOrigins(t *testing.T) {
+	listener, addr := startListener(t, proxy.WebSocketProxyListenerOptions{OriginPatterns: []string{"localhost:8000", "*.example.com"}})
+
+	tests := []struct {
+		name   string
+		origin string
+		status int
+	}{
+		{name: "no origin", origin: "", status: http.StatusSwitchingProtocols},
+		{name: "same origin", origin: "http://" + addr, status: http.StatusSwitchingProtocols},
+		{name: "allowed origin", origin: "http://localhost:8000", status: http.StatusSwitchingProtocols},
+		{name: "allowed origin pattern", origin: "https://dashboard.example.com", status: http.StatusSwitchingProtocols},
+		{name: "other port", origin: "http://localhost:8001", status: http.StatusForbidden},
+		{name: "other origin", origin: "https://attacker.test", status: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, status := dialWebSocket(t, addr, test.origin)
+			if c != nil {
+				defer c.CloseNow()
+			}
+
+			if status != test.status {
+				t.Fatalf("Connection from origin %q got status %d, expected %d", test.origin, status, test.status)
+			}
+		})
+	}
+
+	// Refused connections don't count towards the limit.
+	expectConnections(t, listener, 0)
+}
@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"time"
 
@@ -14,7 +15,8 @@ import (
 // TODO(Ben): Update license accordingly.
 
 type WebSocketProxyClient struct {
-	timeout time.Duration
+	timeout   time.Duration
+	tlsConfig *tls.Config // Used when dialing wss:// URLs. Nil to use the defaults.
 }
 
 func NewWebSocketProxyClient(timeout time.Duration) *WebSocketProxyClient {
@@ -25,6 +27,12 @@ func NewWebSocketProxyClient(timeout time.Duration) *WebSocketProxyClient {
 	return client
 }
 
+// Use the given TLS configuration when dialing wss:// URLs. Ignored in the browser, which uses its own.
+func (p *WebSocketProxyClient) WithTLSConfig(tlsConfig *tls.Config) *WebSocketProxyClient {
+	p.tlsConfig = tlsConfig
+	return p
+}
+
 // Pass this to the grpc.Dial function, wrapped in a grpc.WithContextDialer.
 //
 // /* Begin Example: */
@@ -44,7 +52,7 @@ func (p *WebSocketProxyClient) Dialer(ctx context.Context, url string) (net.Conn
 	ctx, cancel := context.WithTimeout(ctx, p.timeout)
 	defer cancel()
 
-	conn, _, err := websocket.Dial(ctx, url, nil)
+	conn, err := DialWebSocket(ctx, url, p.tlsConfig, nil)
 	if err != nil {
 		return nil, err
 	}
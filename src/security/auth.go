@@ -0,0 +1,206 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Query parameter with which clients that cannot set headers (i.e., browsers opening websockets) pass the bearer token.
+	TokenQueryParam = "access_token"
+
+	// Query parameters with which clients sign their requests.
+	TimestampQueryParam = "ts"
+	NonceQueryParam     = "nonce"
+	SignatureQueryParam = "sig"
+
+	// How far the timestamp of a signed request may be from the server's clock.
+	maxSignatureSkew = time.Minute * 5
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// The credentials with which a client authenticates with the backend.
+type Credentials struct {
+	Token      string // Sent as a bearer token.
+	HmacSecret string // Used to sign each request.
+}
+
+func (c Credentials) Empty() bool {
+	return c.Token == "" && c.HmacSecret == ""
+}
+
+// Add the credentials to a request with the given method for the given URL. The token is sent in the Authorization
+// header, or, if header is nil (as browsers cannot set headers when opening websockets), as a query parameter instead.
+func (c Credentials) Apply(method string, u *url.URL, header http.Header, now time.Time) {
+	query := u.Query()
+
+	if c.Token != "" {
+		if header != nil {
+			header.Set("Authorization", "Bearer "+c.Token)
+		} else {
+			query.Set(TokenQueryParam, c.Token)
+		}
+	}
+
+	if c.HmacSecret != "" {
+		query.Set(TimestampQueryParam, strconv.FormatInt(now.Unix(), 10))
+		query.Set(NonceQueryParam, newNonce())
+		query.Set(SignatureQueryParam, Sign(c.HmacSecret, method, u.Path, query))
+	}
+
+	u.RawQuery = query.Encode()
+}
+
+// Return a random nonce, so that no two signed requests are alike.
+func newNonce() string {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(nonce)
+}
+
+// Return the signature of a request with the given method, path, and query parameters, which must include the
+// timestamp and the nonce. The signature is the HMAC of the method, the path, and the query parameters other than the
+// signature itself (sorted by name, and URL-encoded), separated by newlines.
+func Sign(secret string, method string, path string, query url.Values) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.ToUpper(method) + "\n" + path + "\n" + canonicalQuery(query)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Encode the query parameters other than the signature, sorted by name.
+func canonicalQuery(query url.Values) string {
+	signed := make(url.Values, len(query))
+	for name, values := range query {
+		if name != SignatureQueryParam {
+			signed[name] = values
+		}
+	}
+
+	return signed.Encode()
+}
+
+// Verifies that requests carry either the bearer token or a valid signature. If neither a token nor a secret is
+// configured, then every request is accepted. A signed request cannot be replayed: its nonce is remembered for as long
+// as its timestamp is within the allowed skew, and requests that reuse it are rejected.
+type Authenticator struct {
+	token      string
+	hmacSecret string
+	now        func() time.Time
+
+	noncesMutex sync.Mutex
+	nonces      map[string]time.Time // The nonces of the signed requests accepted so far, and when each can be forgotten.
+}
+
+func NewAuthenticator(token string, hmacSecret string) *Authenticator {
+	return &Authenticator{
+		token:      token,
+		hmacSecret: hmacSecret,
+		now:        time.Now,
+		nonces:     make(map[string]time.Time),
+	}
+}
+
+func (a *Authenticator) Enabled() bool {
+	return a.token != "" || a.hmacSecret != ""
+}
+
+// Return nil if the request is authenticated, or else an error wrapping ErrUnauthorized.
+func (a *Authenticator) Authenticate(r *http.Request) error {
+	if !a.Enabled() {
+		return nil
+	}
+
+	if a.token != "" {
+		if token := bearerToken(r); token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1 {
+			return nil
+		}
+	}
+
+	if a.hmacSecret != "" {
+		err := a.verifySignature(r)
+		if err == nil {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+
+	return fmt.Errorf("%w: missing or invalid bearer token", ErrUnauthorized)
+}
+
+func (a *Authenticator) verifySignature(r *http.Request) error {
+	query := r.URL.Query()
+	ts, nonce, sig := query.Get(TimestampQueryParam), query.Get(NonceQueryParam), query.Get(SignatureQueryParam)
+	if ts == "" || nonce == "" || sig == "" {
+		return errors.New("missing signature")
+	}
+
+	seconds, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp \"%s\"", ts)
+	}
+
+	now := a.now()
+	issuedAt := time.Unix(seconds, 0)
+	if skew := now.Sub(issuedAt); skew > maxSignatureSkew || skew < -maxSignatureSkew {
+		return fmt.Errorf("timestamp is %v away from the server's clock", skew.Round(time.Second))
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(Sign(a.hmacSecret, r.Method, r.URL.Path, query))) {
+		return errors.New("invalid signature")
+	}
+
+	// Once the timestamp is outside of the allowed skew, the request would be rejected anyway, so the nonce can be forgotten.
+	if !a.useNonce(nonce, now, issuedAt.Add(maxSignatureSkew)) {
+		return errors.New("the signature has already been used")
+	}
+
+	return nil
+}
+
+// Record that the nonce has been used, until the given time. Returns false if it has already been used.
+func (a *Authenticator) useNonce(nonce string, now time.Time, until time.Time) bool {
+	a.noncesMutex.Lock()
+	defer a.noncesMutex.Unlock()
+
+	for used, expiry := range a.nonces {
+		if now.After(expiry) {
+			delete(a.nonces, used)
+		}
+	}
+
+	if _, ok := a.nonces[nonce]; ok {
+		return false
+	}
+
+	a.nonces[nonce] = until
+	return true
+}
+
+// Return the bearer token from the Authorization header or, failing that, from the query parameters.
+func bearerToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return token
+		}
+	}
+
+	return r.URL.Query().Get(TokenQueryParam)
+}
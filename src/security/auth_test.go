@@ -0,0 +1,162 @@
+package security
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Return a request signed with the credentials, as a client would send it.
+func signedRequest(t *testing.T, credentials Credentials, method string, rawURL string, now time.Time) *http.Request {
+	t.Helper()
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("Invalid URL %s: %v", rawURL, err)
+	}
+	credentials.Apply(method, u, nil, now)
+
+	return httptest.NewRequest(method, u.String(), nil)
+}
+
+func TestAuthenticateSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	credentials := Credentials{HmacSecret: "secret"}
+
+	tests := []struct {
+		name   string
+		tamper func(r *http.Request)
+		valid  bool
+	}{
+		{name: "valid", tamper: func(r *http.Request) {}, valid: true},
+		{name: "other method", tamper: func(r *http.Request) { r.Method = http.MethodPost }},
+		{name: "other path", tamper: func(r *http.Request) { r.URL.Path = "/api/config" }},
+		{
+			name: "added query parameter",
+			tamper: func(r *http.Request) {
+				query := r.URL.Query()
+				query.Set("watch", "true")
+				r.URL.RawQuery = query.Encode()
+			},
+		},
+		{
+			name: "changed query parameter",
+			tamper: func(r *http.Request) {
+				query := r.URL.Query()
+				query.Set("node", "other")
+				r.URL.RawQuery = query.Encode()
+			},
+		},
+		{
+			name: "missing nonce",
+			tamper: func(r *http.Request) {
+				query := r.URL.Query()
+				query.Del(NonceQueryParam)
+				r.URL.RawQuery = query.Encode()
+			},
+		},
+		{
+			name: "other secret",
+			tamper: func(r *http.Request) {
+				query := r.URL.Query()
+				query.Set(SignatureQueryParam, Sign("other", r.Method, r.URL.Path, query))
+				r.URL.RawQuery = query.Encode()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			authenticator := NewAuthenticator("", credentials.HmacSecret)
+			authenticator.now = func() time.Time { return now }
+
+			r := signedRequest(t, credentials, http.MethodGet, "http://localhost:8000/api/nodes?node=a", now)
+			test.tamper(r)
+
+			err := authenticator.Authenticate(r)
+			if test.valid && err != nil {
+				t.Errorf("Valid request was rejected: %v", err)
+			}
+			if !test.valid && !errors.Is(err, ErrUnauthorized) {
+				t.Errorf("Invalid request was accepted (error: %v)", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRejectsReplays(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	credentials := Credentials{HmacSecret: "secret"}
+
+	authenticator := NewAuthenticator("", credentials.HmacSecret)
+	authenticator.now = func() time.Time { return now }
+
+	r := signedRequest(t, credentials, http.MethodGet, "http://localhost:8000/api/nodes", now)
+	if err := authenticator.Authenticate(r); err != nil {
+		t.Fatalf("Valid request was rejected: %v", err)
+	}
+
+	// The same request cannot be sent again while its timestamp is valid.
+	replay := httptest.NewRequest(http.MethodGet, r.URL.String(), nil)
+	if err := authenticator.Authenticate(replay); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Replayed request was accepted (error: %v)", err)
+	}
+
+	// Each request has its own nonce, so requests signed at the same time are all accepted.
+	if err := authenticator.Authenticate(signedRequest(t, credentials, http.MethodGet, "http://localhost:8000/api/nodes", now)); err != nil {
+		t.Errorf("Second request was rejected: %v", err)
+	}
+
+	// Once the timestamp has expired, the nonce is forgotten, but the request is rejected anyway.
+	now = now.Add(maxSignatureSkew + time.Second)
+	if err := authenticator.Authenticate(httptest.NewRequest(http.MethodGet, r.URL.String(), nil)); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expired request was accepted (error: %v)", err)
+	}
+
+	if err := authenticator.Authenticate(signedRequest(t, credentials, http.MethodGet, "http://localhost:8000/api/nodes", now)); err != nil {
+		t.Errorf("Request signed after the others expired was rejected: %v", err)
+	}
+
+	authenticator.noncesMutex.Lock()
+	numNonces := len(authenticator.nonces)
+	authenticator.noncesMutex.Unlock()
+	if numNonces != 1 {
+		t.Errorf("Authenticator remembers %d nonces, expected only that of the latest request", numNonces)
+	}
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	authenticator := NewAuthenticator("token", "")
+
+	tests := []struct {
+		name   string
+		header string
+		query  string
+		valid  bool
+	}{
+		{name: "header", header: "Bearer token", valid: true},
+		{name: "query parameter", query: "?" + TokenQueryParam + "=token", valid: true},
+		{name: "wrong token", header: "Bearer other"},
+		{name: "missing token"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/nodes"+test.query, nil)
+			if test.header != "" {
+				r.Header.Set("Authorization", test.header)
+			}
+
+			err := authenticator.Authenticate(r)
+			if test.valid && err != nil {
+				t.Errorf("Valid request was rejected: %v", err)
+			}
+			if !test.valid && !errors.Is(err, ErrUnauthorized) {
+				t.Errorf("Invalid request was accepted (error: %v)", err)
+			}
+		})
+	}
+}
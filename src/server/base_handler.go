@@ -3,28 +3,75 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
+	"sync"
 
 	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/security"
 	"go.uber.org/zap"
 	"nhooyr.io/websocket"
 	"nhooyr.io/websocket/wsjson"
 )
 
+const (
+	// Maximum size of a single request from a client. The code of an execute request may be large.
+	maxRequestSize = 1 << 20
+)
+
+// Handles a single request. The value returned is sent to the client as the payload of the final response. Streaming
+// operations may send any number of intermediate responses beforehand, via OpRequest.Send. If an error is returned,
+// then it is sent instead, with the code of the *domain.ResponseError in its chain, or else as an internal error.
+type OpHandler func(ctx context.Context, req *OpRequest) (interface{}, error)
+
+// A request that is being dispatched to an OpHandler.
+type OpRequest struct {
+	*domain.Request
+
+	HttpRequest *http.Request // The request with which the websocket was opened.
+
+	send func(*domain.Response) error
+}
+
+// Unmarshal the request's payload into v. Returns an ErrCodeBadRequest error if it cannot be unmarshalled.
+func (r *OpRequest) Decode(v interface{}) error {
+	if len(r.Payload) == 0 {
+		return domain.NewResponseError(domain.ErrCodeBadRequest, "The \"%s\" operation requires a payload.", r.Op)
+	}
+
+	if err := json.Unmarshal(r.Payload, v); err != nil {
+		return domain.NewResponseError(domain.ErrCodeBadRequest, "Invalid payload for the \"%s\" operation: %v", r.Op, err)
+	}
+
+	return nil
+}
+
+// Send an intermediate response to the client.
+func (r *OpRequest) Send(payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return r.send(&domain.Response{Version: domain.ProtocolVersion, Id: r.Id, Op: r.Op, Payload: data})
+}
+
+// Accepts websockets, and dispatches each request received over them to the OpHandler registered for its op.
 type BaseHandler struct {
 	http.Handler
 
-	Logger *zap.Logger
-	opts   *config.Configuration
-
-	BackendHttpHandler domain.BackendHttpHandler
+	Logger        *zap.Logger
+	opts          *config.Configuration
+	authenticator *security.Authenticator // Verifies that requests carry the configured token or a valid signature, if either is configured.
+	ops           map[string]OpHandler    // Registered by the concrete handlers when they're created.
 }
 
 func NewBaseHandler(opts *config.Configuration) *BaseHandler {
 	handler := &BaseHandler{
-		opts: opts,
+		opts:          opts,
+		authenticator: security.NewAuthenticator(opts.AuthToken, opts.AuthHmacSecret),
+		ops:           make(map[string]OpHandler),
 	}
 
 	var err error
@@ -33,61 +80,126 @@ func NewBaseHandler(opts *config.Configuration) *BaseHandler {
 		panic(err)
 	}
 
-	handler.BackendHttpHandler = handler
-
 	return handler
 }
 
-// Write an error back to the client.
-func (h *BaseHandler) WriteError(c *websocket.Conn, errorMessage string) {
-	// Write error back to front-end.
-	msg := &domain.ErrorMessage{
-		ErrorMessage: errorMessage,
-		Valid:        true,
-	}
-	msgJSON, _ := json.Marshal(msg)
-
-	err := c.Write(context.Background(), websocket.MessageBinary, msgJSON)
-	if err != nil {
-		h.Logger.Error("Error while writing error message back to front-end.", zap.String("original-error-message", errorMessage), zap.Error(err))
-	}
+// Dispatch requests with the given op to the handler. This must be called before the handler starts serving requests.
+func (h *BaseHandler) RegisterOp(op string, handler OpHandler) {
+	h.ops[op] = handler
 }
 
+// Accept a websocket, and serve the requests sent over it until the client closes it. Each request is handled in its
+// own goroutine, so a streaming operation doesn't hold up the requests sent after it.
 func (h *BaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	h.Logger.Info("Trying to accept a websocket connection now.")
+	if err := h.authenticator.Authenticate(r); err != nil {
+		h.Logger.Warn("Rejected unauthenticated request.", zap.String("path", r.URL.Path), zap.String("remote-addr", r.RemoteAddr), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
 
-	c, err := websocket.Accept(w, r, nil)
+	// Same-origin websockets are always allowed, as are those from the configured origins, e.g., of a separately hosted dashboard.
+	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: h.opts.AllowedOrigins})
 	if err != nil {
 		h.Logger.Error("Failed to accept websocket connection.", zap.Error(err))
 		return
 	}
 	defer c.CloseNow()
+	c.SetReadLimit(maxRequestSize)
+
+	h.Logger.Debug("Accepted websocket connection.", zap.String("path", r.URL.Path), zap.String("remote-addr", r.RemoteAddr))
 
-	ctx, cancel := context.WithTimeout(r.Context(), time.Second*10)
+	// Cancelled once the client goes away, which stops the requests that are still being handled.
+	ctx, cancel := context.WithCancel(r.Context())
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
 	defer cancel()
 
-	h.Logger.Info("Accepted websockets connection.", zap.Any("connection", c))
+	send := func(resp *domain.Response) error {
+		return wsjson.Write(ctx, c, resp)
+	}
 
-	var payload map[string]interface{}
-	err = wsjson.Read(ctx, c, &payload)
-	if err != nil {
-		h.Logger.Error("Failed to read data from websocket connection.", zap.Error(err))
-		h.WriteError(c, "Failed to read any data.")
+	for {
+		_, data, err := c.Read(ctx)
+		if err != nil {
+			if status := websocket.CloseStatus(err); status != websocket.StatusNormalClosure && status != websocket.StatusGoingAway {
+				h.Logger.Debug("Websocket connection closed.", zap.String("path", r.URL.Path), zap.Error(err))
+			}
+			return
+		}
+
+		req := &domain.Request{}
+		if err := json.Unmarshal(data, req); err != nil {
+			h.Logger.Warn("Received malformed request.", zap.String("path", r.URL.Path), zap.Error(err))
+			h.respond(send, req, nil, domain.NewResponseError(domain.ErrCodeBadRequest, "Malformed request: %v", err))
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.dispatch(ctx, &OpRequest{Request: req, HttpRequest: r, send: send})
+		}()
+	}
+}
+
+// Pass the request to the handler registered for its op, and send the handler's result as the final response.
+func (h *BaseHandler) dispatch(ctx context.Context, req *OpRequest) {
+	if req.Version != domain.ProtocolVersion {
+		h.respond(req.send, req.Request, nil, domain.NewResponseError(domain.ErrCodeUnsupportedVersion, "Unsupported protocol version %d. The backend speaks version %d.", req.Version, domain.ProtocolVersion))
 		return
 	}
 
-	// The payload will likely be a dict with a single entry "payload" containing the dict that the client sent.
-	if _, ok := payload["payload"]; ok {
-		payload = payload["payload"].(map[string]interface{})
+	if req.Op == "" {
+		h.respond(req.send, req.Request, nil, domain.NewResponseError(domain.ErrCodeBadRequest, "The request has no op."))
+		return
 	}
 
-	h.HandleRequest(c, r, payload)
+	handler, ok := h.ops[req.Op]
+	if !ok {
+		h.Logger.Error("Unexpected operation requested from client.", zap.String("op", req.Op), zap.String("path", req.HttpRequest.URL.Path))
+		h.respond(req.send, req.Request, nil, domain.NewResponseError(domain.ErrCodeUnknownOp, "Unexpected operation: %s", req.Op))
+		return
+	}
 
-	c.Close(websocket.StatusNormalClosure, "")
+	h.Logger.Debug("Handling request.", zap.String("op", req.Op), zap.String("id", req.Id))
+
+	result, err := handler(ctx, req)
+	if err != nil {
+		var responseErr *domain.ResponseError
+		if !errors.As(err, &responseErr) {
+			responseErr = domain.NewResponseError(domain.ErrCodeInternal, "%v", err)
+		}
+
+		h.Logger.Error("Failed to handle request.", zap.String("op", req.Op), zap.String("id", req.Id), zap.String("code", string(responseErr.Code)), zap.Error(err))
+		h.respond(req.send, req.Request, nil, responseErr)
+		return
+	}
+
+	h.respond(req.send, req.Request, result, nil)
 }
 
-// It would make sense to add some sort of security/verification here so that we only respond to requests from the front-end.
-// But that doesn't really matter for debugging and development purposes.
-func (h *BaseHandler) HandleRequest(c *websocket.Conn, r *http.Request, payload map[string]interface{}) {
-	h.BackendHttpHandler.HandleRequest(c, r, payload)
+// Send the final response to the request: either the result, or the error if it is non-nil.
+func (h *BaseHandler) respond(send func(*domain.Response) error, req *domain.Request, result interface{}, responseErr *domain.ResponseError) {
+	resp := &domain.Response{
+		Version: domain.ProtocolVersion,
+		Id:      req.Id,
+		Op:      req.Op,
+		Final:   true,
+		Error:   responseErr,
+	}
+
+	if responseErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			h.Logger.Error("Failed to marshal response to JSON.", zap.String("op", req.Op), zap.Error(err))
+			resp.Error = domain.NewResponseError(domain.ErrCodeInternal, "Failed to marshal response to JSON: %v", err)
+		} else {
+			resp.Payload = data
+		}
+	}
+
+	if err := send(resp); err != nil {
+		h.Logger.Warn("Failed to write response back to client.", zap.String("op", req.Op), zap.String("id", req.Id), zap.Error(err))
+	}
 }
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/driver"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// How long code may execute before we give up on it.
+	executionTimeout = time.Minute * 10
+)
+
+// Executes code on kernels on behalf of the frontend. The outputs are streamed back to the frontend as intermediate
+// responses as they are produced, and the kernel's reply is the final response. Requests that target a single replica
+// are rejected, as the Cluster Gateway cannot route them.
+type ExecuteHttpHandler struct {
+	*BaseHandler
+
+	executor domain.CodeExecutor
+}
+
+func NewExecuteHttpHandler(opts *config.Configuration) *ExecuteHttpHandler {
+	handler := &ExecuteHttpHandler{
+		BaseHandler: NewBaseHandler(opts),
+	}
+	// Only used to execute code, so no kernel manager is needed.
+	handler.executor = driver.NewSessionManager(opts, nil, metrics.NewRecorder())
+	handler.RegisterOp(domain.ExecuteOp, handler.execute)
+
+	handler.Logger.Info("Creating server-side ExecuteHttpHandler.", zap.Bool("spoof", opts.SpoofCluster))
+
+	return handler
+}
+
+func (h *ExecuteHttpHandler) execute(ctx context.Context, req *OpRequest) (interface{}, error) {
+	var execReq domain.ExecuteRequest
+	if err := req.Decode(&execReq); err != nil {
+		return nil, err
+	}
+
+	if execReq.KernelId == "" {
+		return nil, domain.NewResponseError(domain.ErrCodeBadRequest, "Invalid execute request. A kernel ID is required.")
+	}
+
+	h.Logger.Info("Executing code.", zap.String("target", execReq.String()), zap.Int("code-length", len(execReq.Code)))
+
+	ctx, cancel := context.WithTimeout(ctx, executionTimeout)
+	defer cancel()
+
+	reply, err := h.executor.Execute(ctx, &execReq, func(output *domain.ExecutionOutput) {
+		if err := req.Send(output); err != nil {
+			h.Logger.Warn("Failed to write output to frontend.", zap.String("target", execReq.String()), zap.Error(err))
+		}
+	})
+
+	if errors.Is(err, domain.ErrReplicaExecutionUnsupported) {
+		return nil, domain.NewResponseError(domain.ErrCodeFailedPrecondition, "Cannot execute code on %s: %v", execReq.String(), err)
+	}
+	if err != nil {
+		return nil, domain.NewResponseError(domain.ErrCodeUnavailable, "Failed to execute code on %s: %v", execReq.String(), err)
+	}
+
+	h.Logger.Info("Executed code.", zap.String("target", execReq.String()), zap.String("status", reply.Status))
+
+	return reply, nil
+}
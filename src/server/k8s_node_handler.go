@@ -2,40 +2,56 @@ package server
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	"sort"
 	"time"
 
+	"github.com/scusemua/djn-workload-driver/m/v2/src/cluster"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
 	"go.uber.org/zap"
-	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
-	"nhooyr.io/websocket"
+)
+
+const (
+	// How often the resource usage of the nodes of the Kubernetes cluster is polled, if the node query interval is invalid.
+	defaultNodeWatchInterval = time.Second * 10
+
+	// How long a request waits for the node cache to sync with Kubernetes, e.g., just after the backend has started.
+	nodeCacheSyncTimeout = time.Second * 15
 )
 
 type KubeNodeHttpHandler struct {
 	*BaseHandler
 
-	metricsClient *metrics.Clientset
-	clientset     *kubernetes.Clientset
+	clientset   kubernetes.Interface
+	nodeCache   *KubeNodeCache       // Serves the nodes of the Kubernetes cluster. Nil if we're spoofing the cluster.
+	fakeCluster *cluster.FakeCluster // If non-nil, then the nodes are the fake cluster's simulated hosts rather than those of Kubernetes.
 }
 
-func NewKubeNodeHttpHandler(opts *config.Configuration) *KubeNodeHttpHandler {
+// If fakeCluster is non-nil, then its simulated hosts are returned instead of the nodes of the Kubernetes cluster.
+func NewKubeNodeHttpHandler(opts *config.Configuration, fakeCluster *cluster.FakeCluster) *KubeNodeHttpHandler {
 	handler := &KubeNodeHttpHandler{
 		BaseHandler: NewBaseHandler(opts),
+		fakeCluster: fakeCluster,
 	}
-	handler.BackendHttpHandler = handler
+	handler.RegisterOp(domain.NodeOpRequest, handler.requestNodes)
+	handler.RegisterOp(domain.NodeOpWatch, handler.watchNodes)
+	handler.RegisterOp(domain.NodeOpCordon, handler.cordon(true))
+	handler.RegisterOp(domain.NodeOpUncordon, handler.cordon(false))
 
-	handler.Logger.Info("Creating server-side KubeNodeHttpHandler.")
+	handler.Logger.Info("Creating server-side KubeNodeHttpHandler.", zap.Bool("fake-cluster", fakeCluster != nil))
 
-	if opts.InCluster {
+	if fakeCluster != nil {
+		// There's no need to connect to Kubernetes.
+	} else if opts.InCluster {
 		// creates the in-cluster config
 		config, err := rest.InClusterConfig()
 		if err != nil {
@@ -59,7 +75,7 @@ func NewKubeNodeHttpHandler(opts *config.Configuration) *KubeNodeHttpHandler {
 		}
 
 		handler.clientset = clientset
-		handler.metricsClient = metricsClient
+		handler.nodeCache = newNodeCache(opts, clientset, metricsClient, handler.Logger)
 	} else {
 		// use the current context in kubeconfig
 		config, err := clientcmd.BuildConfigFromFlags("", opts.KubeConfig)
@@ -84,7 +100,11 @@ func NewKubeNodeHttpHandler(opts *config.Configuration) *KubeNodeHttpHandler {
 		}
 
 		handler.clientset = clientset
-		handler.metricsClient = metricsClient
+		handler.nodeCache = newNodeCache(opts, clientset, metricsClient, handler.Logger)
+	}
+
+	if handler.nodeCache != nil {
+		handler.nodeCache.Start(context.Background())
 	}
 
 	handler.Logger.Info("Successfully created server-side HTTP handler.")
@@ -92,134 +112,209 @@ func NewKubeNodeHttpHandler(opts *config.Configuration) *KubeNodeHttpHandler {
 	return handler
 }
 
-func (h *KubeNodeHttpHandler) HandleRequest(c *websocket.Conn, r *http.Request, payload map[string]interface{}) {
-	h.Logger.Info("Received payload from client.", zap.Any("payload", payload))
-
-	if payload["op"] != "request-nodes" {
-		h.Logger.Error("Unexpected operation requested from client.", zap.String("op", payload["op"].(string)))
-		h.WriteError(c, fmt.Sprintf("Unexpected operation: %s", payload["op"].(string)))
-		return
+// Create the cache of the nodes of the Kubernetes cluster, and of the pods scheduled on them.
+func newNodeCache(opts *config.Configuration, clientset kubernetes.Interface, metricsClient metrics.Interface, logger *zap.Logger) *KubeNodeCache {
+	metricsInterval, err := time.ParseDuration(opts.NodeQueryInterval)
+	if err != nil || metricsInterval <= 0 {
+		metricsInterval = defaultNodeWatchInterval
 	}
 
-	nodes, err := h.clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	nodeCache, err := NewKubeNodeCache(clientset, metricsClient, KubeNodeCacheOptions{
+		Namespaces:      opts.KubeNamespaces,
+		PodSelector:     opts.KubePodSelector,
+		NodeSelector:    opts.KubeNodeSelector,
+		MetricsInterval: metricsInterval,
+		GpuResource:     opts.KubeGpuResource,
+		VgpuResource:    opts.KubeVgpuResource,
+	}, logger)
 	if err != nil {
-		h.Logger.Error("Failed to retrieve nodes from Kubernetes.", zap.Error(err))
-		h.WriteError(c, "Failed to retrieve nodes from Kubernetes.")
-		return
+		panic(err)
 	}
 
-	nodeUsageMetrics, err := h.metricsClient.MetricsV1beta1().NodeMetricses().List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		h.Logger.Error("Failed to retrieve node metrics from Kubernetes.", zap.Error(err))
-		h.WriteError(c, "Failed to retrieve node metrics from Kubernetes.")
-		return
+	return nodeCache
+}
+
+func (h *KubeNodeHttpHandler) requestNodes(ctx context.Context, req *OpRequest) (interface{}, error) {
+	return h.listNodes(ctx)
+}
+
+// Return a handler that marks the requested node as unschedulable (if unschedulable is true) or as schedulable again.
+func (h *KubeNodeHttpHandler) cordon(unschedulable bool) OpHandler {
+	return func(ctx context.Context, req *OpRequest) (interface{}, error) {
+		var cordonReq domain.NodeCordonRequest
+		if err := req.Decode(&cordonReq); err != nil {
+			return nil, err
+		}
+
+		if cordonReq.NodeId == "" {
+			return nil, domain.NewResponseError(domain.ErrCodeBadRequest, "The \"%s\" operation requires the ID of a node.", req.Op)
+		}
+
+		if err := h.cordonNode(ctx, cordonReq.NodeId, unschedulable); err != nil {
+			return nil, err
+		}
+
+		return &domain.NodeCordonResponse{NodeId: cordonReq.NodeId, Unschedulable: unschedulable}, nil
 	}
+}
 
-	h.Logger.Info(fmt.Sprintf("Sending a list of %d nodes back to the client.", len(nodes.Items)), zap.Int("num-nodes", len(nodes.Items)))
+// Return the current nodes: the fake cluster's simulated hosts if we're spoofing the cluster, or else the nodes of the
+// Kubernetes cluster, as cached by the node cache.
+func (h *KubeNodeHttpHandler) listNodes(ctx context.Context) (map[string]*domain.KubernetesNode, error) {
+	// If we're spoofing the cluster, then just return the fake cluster's simulated hosts.
+	if h.fakeCluster != nil {
+		return h.fakeCluster.Nodes(), nil
+	}
 
-	var kubernetesNodes map[string]*domain.KubernetesNode = make(map[string]*domain.KubernetesNode, len(nodes.Items))
-	val := nodes.Items[0].Status.Capacity[corev1.ResourceCPU]
-	val.AsInt64()
-	for _, node := range nodes.Items {
-		allocatableCPU := node.Status.Capacity[corev1.ResourceCPU]
-		allocatableMemory := node.Status.Capacity[corev1.ResourceMemory]
+	syncCtx, cancel := context.WithTimeout(ctx, nodeCacheSyncTimeout)
+	defer cancel()
+	h.nodeCache.WaitForSync(syncCtx)
 
-		allocCpu := allocatableCPU.AsApproximateFloat64()
-		allocMem := allocatableMemory.AsApproximateFloat64()
+	nodes, err := h.nodeCache.Nodes()
+	if err != nil {
+		h.Logger.Error("Failed to retrieve nodes from the node cache.", zap.Error(err))
+		return nil, domain.NewResponseError(domain.ErrCodeUnavailable, "Failed to retrieve nodes from Kubernetes: %v", err)
+	}
 
-		// h.Logger.Info("Memory as inf.Dec.", zap.String("node-id", node.Name), zap.Any("mem inf.Dec", allocatableMemory.AsDec().String()))
+	h.Logger.Info(fmt.Sprintf("Sending a list of %d nodes back to the client.", len(nodes)), zap.Int("num-nodes", len(nodes)))
 
-		ctx, cancel := context.WithTimeout(r.Context(), time.Second*15)
-		defer cancel()
+	return nodes, nil
+}
 
-		pods, err := h.clientset.CoreV1().Pods("default").List(ctx, metav1.ListOptions{
-			FieldSelector: "spec.nodeName=" + node.Name,
-		})
+// Mark the node as unschedulable, or as schedulable again: the fake cluster's simulated host if we're spoofing the
+// cluster, or else the node of the Kubernetes cluster, by patching its spec as "kubectl cordon" does.
+func (h *KubeNodeHttpHandler) cordonNode(ctx context.Context, nodeId string, unschedulable bool) error {
+	h.Logger.Info("Setting whether node is schedulable.", zap.String("node", nodeId), zap.Bool("unschedulable", unschedulable))
 
-		if err != nil {
-			h.Logger.Error("Could not retrieve Pods running on node.", zap.String("node", node.Name), zap.Error(err))
+	if h.fakeCluster != nil {
+		err := h.fakeCluster.CordonHost(nodeId, unschedulable)
+		if errors.Is(err, cluster.ErrHostNotFound) {
+			return domain.NewResponseError(domain.ErrCodeNotFound, "%v", err)
 		}
 
-		var kubePods []*domain.KubernetesPod
-		if pods != nil {
-			kubePods = make([]*domain.KubernetesPod, 0, len(pods.Items))
+		return err
+	}
 
-			for _, pod := range pods.Items {
-				kubePod := &domain.KubernetesPod{
-					PodName:  pod.ObjectMeta.Name,
-					PodPhase: string(pod.Status.Phase),
-					PodIP:    pod.Status.PodIP,
-					PodAge:   time.Since(pod.GetCreationTimestamp().Time).Round(time.Second),
-				}
+	patch := []byte(fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable))
+	if _, err := h.clientset.CoreV1().Nodes().Patch(ctx, nodeId, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		h.Logger.Error("Failed to patch node.", zap.String("node", nodeId), zap.Bool("unschedulable", unschedulable), zap.Error(err))
 
-				kubePods = append(kubePods, kubePod)
-			}
+		code := domain.ErrCodeUnavailable
+		if apierrors.IsNotFound(err) {
+			code = domain.ErrCodeNotFound
 		}
 
-		sort.Slice(kubePods, func(i, j int) bool {
-			return kubePods[i].PodName < kubePods[j].PodName
-		})
+		return domain.NewResponseError(code, "Failed to update node %s: %v", nodeId, err)
+	}
 
-		kubernetesNode := domain.KubernetesNode{
-			NodeId:         node.Name,
-			CapacityCPU:    allocCpu,
-			CapacityMemory: allocMem / 976600.0, // Convert from Ki to GB.
-			Pods:           kubePods,
-			Age:            time.Since(node.GetCreationTimestamp().Time).Round(time.Second),
-			IP:             node.Status.Addresses[0].Address,
-			// CapacityGPUs:    0,
-			// CapacityVGPUs:   0,
-			// AllocatedCPU:    0,
-			// AllocatedMemory: 0,
-			// AllocatedGPUs:   0,
-			// AllocatedVGPUs:  0,
-		}
+	return nil
+}
 
-		kubernetesNodes[node.Name] = &kubernetesNode
+// Stream changes to the nodes to the client, one NodeEvent per intermediate response, until it disconnects. The nodes
+// are compared with those that were last sent whenever the fake cluster changes, if we're spoofing the cluster, or
+// else whenever the node cache changes.
+func (h *KubeNodeHttpHandler) watchNodes(ctx context.Context, req *OpRequest) (interface{}, error) {
+	var changes <-chan struct{}
+	var unsubscribe func()
+	if h.fakeCluster != nil {
+		changes, unsubscribe = h.fakeCluster.Changes()
+	} else {
+		changes, unsubscribe = h.nodeCache.Changes()
 	}
+	defer unsubscribe()
 
-	for _, nodeMetric := range nodeUsageMetrics.Items {
-		nodeName := nodeMetric.ObjectMeta.Name
-		kubeNode := kubernetesNodes[nodeName]
-		// h.Logger.Info("Node metric.", zap.String("node", nodeName), zap.Any("metric", nodeMetric))
+	sent := make(map[string]*domain.KubernetesNode)
 
-		cpu := nodeMetric.Usage.Cpu().AsApproximateFloat64()
-		// if !ok {
-		// 	h.Logger.Error("Could not convert CPU usage metric to Int64.", zap.Any("cpu-metric", nodeMetric.Usage.Cpu()))
-		// }
-		// h.Logger.Info("CPU metric.", zap.String("node-id", nodeName), zap.Float64("cpu", cpu))
+	// Send the events for the nodes that have changed since they were last sent, in order of ID.
+	sync := func() error {
+		nodes, err := h.listNodes(ctx)
+		if err != nil {
+			// The cache may not have synced yet, so we'll just try again next time.
+			return nil
+		}
 
-		mem := nodeMetric.Usage.Memory().AsApproximateFloat64()
-		// if !ok {
-		// 	h.Logger.Error("Could not convert 	memory usage metric to Int64.", zap.Any("mem-metric", nodeMetric.Usage.Memory()))
-		// }
-		// h.Logger.Info("Memory metric.", zap.String("node-id", nodeName), zap.Float64("memory", cpu))
+		ids := make([]string, 0, len(nodes)+len(sent))
+		for id := range nodes {
+			ids = append(ids, id)
+		}
+		for id := range sent {
+			if _, ok := nodes[id]; !ok {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			node, exists := nodes[id]
+			previous, wasSent := sent[id]
+
+			event := &domain.NodeEvent{NodeId: id, Node: node}
+			switch {
+			case exists && !wasSent:
+				event.Type = domain.ResourceAdded
+			case exists && !sameNode(node, previous):
+				event.Type = domain.ResourceUpdated
+			case !exists:
+				event.Type = domain.ResourceRemoved
+				event.Node = previous
+			default:
+				continue
+			}
+
+			if err := req.Send(event); err != nil {
+				return err
+			}
 
-		kubeNode.AllocatedCPU = cpu
-		kubeNode.AllocatedMemory = mem / 976600.0 // Convert from Ki to GB.
+			if exists {
+				sent[id] = node
+			} else {
+				delete(sent, id)
+			}
+		}
 
-		kubernetesNodes[nodeName] = kubeNode
+		return nil
 	}
 
-	// for _, node := range kubernetesNodes {
-	// 	h.Logger.Info("Kubernetes node.", zap.String(node.NodeId, node.String()))
-	// }
+	if err := sync(); err != nil {
+		h.Logger.Debug("Node watcher disconnected.", zap.Error(err))
+		return nil, err
+	}
 
-	data, err := json.Marshal(kubernetesNodes)
-	if err != nil {
-		h.Logger.Error("Failed to marshall nodes from Kubernetes to JSON.", zap.Error(err))
+	if err := req.Send(&domain.NodeEvent{Type: domain.ResourceSynced}); err != nil {
+		return nil, err
+	}
 
-		// Write error back to front-end.
-		h.WriteError(c, "Failed to marshall nodes to JSON.")
+	h.Logger.Info("Client is watching the nodes.", zap.Int("num-nodes", len(sent)))
 
-		return
+	for {
+		select {
+		case <-changes:
+		case <-ctx.Done():
+			h.Logger.Info("Client stopped watching the nodes.")
+			return nil, ctx.Err()
+		}
+
+		if err := sync(); err != nil {
+			h.Logger.Info("Node watcher disconnected.", zap.Error(err))
+			return nil, err
+		}
 	}
+}
 
-	h.Logger.Info("Sending nodes back to client now.")
-	err = c.Write(context.Background(), websocket.MessageBinary, data)
-	if err != nil {
-		h.Logger.Error("Error while writing node list back to front-end.", zap.Error(err))
-	} else {
-		h.Logger.Info("Successfully sent config back to client.")
+// Return true if the nodes are the same, ignoring their ages and those of their pods, which change constantly.
+func sameNode(a *domain.KubernetesNode, b *domain.KubernetesNode) bool {
+	withoutAges := func(node *domain.KubernetesNode) string {
+		copied := *node
+		copied.Age = 0
+		copied.Pods = make([]*domain.KubernetesPod, 0, len(node.Pods))
+		for _, pod := range node.Pods {
+			copiedPod := *pod
+			copiedPod.PodAge = 0
+			copied.Pods = append(copied.Pods, &copiedPod)
+		}
+
+		return copied.String()
 	}
+
+	return withoutAges(a) == withoutAges(b)
 }
@@ -2,84 +2,61 @@ package server
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"time"
 
 	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
 	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/jupyter"
 	"go.uber.org/zap"
-	"nhooyr.io/websocket"
 )
 
 const (
-	// Jupyter Server HTTP API endpoint for retrieving the Jupyter Server version.
-	versionSpecJupyterServerEndpoint = "/api"
-
-	// Jupyter Server HTTP API endpoint for retrieving the list of kernel specs.
-	kernelSpecJupyterServerEndpoint = "/api/kernelspecs"
+	// How long to wait for the Jupyter Server to respond, including any retries.
+	jupyterServerTimeout = time.Second * 30
 )
 
 type KernelSpecHttpHandler struct {
 	*BaseHandler
 
-	jupyterServerAddress string // IP of the Jupyter Server.
+	jupyterClient        *jupyter.Client
 	jupyterServerVersion string // We just obtain this when testing connectivity. It's not presently used for anything.
 }
 
 func NewKernelSpecHttpHandler(opts *config.Configuration) *KernelSpecHttpHandler {
 	handler := &KernelSpecHttpHandler{
-		BaseHandler:          NewBaseHandler(opts),
-		jupyterServerAddress: opts.JupyterServerAddress,
+		BaseHandler:   NewBaseHandler(opts),
+		jupyterClient: jupyter.NewClient(opts.JupyterServerAddress, &jupyter.ClientOptions{Token: opts.JupyterServerToken}),
 	}
-	handler.BackendHttpHandler = handler
+	handler.RegisterOp(domain.KernelSpecOpRequest, handler.requestKernelSpecs)
 
 	handler.Logger.Info(fmt.Sprintf("Creating server-side KernelSpecHttpHandler.\nOptions: %s", opts))
 
-	connectivity := handler.testJupyterServerConnectivity()
-	if !connectivity {
-		handler.Logger.Error("Cannot connect to the Jupyter server.", zap.String("jupyter-server-ip", handler.jupyterServerAddress))
-		panic("Could not connect to Jupyter server.")
+	// The spoofed kernel specs don't come from the Jupyter Server, so we only need it if we're not spoofing.
+	if !opts.SpoofCluster {
+		connectivity := handler.testJupyterServerConnectivity()
+		if !connectivity {
+			handler.Logger.Error("Cannot connect to the Jupyter server.", zap.String("jupyter-server-ip", opts.JupyterServerAddress))
+			panic("Could not connect to Jupyter server.")
+		}
 	}
 
 	return handler
 }
 
-func (h *KernelSpecHttpHandler) issueHttpRequest(target string) ([]byte, error) {
-	resp, err := http.Get(target)
-	if err != nil {
-		h.Logger.Error("Failed to complete HTTP GET request.", zap.Error(err), zap.String("URL", target))
-		return nil, err
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		h.Logger.Error("Failed to read response from HTTP GET request.", zap.Error(err), zap.String("URL", target))
-		return nil, err
-	}
-
-	return body, nil
-}
-
 func (h *KernelSpecHttpHandler) testJupyterServerConnectivity() bool {
-	target := h.jupyterServerAddress + versionSpecJupyterServerEndpoint
-	body, err := h.issueHttpRequest(target)
+	ctx, cancel := context.WithTimeout(context.Background(), jupyterServerTimeout)
+	defer cancel()
+
+	version, err := h.jupyterClient.Version(ctx)
 	if err != nil {
+		h.Logger.Error("Failed to read the Jupyter Server's version.", zap.Error(err), zap.String("jupyter-server-ip", h.jupyterClient.Address()))
 		return false
 	}
 
-	var version map[string]interface{}
-	json.Unmarshal(body, &version)
-
-	if versionString, ok := version["version"]; ok {
-		h.jupyterServerVersion = versionString.(string)
-		h.Logger.Debug("Successfully read Jupyter Server version. Connectivity established.", zap.String("version", h.jupyterServerVersion))
-		return true
-	} else {
-		h.Logger.Error("Unexpected response from HTTP GET request to Jupyter Server /api/ endpoint.", zap.Any("response", version), zap.String("URL", target))
-		return false
-	}
+	h.jupyterServerVersion = version
+	h.Logger.Debug("Successfully read Jupyter Server version. Connectivity established.", zap.String("version", h.jupyterServerVersion))
+	return true
 }
 
 func (h *KernelSpecHttpHandler) spoofKernelSpecs() []*domain.KernelSpec {
@@ -117,63 +94,36 @@ func (h *KernelSpecHttpHandler) spoofKernelSpecs() []*domain.KernelSpec {
 	return []*domain.KernelSpec{distributed_kernel, python3_kernel, ai_kernel}
 }
 
-// Retrieve the kernel specs by issuing an HTTP request to the Jupyter Server.
-func (h *KernelSpecHttpHandler) getKernelSpecsFromJupyter(c *websocket.Conn) []*domain.KernelSpec {
-	target := h.jupyterServerAddress + kernelSpecJupyterServerEndpoint
+// Retrieve the kernel specs from the Jupyter Server. Returns nil if they could not be retrieved.
+func (h *KernelSpecHttpHandler) getKernelSpecsFromJupyter(ctx context.Context) []*domain.KernelSpec {
+	ctx, cancel := context.WithTimeout(ctx, jupyterServerTimeout)
+	defer cancel()
 
-	body, err := h.issueHttpRequest(target)
+	kernelSpecs, defaultKernelSpec, err := h.jupyterClient.KernelSpecs(ctx)
 	if err != nil {
+		h.Logger.Error("Failed to retrieve kernel specs from Jupyter Server.", zap.Error(err))
 		return nil
 	}
 
-	var kernelSpecs map[string]interface{}
-	json.Unmarshal(body, &kernelSpecs)
-
-	h.Logger.Info("Retrieved kernel specs from Jupyter Server.", zap.Any("kernel-specs", kernelSpecs))
+	h.Logger.Info("Retrieved kernel specs from Jupyter Server.", zap.Int("num-kernel-specs", len(kernelSpecs)), zap.String("default", defaultKernelSpec))
 
-	return make([]*domain.KernelSpec, 0)
+	return kernelSpecs
 }
 
-func (h *KernelSpecHttpHandler) HandleRequest(c *websocket.Conn, r *http.Request, payload map[string]interface{}) {
-	h.Logger.Info("Received payload from client.", zap.Any("payload", payload))
-
-	if payload["op"] != "request-kernel-specs" {
-		h.Logger.Error(fmt.Sprintf("Unexpected operation requested from client: '%s'", payload["op"]), zap.String("op", payload["op"].(string)))
-		h.WriteError(c, fmt.Sprintf("Unexpected operation: %s", payload["op"].(string)))
-		return
-	}
-
-	var kernelSpecs []*domain.KernelSpec
-
+// Return the kernel specs: some made up ones if we're spoofing the cluster, or else those of the Jupyter Server.
+func (h *KernelSpecHttpHandler) requestKernelSpecs(ctx context.Context, req *OpRequest) (interface{}, error) {
 	// If we're spoofing the cluster, then just return some made up kernel specs for testing/debugging purposes.
 	if h.opts.SpoofCluster {
 		h.Logger.Info("Spoofing Jupyter kernel specs now.")
-		kernelSpecs = h.spoofKernelSpecs()
-	} else {
-		h.Logger.Info("Retrieving Jupyter kernel specs from the Jupyter Server now.", zap.String("jupyter-server-ip", h.jupyterServerAddress))
-		kernelSpecs = h.getKernelSpecsFromJupyter(c)
-
-		if kernelSpecs == nil {
-			// Write error back to front-end.
-			h.Logger.Error("Failed to retrieve list of kernel specs from Jupyter Server.")
-			h.WriteError(c, "Failed to retrieve list of kernel specs from Jupyter Server.")
-			return
-		}
+		return h.spoofKernelSpecs(), nil
 	}
 
-	data, err := json.Marshal(kernelSpecs)
-	if err != nil {
-		// Write error back to front-end.
-		h.Logger.Error("Failed to marshall kernel spec objects to JSON.", zap.Error(err))
-		h.WriteError(c, "Failed to marshall kernel spec objects to JSON.")
-		return
+	h.Logger.Info("Retrieving Jupyter kernel specs from the Jupyter Server now.", zap.String("jupyter-server-ip", h.jupyterClient.Address()))
+	kernelSpecs := h.getKernelSpecsFromJupyter(ctx)
+	if kernelSpecs == nil {
+		return nil, domain.NewResponseError(domain.ErrCodeUnavailable, "Failed to retrieve list of kernel specs from Jupyter Server.")
 	}
 
-	h.Logger.Info("Sending kernel specs back to client now.", zap.Any("kernel-specs", kernelSpecs))
-	err = c.Write(context.Background(), websocket.MessageBinary, data)
-	if err != nil {
-		h.Logger.Error("Error while writing kernel specs back to front-end.", zap.Error(err))
-	} else {
-		h.Logger.Info("Successfully sent kernel specs back to client.")
-	}
+	h.Logger.Info("Sending kernel specs back to client now.", zap.Int("num-kernel-specs", len(kernelSpecs)))
+	return kernelSpecs, nil
 }
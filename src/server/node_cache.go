@@ -0,0 +1,384 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+const (
+	// Name of the index of the pods by the node on which each is scheduled.
+	podNodeIndex = "node"
+
+	// How often the informers re-deliver every cached object to the event handlers, if the interval isn't specified.
+	defaultCacheResyncPeriod = time.Minute * 10
+)
+
+var ErrCacheNotSynced = errors.New("the node cache has not yet synced with Kubernetes")
+
+type KubeNodeCacheOptions struct {
+	Namespaces      []string      // Namespaces whose pods are cached. Empty means every namespace.
+	PodSelector     string        // Label selector restricting the pods that are cached.
+	NodeSelector    string        // Label selector restricting the nodes that are cached.
+	MetricsInterval time.Duration // How often the resource usage of the nodes is polled.
+	GpuResource     string        // Extended resource through which the nodes advertise their GPUs, e.g., "nvidia.com/gpu".
+	VgpuResource    string        // Extended resource through which the nodes advertise their vGPUs. Empty if there are none.
+	ResyncPeriod    time.Duration // How often the informers re-deliver every cached object. Defaults to ten minutes.
+}
+
+// Serves the nodes of the Kubernetes cluster, and the pods scheduled on them, from memory. The nodes and pods are kept
+// up to date by shared informers, so serving them costs no calls to the Kubernetes API. The resource usage of the
+// nodes cannot be watched, so it is polled every MetricsInterval.
+type KubeNodeCache struct {
+	logger        *zap.Logger
+	metricsClient metrics.Interface
+	opts          KubeNodeCacheOptions
+
+	nodeFactory  informers.SharedInformerFactory
+	nodeInformer cache.SharedIndexInformer
+	nodeLister   listersv1.NodeLister
+	podFactories []informers.SharedInformerFactory // One for each namespace.
+	podInformers []cache.SharedIndexInformer
+
+	mutex       sync.Mutex
+	usage       map[string]corev1.ResourceList // The most recent resource usage of each node, keyed by name.
+	subscribers map[chan struct{}]struct{}
+}
+
+// Create the cache. The informers aren't started until Start is called.
+func NewKubeNodeCache(clientset kubernetes.Interface, metricsClient metrics.Interface, opts KubeNodeCacheOptions, logger *zap.Logger) (*KubeNodeCache, error) {
+	if _, err := labels.Parse(opts.PodSelector); err != nil {
+		return nil, err
+	}
+
+	if _, err := labels.Parse(opts.NodeSelector); err != nil {
+		return nil, err
+	}
+
+	if opts.ResyncPeriod <= 0 {
+		opts.ResyncPeriod = defaultCacheResyncPeriod
+	}
+
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	c := &KubeNodeCache{
+		logger:        logger,
+		metricsClient: metricsClient,
+		opts:          opts,
+		usage:         make(map[string]corev1.ResourceList),
+		subscribers:   make(map[chan struct{}]struct{}),
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.notifyChanged() },
+		UpdateFunc: func(oldObj, newObj interface{}) { c.notifyChanged() },
+		DeleteFunc: func(obj interface{}) { c.notifyChanged() },
+	}
+
+	c.nodeFactory = informers.NewSharedInformerFactoryWithOptions(clientset, opts.ResyncPeriod, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = opts.NodeSelector
+	}))
+	c.nodeInformer = c.nodeFactory.Core().V1().Nodes().Informer()
+	c.nodeLister = c.nodeFactory.Core().V1().Nodes().Lister()
+	if _, err := c.nodeInformer.AddEventHandler(handler); err != nil {
+		return nil, err
+	}
+
+	for _, namespace := range namespaces {
+		factory := informers.NewSharedInformerFactoryWithOptions(clientset, opts.ResyncPeriod, informers.WithNamespace(namespace), informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = opts.PodSelector
+		}))
+
+		informer := factory.Core().V1().Pods().Informer()
+		if err := informer.AddIndexers(cache.Indexers{podNodeIndex: podNodeName}); err != nil {
+			return nil, err
+		}
+
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return nil, err
+		}
+
+		c.podFactories = append(c.podFactories, factory)
+		c.podInformers = append(c.podInformers, informer)
+	}
+
+	return c, nil
+}
+
+// Index the pods by the node on which they're scheduled. Pods that haven't been scheduled yet aren't indexed.
+func podNodeName(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+
+	return []string{pod.Spec.NodeName}, nil
+}
+
+// Start the informers, and begin polling the resource usage of the nodes, until the context is cancelled.
+// Returns immediately; until the informers have synced, Nodes returns ErrCacheNotSynced.
+func (c *KubeNodeCache) Start(ctx context.Context) {
+	c.logger.Info("Starting node cache.", zap.Strings("namespaces", c.opts.Namespaces), zap.String("pod-selector", c.opts.PodSelector), zap.String("node-selector", c.opts.NodeSelector))
+
+	c.nodeFactory.Start(ctx.Done())
+	for _, factory := range c.podFactories {
+		factory.Start(ctx.Done())
+	}
+
+	go c.pollMetrics(ctx)
+}
+
+// Return true if every informer has synced.
+func (c *KubeNodeCache) HasSynced() bool {
+	if !c.nodeInformer.HasSynced() {
+		return false
+	}
+
+	for _, informer := range c.podInformers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Block until every informer has synced, or until the context is done. Returns true if the informers have synced.
+func (c *KubeNodeCache) WaitForSync(ctx context.Context) bool {
+	synced := []cache.InformerSynced{c.nodeInformer.HasSynced}
+	for _, informer := range c.podInformers {
+		synced = append(synced, informer.HasSynced)
+	}
+
+	return cache.WaitForCacheSync(ctx.Done(), synced...)
+}
+
+// Poll the resource usage of the nodes every MetricsInterval until the context is cancelled.
+func (c *KubeNodeCache) pollMetrics(ctx context.Context) {
+	interval := c.opts.MetricsInterval
+	if interval <= 0 {
+		interval = defaultNodeWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.refreshMetrics(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Fetch the resource usage of the nodes, and notify the subscribers if it changed. If the metrics cannot be fetched,
+// then the previous usage is kept.
+func (c *KubeNodeCache) refreshMetrics(ctx context.Context) {
+	nodeMetrics, err := c.metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{LabelSelector: c.opts.NodeSelector})
+	if err != nil {
+		c.logger.Error("Failed to retrieve node metrics from Kubernetes.", zap.Error(err))
+		return
+	}
+
+	usage := make(map[string]corev1.ResourceList, len(nodeMetrics.Items))
+	for _, nodeMetric := range nodeMetrics.Items {
+		usage[nodeMetric.Name] = nodeMetric.Usage
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if equality.Semantic.DeepEqual(usage, c.usage) {
+		return
+	}
+
+	c.usage = usage
+	c.notifyChangedLocked()
+}
+
+// Return the cached nodes, along with their pods and most recent resource usage, keyed by name.
+func (c *KubeNodeCache) Nodes() (map[string]*domain.KubernetesNode, error) {
+	if !c.HasSynced() {
+		return nil, ErrCacheNotSynced
+	}
+
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	usage := c.usage
+	c.mutex.Unlock()
+
+	kubernetesNodes := make(map[string]*domain.KubernetesNode, len(nodes))
+	for _, node := range nodes {
+		capacityCPU := node.Status.Capacity[corev1.ResourceCPU]
+		capacityMemory := node.Status.Capacity[corev1.ResourceMemory]
+		pods := c.podsOn(node.Name)
+
+		kubernetesNode := &domain.KubernetesNode{
+			NodeId:         node.Name,
+			CapacityCPU:    capacityCPU.AsApproximateFloat64(),
+			CapacityMemory: capacityMemory.AsApproximateFloat64() / 976600.0, // Convert from Ki to GB.
+			Pods:           make([]*domain.KubernetesPod, 0, len(pods)),
+			Age:            time.Since(node.GetCreationTimestamp().Time).Round(time.Second),
+			Unschedulable:  node.Spec.Unschedulable,
+			Valid:          true,
+		}
+
+		// The GPUs and vGPUs cannot be measured like CPU and memory, so their allocation is what the pods have requested.
+		if c.opts.GpuResource != "" {
+			kubernetesNode.CapacityGPUs = extendedResource(node.Status.Capacity, c.opts.GpuResource)
+			kubernetesNode.AllocatedGPUs = requested(pods, c.opts.GpuResource)
+		}
+
+		if c.opts.VgpuResource != "" {
+			kubernetesNode.CapacityVGPUs = extendedResource(node.Status.Capacity, c.opts.VgpuResource)
+			kubernetesNode.AllocatedVGPUs = requested(pods, c.opts.VgpuResource)
+		}
+
+		for _, pod := range pods {
+			kubernetesNode.Pods = append(kubernetesNode.Pods, &domain.KubernetesPod{
+				PodName:  pod.Name,
+				PodPhase: string(pod.Status.Phase),
+				PodIP:    pod.Status.PodIP,
+				PodAge:   time.Since(pod.GetCreationTimestamp().Time).Round(time.Second),
+				Valid:    true,
+			})
+		}
+
+		if len(node.Status.Addresses) > 0 {
+			kubernetesNode.IP = node.Status.Addresses[0].Address
+		}
+
+		if nodeUsage, ok := usage[node.Name]; ok {
+			kubernetesNode.AllocatedCPU = nodeUsage.Cpu().AsApproximateFloat64()
+			kubernetesNode.AllocatedMemory = nodeUsage.Memory().AsApproximateFloat64() / 976600.0 // Convert from Ki to GB.
+		}
+
+		kubernetesNodes[node.Name] = kubernetesNode
+	}
+
+	return kubernetesNodes, nil
+}
+
+// Return the cached pods scheduled on the node, sorted by name.
+func (c *KubeNodeCache) podsOn(nodeName string) []*corev1.Pod {
+	pods := make([]*corev1.Pod, 0)
+	for _, informer := range c.podInformers {
+		objs, err := informer.GetIndexer().ByIndex(podNodeIndex, nodeName)
+		if err != nil {
+			c.logger.Error("Could not retrieve Pods running on node.", zap.String("node", nodeName), zap.Error(err))
+			continue
+		}
+
+		for _, obj := range objs {
+			pods = append(pods, obj.(*corev1.Pod))
+		}
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	return pods
+}
+
+// Return the quantity of the extended resource, e.g., the number of GPUs, or zero if there is none.
+func extendedResource(resources corev1.ResourceList, name string) float64 {
+	quantity, ok := resources[corev1.ResourceName(name)]
+	if !ok {
+		return 0
+	}
+
+	return quantity.AsApproximateFloat64()
+}
+
+// Return the total quantity of the resource requested by the pods that haven't terminated. As the scheduler does, a
+// pod's request is the greater of the sum of its containers' requests and the largest of its init containers'
+// requests, as the init containers run one at a time before the other containers. Extended resources cannot be
+// overcommitted, so a container that specifies only a limit requests that limit.
+func requested(pods []*corev1.Pod, name string) float64 {
+	containerRequest := func(container corev1.Container) float64 {
+		if quantity, ok := container.Resources.Requests[corev1.ResourceName(name)]; ok {
+			return quantity.AsApproximateFloat64()
+		}
+
+		return extendedResource(container.Resources.Limits, name)
+	}
+
+	total := 0.0
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		podRequest := 0.0
+		for _, container := range pod.Spec.Containers {
+			podRequest += containerRequest(container)
+		}
+
+		for _, container := range pod.Spec.InitContainers {
+			podRequest = max(podRequest, containerRequest(container))
+		}
+
+		total += podRequest
+	}
+
+	return total
+}
+
+// Return a channel that receives a value whenever the cached nodes, pods, or resource usage change, and a function
+// that unsubscribes. Notifications are coalesced: a burst of changes may produce a single notification.
+func (c *KubeNodeCache) Changes() (<-chan struct{}, func()) {
+	changes := make(chan struct{}, 1)
+
+	c.mutex.Lock()
+	c.subscribers[changes] = struct{}{}
+	c.mutex.Unlock()
+
+	return changes, func() {
+		c.mutex.Lock()
+		delete(c.subscribers, changes)
+		c.mutex.Unlock()
+	}
+}
+
+func (c *KubeNodeCache) notifyChanged() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.notifyChangedLocked()
+}
+
+// Notify the subscribers that the cache has changed. Must be called with the mutex held.
+func (c *KubeNodeCache) notifyChangedLocked() {
+	for changes := range c.subscribers {
+		select {
+		case changes <- struct{}{}:
+		default:
+			// The subscriber already has a pending notification.
+		}
+	}
+}
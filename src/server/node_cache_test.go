@@ -0,0 +1,387 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+const (
+	testGpuResource  = "nvidia.com/gpu"
+	testVgpuResource = "example.com/vgpu"
+)
+
+func testNode(name string, labels map[string]string, capacity corev1.ResourceList) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Capacity:  capacity,
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.0.1"}},
+		},
+	}
+}
+
+func testPod(namespace, name, nodeName string, phase corev1.PodPhase, labels map[string]string, containers ...corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: nodeName, Containers: containers},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+// A container that requests and limits the given quantities of resources, which are keyed by name.
+func testContainer(requests map[string]string, limits map[string]string) corev1.Container {
+	toList := func(quantities map[string]string) corev1.ResourceList {
+		list := make(corev1.ResourceList, len(quantities))
+		for name, quantity := range quantities {
+			list[corev1.ResourceName(name)] = resource.MustParse(quantity)
+		}
+		return list
+	}
+
+	return corev1.Container{
+		Name:      "container",
+		Resources: corev1.ResourceRequirements{Requests: toList(requests), Limits: toList(limits)},
+	}
+}
+
+// Create and start a cache of the given objects, and wait for it to sync.
+func startTestNodeCache(t *testing.T, opts KubeNodeCacheOptions, metrics []runtime.Object, objects ...runtime.Object) (*KubeNodeCache, *fake.Clientset) {
+	t.Helper()
+
+	clientset := fake.NewSimpleClientset(objects...)
+	metricsClient := metricsfake.NewSimpleClientset()
+	for _, obj := range metrics {
+		if err := metricsClient.Tracker().Create(metricsv1beta1.SchemeGroupVersion.WithResource("nodes"), obj, ""); err != nil {
+			t.Fatalf("Failed to add node metrics: %v", err)
+		}
+	}
+
+	nodeCache, err := NewKubeNodeCache(clientset, metricsClient, opts, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Failed to create node cache: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	nodeCache.Start(ctx)
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, time.Second*10)
+	defer syncCancel()
+	if !nodeCache.WaitForSync(syncCtx) {
+		t.Fatal("Node cache did not sync.")
+	}
+
+	return nodeCache, clientset
+}
+
+// Wait until the condition holds, failing the test if it doesn't within a few seconds.
+func eventually(t *testing.T, description string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second * 5)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting until %s.", description)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+func TestKubeNodeCacheNotSynced(t *testing.T) {
+	nodeCache, err := NewKubeNodeCache(fake.NewSimpleClientset(), metricsfake.NewSimpleClientset(), KubeNodeCacheOptions{}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Failed to create node cache: %v", err)
+	}
+
+	if _, err := nodeCache.Nodes(); !errors.Is(err, ErrCacheNotSynced) {
+		t.Errorf("Nodes returned %v before the cache was started, expected %v", err, ErrCacheNotSynced)
+	}
+}
+
+func TestKubeNodeCacheInvalidSelector(t *testing.T) {
+	if _, err := NewKubeNodeCache(fake.NewSimpleClientset(), metricsfake.NewSimpleClientset(), KubeNodeCacheOptions{PodSelector: "app in ("}, zap.NewNop()); err == nil {
+		t.Error("Expected an invalid pod selector to be rejected.")
+	}
+
+	if _, err := NewKubeNodeCache(fake.NewSimpleClientset(), metricsfake.NewSimpleClientset(), KubeNodeCacheOptions{NodeSelector: "role in ("}, zap.NewNop()); err == nil {
+		t.Error("Expected an invalid node selector to be rejected.")
+	}
+}
+
+func TestKubeNodeCachePopulates(t *testing.T) {
+	kernelLabels := map[string]string{"app": "kernel"}
+
+	objects := []runtime.Object{
+		testNode("node-a", map[string]string{"role": "worker"}, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("8"),
+			corev1.ResourceMemory: resource.MustParse("32Gi"),
+			testGpuResource:       resource.MustParse("4"),
+		}),
+		testNode("node-b", map[string]string{"role": "worker"}, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+		testNode("control-plane", map[string]string{"role": "control-plane"}, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}),
+
+		testPod("kernels", "kernel-2", "node-a", corev1.PodRunning, kernelLabels, testContainer(map[string]string{testGpuResource: "1"}, nil)),
+		testPod("kernels", "kernel-1", "node-a", corev1.PodRunning, kernelLabels, testContainer(map[string]string{testGpuResource: "2"}, nil)),
+		testPod("kernels", "kernel-pending", "", corev1.PodPending, kernelLabels),
+		testPod("kernels", "other", "node-a", corev1.PodRunning, map[string]string{"app": "other"}),
+		testPod("default", "kernel-elsewhere", "node-a", corev1.PodRunning, kernelLabels),
+	}
+
+	metrics := []runtime.Object{
+		&metricsv1beta1.NodeMetrics{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"role": "worker"}},
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1500m"),
+				corev1.ResourceMemory: resource.MustParse("1953200"),
+			},
+		},
+	}
+
+	nodeCache, _ := startTestNodeCache(t, KubeNodeCacheOptions{
+		Namespaces:      []string{"kernels"},
+		PodSelector:     "app=kernel",
+		NodeSelector:    "role=worker",
+		MetricsInterval: time.Millisecond * 10,
+		GpuResource:     testGpuResource,
+	}, metrics, objects...)
+
+	eventually(t, "the metrics have been polled", func() bool {
+		nodes, err := nodeCache.Nodes()
+		return err == nil && nodes["node-a"] != nil && nodes["node-a"].AllocatedCPU > 0
+	})
+
+	nodes, err := nodeCache.Nodes()
+	if err != nil {
+		t.Fatalf("Nodes returned an error: %v", err)
+	}
+
+	if len(nodes) != 2 || nodes["node-a"] == nil || nodes["node-b"] == nil {
+		t.Fatalf("Nodes returned %v, expected only the worker nodes", nodes)
+	}
+
+	nodeA := nodes["node-a"]
+	if nodeA.CapacityCPU != 8 {
+		t.Errorf("Node has %v CPUs, expected 8", nodeA.CapacityCPU)
+	}
+	if nodeA.CapacityGPUs != 4 {
+		t.Errorf("Node has %v GPUs, expected 4", nodeA.CapacityGPUs)
+	}
+	if nodeA.AllocatedGPUs != 3 {
+		t.Errorf("Node has %v GPUs allocated, expected 3", nodeA.AllocatedGPUs)
+	}
+	if nodeA.AllocatedCPU != 1.5 {
+		t.Errorf("Node has %v CPUs allocated, expected 1.5", nodeA.AllocatedCPU)
+	}
+	if nodeA.AllocatedMemory != 2 {
+		t.Errorf("Node has %v GB of memory allocated, expected 2", nodeA.AllocatedMemory)
+	}
+	if nodeA.IP != "10.0.0.1" {
+		t.Errorf("Node has IP %q, expected 10.0.0.1", nodeA.IP)
+	}
+
+	// Only the kernel pods in the kernels namespace are cached, sorted by name.
+	if len(nodeA.Pods) != 2 || nodeA.Pods[0].PodName != "kernel-1" || nodeA.Pods[1].PodName != "kernel-2" {
+		t.Errorf("Node has pods %v, expected kernel-1 and kernel-2", nodeA.Pods)
+	}
+
+	if nodeB := nodes["node-b"]; len(nodeB.Pods) != 0 || nodeB.AllocatedCPU != 0 {
+		t.Errorf("Node %s has %d pods and %v CPUs allocated, expected none", nodeB.NodeId, len(nodeB.Pods), nodeB.AllocatedCPU)
+	}
+}
+
+func TestKubeNodeCacheChanges(t *testing.T) {
+	nodeCache, clientset := startTestNodeCache(t, KubeNodeCacheOptions{MetricsInterval: time.Hour}, nil,
+		testNode("node-a", nil, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}))
+
+	changes, unsubscribe := nodeCache.Changes()
+	defer unsubscribe()
+
+	// Wait for a change notification, and then until the change is visible in the cache.
+	expectChange := func(description string, check func(nodes map[string]*domain.KubernetesNode) bool) {
+		t.Helper()
+
+		select {
+		case <-changes:
+		case <-time.After(time.Second * 5):
+			t.Fatalf("No change was published after %s.", description)
+		}
+
+		eventually(t, description+" is visible", func() bool {
+			nodes, err := nodeCache.Nodes()
+			return err == nil && check(nodes)
+		})
+	}
+
+	// Drain the notification of the initial sync, if any.
+	select {
+	case <-changes:
+	default:
+	}
+
+	ctx := context.Background()
+
+	if _, err := clientset.CoreV1().Nodes().Create(ctx, testNode("node-b", nil, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	expectChange("adding a node", func(nodes map[string]*domain.KubernetesNode) bool {
+		return nodes["node-b"] != nil
+	})
+
+	updated := testNode("node-a", nil, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")})
+	updated.Spec.Unschedulable = true
+	if _, err := clientset.CoreV1().Nodes().Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update node: %v", err)
+	}
+	expectChange("cordoning a node", func(nodes map[string]*domain.KubernetesNode) bool {
+		return nodes["node-a"] != nil && nodes["node-a"].Unschedulable
+	})
+
+	if _, err := clientset.CoreV1().Pods("default").Create(ctx, testPod("default", "kernel", "node-b", corev1.PodRunning, nil), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create pod: %v", err)
+	}
+	expectChange("scheduling a pod", func(nodes map[string]*domain.KubernetesNode) bool {
+		return nodes["node-b"] != nil && len(nodes["node-b"].Pods) == 1
+	})
+
+	if err := clientset.CoreV1().Nodes().Delete(ctx, "node-a", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Failed to delete node: %v", err)
+	}
+	expectChange("deleting a node", func(nodes map[string]*domain.KubernetesNode) bool {
+		return nodes["node-a"] == nil
+	})
+
+	// Once unsubscribed, no more changes are published.
+	unsubscribe()
+	if err := clientset.CoreV1().Nodes().Delete(ctx, "node-b", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Failed to delete node: %v", err)
+	}
+	eventually(t, "the deleted node is gone", func() bool {
+		nodes, err := nodeCache.Nodes()
+		return err == nil && len(nodes) == 0
+	})
+
+	select {
+	case <-changes:
+		t.Error("A change was published after unsubscribing.")
+	default:
+	}
+}
+
+func TestRequested(t *testing.T) {
+	request := func(quantity string) map[string]string { return map[string]string{testGpuResource: quantity} }
+	withInitContainers := func(pod *corev1.Pod, containers ...corev1.Container) *corev1.Pod {
+		pod.Spec.InitContainers = containers
+		return pod
+	}
+
+	tests := []struct {
+		name     string
+		pods     []*corev1.Pod
+		expected float64
+	}{
+		{
+			name:     "no pods",
+			expected: 0,
+		},
+		{
+			name: "sum of containers and pods",
+			pods: []*corev1.Pod{
+				testPod("default", "a", "node", corev1.PodRunning, nil, testContainer(request("1"), nil), testContainer(request("2"), nil)),
+				testPod("default", "b", "node", corev1.PodPending, nil, testContainer(request("3"), nil)),
+			},
+			expected: 6,
+		},
+		{
+			name: "terminated pods are skipped",
+			pods: []*corev1.Pod{
+				testPod("default", "running", "node", corev1.PodRunning, nil, testContainer(request("1"), nil)),
+				testPod("default", "succeeded", "node", corev1.PodSucceeded, nil, testContainer(request("2"), nil)),
+				testPod("default", "failed", "node", corev1.PodFailed, nil, testContainer(request("4"), nil)),
+			},
+			expected: 1,
+		},
+		{
+			name: "limit used when there is no request",
+			pods: []*corev1.Pod{
+				testPod("default", "a", "node", corev1.PodRunning, nil, testContainer(nil, request("2"))),
+			},
+			expected: 2,
+		},
+		{
+			name: "request preferred over limit",
+			pods: []*corev1.Pod{
+				testPod("default", "a", "node", corev1.PodRunning, nil, testContainer(request("1"), request("2"))),
+			},
+			expected: 1,
+		},
+		{
+			name: "other resources ignored",
+			pods: []*corev1.Pod{
+				testPod("default", "a", "node", corev1.PodRunning, nil, testContainer(map[string]string{testVgpuResource: "3", "cpu": "2"}, nil)),
+			},
+			expected: 0,
+		},
+		{
+			name: "init container larger than containers",
+			pods: []*corev1.Pod{
+				withInitContainers(testPod("default", "a", "node", corev1.PodRunning, nil, testContainer(request("1"), nil), testContainer(request("1"), nil)),
+					testContainer(request("3"), nil), testContainer(request("1"), nil)),
+			},
+			expected: 3,
+		},
+		{
+			name: "init container smaller than containers",
+			pods: []*corev1.Pod{
+				withInitContainers(testPod("default", "a", "node", corev1.PodRunning, nil, testContainer(request("2"), nil), testContainer(request("2"), nil)),
+					testContainer(nil, request("3"))),
+			},
+			expected: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if total := requested(test.pods, testGpuResource); total != test.expected {
+				t.Errorf("requested returned %v, expected %v", total, test.expected)
+			}
+		})
+	}
+}
+
+func TestKubeNodeCacheVgpus(t *testing.T) {
+	nodeCache, _ := startTestNodeCache(t, KubeNodeCacheOptions{MetricsInterval: time.Hour, GpuResource: testGpuResource, VgpuResource: testVgpuResource}, nil,
+		testNode("node", nil, corev1.ResourceList{testGpuResource: resource.MustParse("2"), testVgpuResource: resource.MustParse("16")}),
+		testPod("default", "a", "node", corev1.PodRunning, nil, testContainer(nil, map[string]string{testVgpuResource: "4"})),
+		testPod("default", "b", "node", corev1.PodRunning, nil, testContainer(map[string]string{testVgpuResource: "2", testGpuResource: "1"}, nil)),
+	)
+
+	nodes, err := nodeCache.Nodes()
+	if err != nil {
+		t.Fatalf("Nodes returned an error: %v", err)
+	}
+
+	node := nodes["node"]
+	if node == nil {
+		t.Fatalf("Nodes returned %v, expected the node", nodes)
+	}
+
+	if node.CapacityGPUs != 2 || node.AllocatedGPUs != 1 {
+		t.Errorf("Node has %v/%v GPUs allocated, expected 1/2", node.AllocatedGPUs, node.CapacityGPUs)
+	}
+	if node.CapacityVGPUs != 16 || node.AllocatedVGPUs != 6 {
+		t.Errorf("Node has %v/%v vGPUs allocated, expected 6/16", node.AllocatedVGPUs, node.CapacityVGPUs)
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/config"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/driver"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+	"go.uber.org/zap"
+)
+
+// Lets the frontend start, pause, resume, stop, and query the status of a run of the workload passed to the backend via --workload.
+type WorkloadHttpHandler struct {
+	*BaseHandler
+
+	manager *driver.WorkloadManager
+}
+
+func NewWorkloadHttpHandler(opts *config.Configuration) *WorkloadHttpHandler {
+	handler := &WorkloadHttpHandler{
+		BaseHandler: NewBaseHandler(opts),
+	}
+	recorder := metrics.NewRecorder()
+
+	// When spoofing the cluster, the run's kernels are created and terminated via the fake Cluster Gateway.
+	var kernels domain.KernelManager
+	if opts.SpoofCluster {
+		kernelManager, err := driver.ConnectGatewayKernelManager(opts, recorder)
+		if err != nil {
+			handler.Logger.Error("Failed to connect to the fake Cluster Gateway. Workload runs will fail to create their sessions.", zap.String("gateway-address", opts.GatewayAddress), zap.Error(err))
+		} else {
+			kernels = kernelManager
+		}
+	}
+
+	handler.manager = driver.NewWorkloadManager(opts, kernels, driver.NewLoggerErrorHandler(handler.Logger), recorder)
+
+	handler.RegisterOp(domain.WorkloadOpRequest, handler.control(func() error { return nil }))
+	handler.RegisterOp(domain.WorkloadOpStart, handler.control(func() error { return handler.manager.Start(opts.Workload) }))
+	handler.RegisterOp(domain.WorkloadOpPause, handler.control(handler.manager.Pause))
+	handler.RegisterOp(domain.WorkloadOpResume, handler.control(handler.manager.Resume))
+	// Don't block the request while the run's sessions are torn down.
+	handler.RegisterOp(domain.WorkloadOpStop, handler.control(handler.manager.Abort))
+
+	handler.Logger.Info("Creating server-side WorkloadHttpHandler.", zap.Bool("workload-configured", opts.Workload != nil))
+
+	return handler
+}
+
+// Return the status of the current run. If no run has been started, then a pending run describing the configured workload is returned.
+func (h *WorkloadHttpHandler) currentRun() *domain.WorkloadRun {
+	if run := h.manager.Run(); run != nil {
+		return run
+	}
+
+	return &domain.WorkloadRun{
+		Workload:    h.opts.Workload.Name,
+		State:       domain.WorkloadRunPending,
+		NumSessions: h.opts.Workload.NumSessions(),
+		Valid:       true,
+	}
+}
+
+// Return a handler that performs the action on the run, and then responds with the resulting status of the run.
+func (h *WorkloadHttpHandler) control(action func() error) OpHandler {
+	return func(ctx context.Context, req *OpRequest) (interface{}, error) {
+		if h.opts.Workload == nil {
+			h.Logger.Warn("Received workload operation, but no workload was configured.", zap.String("op", req.Op))
+			return nil, domain.NewResponseError(domain.ErrCodeFailedPrecondition, "No workload has been configured. Restart the backend with --workload.")
+		}
+
+		if err := action(); err != nil {
+			return nil, domain.NewResponseError(domain.ErrCodeFailedPrecondition, "Failed to perform operation \"%s\": %v", req.Op, err)
+		}
+
+		run := h.currentRun()
+		h.Logger.Debug("Sending workload run back to client.", zap.String("state", string(run.State)))
+		return run, nil
+	}
+}
@@ -0,0 +1,101 @@
+package simulator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/cluster"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+// Summarizes how the simulated cluster coped with a workload, e.g., to compare placement policies.
+type Report struct {
+	Policy      cluster.PlacementPolicy `json:"policy"`
+	NumHosts    int                     `json:"num_hosts"`
+	NumReplicas int                     `json:"num_replicas"`
+
+	Makespan  time.Duration `json:"makespan"` // Virtual time from the start of the workload until its last session was torn down.
+	WallTime  time.Duration `json:"-"`        // Real time taken to run the simulation. Omitted from the JSON so that reports are reproducible.
+	NumEvents int           `json:"num_events"`
+
+	Sessions       int `json:"sessions"` // Number of sessions that were created.
+	CellsCompleted int `json:"cells_completed"`
+	NumErrors      int `json:"num_errors"`
+	PeakKernels    int `json:"peak_kernels"` // Largest number of kernels that existed at once.
+
+	Migrations       int `json:"migrations"` // Replicas migrated so that a cell could execute.
+	FailedMigrations int `json:"failed_migrations"`
+
+	PlacementWaits   int           `json:"placement_waits"` // Sessions whose kernels had to wait for room on the hosts.
+	PlacementWaitP50 time.Duration `json:"placement_wait_p50"`
+	PlacementWaitMax time.Duration `json:"placement_wait_max"`
+
+	CellWaits   int           `json:"cell_waits"` // Cells that couldn't execute as soon as they were submitted.
+	CellWaitP50 time.Duration `json:"cell_wait_p50"`
+	CellWaitP99 time.Duration `json:"cell_wait_p99"` // Time from submitting a cell until it began executing, over every cell.
+	CellWaitMax time.Duration `json:"cell_wait_max"`
+
+	placementWaits []time.Duration
+	cellWaits      []time.Duration
+}
+
+// Fill in the report once the simulation has finished.
+func (r *Report) finish(now time.Duration, run *domain.WorkloadRun) {
+	r.Makespan = now
+	r.CellsCompleted = run.CellsCompleted
+	r.NumErrors = run.NumErrors
+	r.Sessions = run.SessionsCompleted
+
+	sortDurations(r.placementWaits)
+	r.PlacementWaits = len(r.placementWaits)
+	r.PlacementWaitP50 = percentile(r.placementWaits, 50)
+	r.PlacementWaitMax = percentile(r.placementWaits, 100)
+
+	sortDurations(r.cellWaits)
+	for _, wait := range r.cellWaits {
+		if wait > 0 {
+			r.CellWaits++
+		}
+	}
+	r.CellWaitP50 = percentile(r.cellWaits, 50)
+	r.CellWaitP99 = percentile(r.cellWaits, 99)
+	r.CellWaitMax = percentile(r.cellWaits, 100)
+}
+
+func sortDurations(durations []time.Duration) {
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+}
+
+// Return the given percentile of the sorted durations, or zero if there are none.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+func (r *Report) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Policy\t%s\n", r.Policy)
+	fmt.Fprintf(w, "Hosts\t%d\n", r.NumHosts)
+	fmt.Fprintf(w, "Replicas per kernel\t%d\n", r.NumReplicas)
+	fmt.Fprintf(w, "Makespan\t%v (simulated in %v)\n", r.Makespan, r.WallTime.Round(time.Millisecond))
+	fmt.Fprintf(w, "Events\t%d\n", r.NumEvents)
+	fmt.Fprintf(w, "Sessions\t%d (peak kernels: %d)\n", r.Sessions, r.PeakKernels)
+	fmt.Fprintf(w, "Cells completed\t%d\n", r.CellsCompleted)
+	fmt.Fprintf(w, "Errors\t%d\n", r.NumErrors)
+	fmt.Fprintf(w, "Migrations\t%d (failed: %d)\n", r.Migrations, r.FailedMigrations)
+	fmt.Fprintf(w, "Placement waits\t%d (p50: %v, max: %v)\n", r.PlacementWaits, r.PlacementWaitP50, r.PlacementWaitMax)
+	fmt.Fprintf(w, "Cell waits\t%d (p50: %v, p99: %v, max: %v)\n", r.CellWaits, r.CellWaitP50, r.CellWaitP99, r.CellWaitMax)
+
+	w.Flush()
+	return buf.String()
+}
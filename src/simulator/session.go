@@ -0,0 +1,360 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/arrival"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/cluster"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+)
+
+type sessionState int
+
+const (
+	sessionArriving sessionState = iota // The session hasn't arrived yet.
+	sessionCreating                     // The session's kernel is being placed and started.
+	sessionLive                         // The session's kernel is running.
+	sessionStopped                      // The session has been torn down, or could not be created.
+)
+
+// A cell to be executed by a simulated session.
+type cell struct {
+	gpus      int32         // GPUs (in 1/100 GPU) that must be committed to one of the kernel's replicas for the cell to execute.
+	duration  time.Duration // How long the cell executes for.
+	thinkTime time.Duration // How long the session waits after the cell completes.
+	start     time.Duration // When replaying a trace, when the cell was submitted in the trace.
+	end       time.Duration // When replaying a trace, when the cell completed in the trace.
+}
+
+// A simulated session, along with its kernel.
+type session struct {
+	id        string
+	tenant    string
+	kernelId  string
+	resources *gateway.ResourceSpec
+	cells     []*cell
+
+	cellArrivals arrival.Process      // If set, determines the gaps between cells instead of their think times.
+	trace        *domain.TraceSession // Set if the session is replayed from a trace.
+
+	state       sessionState
+	placed      bool // Whether the session's kernel has been placed on the cluster.
+	cellIdx     int  // Index of the current cell, or -1 if no cell has been submitted yet.
+	inFlight    bool // Whether the current cell has been submitted but has not completed.
+	waited      bool // Whether the session had to wait for room to place its kernel.
+	arrivedAt   time.Duration
+	submittedAt time.Duration // When the current cell was submitted.
+}
+
+func newTenantSession(workload *domain.Workload, tenant *domain.WorkloadTenant, idx int) (*session, error) {
+	id := fmt.Sprintf("%s-%d", tenant.Name, idx)
+
+	sess := &session{
+		id:        id,
+		tenant:    tenant.Name,
+		kernelId:  id,
+		resources: tenant.PeakResources(),
+		cells:     make([]*cell, 0, len(tenant.Cells)),
+		cellIdx:   -1,
+	}
+
+	if sess.resources == nil {
+		sess.resources = &gateway.ResourceSpec{}
+	}
+
+	// Seeded exactly as by the workload engine, so that the gaps between cells are the same.
+	if tenant.CellArrival != nil {
+		var err error
+		sess.cellArrivals, err = arrival.New(tenant.CellArrival, arrival.DeriveSeed(workload.Seed, tenant.Name, "cells", strconv.Itoa(idx)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The kernel is created with the peak resources of the session, whereas each cell binds only the GPUs it requests.
+	for _, c := range tenant.Cells {
+		var gpus int32
+		if c.Resources != nil {
+			gpus = c.Resources.Gpu
+		} else if tenant.Resources != nil {
+			gpus = tenant.Resources.Gpu
+		}
+
+		sess.cells = append(sess.cells, &cell{
+			gpus:      gpus,
+			duration:  c.Duration,
+			thinkTime: c.ThinkTime,
+		})
+	}
+
+	return sess, nil
+}
+
+func newTraceSession(traceSession *domain.TraceSession) *session {
+	id := fmt.Sprintf("%s-%s", traceTenant, traceSession.Id)
+
+	sess := &session{
+		id:        id,
+		tenant:    traceTenant,
+		kernelId:  id,
+		resources: traceSession.PeakResources(),
+		cells:     make([]*cell, 0, len(traceSession.Cells)),
+		trace:     traceSession,
+		cellIdx:   -1,
+	}
+
+	for _, c := range traceSession.Cells {
+		sess.cells = append(sess.cells, &cell{
+			gpus:     c.Gpu,
+			duration: c.End - c.Start,
+			start:    c.Start,
+			end:      c.End,
+		})
+	}
+
+	return sess
+}
+
+// Return true if the session's current cell is executing or waiting to execute.
+func (sess *session) executing() bool {
+	return sess.state == sessionLive && sess.inFlight
+}
+
+func (sess *session) currentCell() *cell {
+	return sess.cells[sess.cellIdx]
+}
+
+// Return how late something happened relative to the given time of the session's trace, or nil if the session isn't replayed from a trace.
+func (s *Simulator) drift(sess *session, scheduled time.Duration) *time.Duration {
+	if sess.trace == nil {
+		return nil
+	}
+
+	drift := s.now - scheduled
+	return &drift
+}
+
+// The session has arrived. Place its kernel, or wait for room to do so.
+func (s *Simulator) arrive(sess *session) {
+	sess.state = sessionCreating
+	sess.arrivedAt = s.now
+
+	if !s.placeable(sess.resources) {
+		s.fail(sess, -1, fmt.Errorf("%w: session %s", ErrUnplaceable, sess.id))
+		sess.state = sessionStopped
+		return
+	}
+
+	if !s.place(sess) {
+		sess.waited = true
+		s.pending = append(s.pending, sess)
+	}
+}
+
+// Try to place the session's kernel. If it is placed, then the kernel becomes ready after the scheduling delay.
+// Returns false if there isn't room for the kernel.
+func (s *Simulator) place(sess *session) bool {
+	if _, err := s.cluster.CreateKernel(sess.kernelId, s.opts.NumReplicas, sess.resources); err != nil {
+		return false
+	}
+
+	sess.placed = true
+	s.report.PeakKernels = max(s.report.PeakKernels, s.cluster.NumKernels())
+
+	if sess.waited {
+		s.report.placementWaits = append(s.report.placementWaits, s.now-sess.arrivedAt)
+	}
+
+	s.after(s.delay(s.opts.SchedulingDelay), func() { s.ready(sess) })
+
+	return true
+}
+
+// The session's kernel has started.
+func (s *Simulator) ready(sess *session) {
+	sess.state = sessionLive
+
+	var drift *time.Duration
+	if sess.trace != nil {
+		drift = s.drift(sess, sess.trace.Start)
+	}
+
+	s.observe(metrics.OpCreateSession, sess.kernelId, sess.arrivedAt, nil)
+	s.record(domain.WorkloadEventSessionCreated, sess, -1, drift, nil)
+
+	s.nextCell(sess)
+}
+
+// Submit the session's next cell, or stop the session if it has no more cells.
+func (s *Simulator) nextCell(sess *session) {
+	idx := sess.cellIdx + 1
+
+	if idx >= len(sess.cells) {
+		// A replayed session is held until it ended in the trace.
+		if sess.trace != nil && sess.trace.End > s.now {
+			s.schedule(sess.trace.End, func() { s.stop(sess) })
+		} else {
+			s.stop(sess)
+		}
+		return
+	}
+
+	// If a previous cell (or the creation of the kernel) ran late, then a replayed cell is submitted immediately.
+	if sess.trace != nil && sess.cells[idx].start > s.now {
+		s.schedule(sess.cells[idx].start, func() { s.submit(sess, idx) })
+	} else {
+		s.submit(sess, idx)
+	}
+}
+
+func (s *Simulator) submit(sess *session, idx int) {
+	sess.cellIdx = idx
+	sess.inFlight = true
+	sess.submittedAt = s.now
+
+	c := sess.currentCell()
+	s.record(domain.WorkloadEventCellSubmitted, sess, idx, s.drift(sess, c.start), nil)
+
+	if c.gpus > s.opts.Hosts.Capacity.Gpu {
+		s.fail(sess, idx, fmt.Errorf("%w: cell %d of session %s", ErrCellTooBig, idx, sess.id))
+		s.stop(sess)
+		return
+	}
+
+	s.execute(sess)
+}
+
+// Start executing the session's current cell if one of its kernel's replicas has enough idle GPUs. Otherwise, migrate
+// a replica to a host that does, or, if there is no such host, wait for GPUs to be released.
+func (s *Simulator) execute(sess *session) {
+	c := sess.currentCell()
+
+	_, err := s.cluster.BindExecutor(sess.kernelId, c.gpus)
+	if err == nil {
+		s.report.cellWaits = append(s.report.cellWaits, s.now-sess.submittedAt)
+		s.after(c.duration, func() { s.complete(sess) })
+		return
+	}
+
+	if !errors.Is(err, cluster.ErrInsufficientResources) {
+		s.fail(sess, sess.cellIdx, err)
+		s.stop(sess)
+		return
+	}
+
+	replicaId, target, err := s.cluster.PlanMigration(sess.kernelId, c.gpus)
+	if err != nil {
+		s.waiting = append(s.waiting, sess)
+		return
+	}
+
+	start := s.now
+	s.report.Migrations++
+	s.after(s.delay(s.opts.MigrationDelay), func() {
+		_, err := s.cluster.MigrateReplica(sess.kernelId, replicaId, target)
+		s.observe(metrics.OpMigrateKernelReplica, sess.kernelId, start, err)
+		if err != nil {
+			// The target filled up in the meantime. We'll try again.
+			s.report.FailedMigrations++
+		} else {
+			// The replica's old host may now have room for a pending kernel.
+			s.retryPending()
+		}
+
+		s.execute(sess)
+	})
+}
+
+// The session's current cell has finished executing.
+func (s *Simulator) complete(sess *session) {
+	c := sess.currentCell()
+
+	if err := s.cluster.ReleaseExecutor(sess.kernelId); err != nil {
+		panic(err) // The kernel can only be destroyed by the session itself.
+	}
+
+	sess.inFlight = false
+	s.observe(metrics.OpExecuteCode, sess.kernelId, sess.submittedAt, nil)
+	s.record(domain.WorkloadEventCellCompleted, sess, sess.cellIdx, s.drift(sess, c.end), nil)
+
+	// The GPUs that were committed to the cell may let a waiting cell execute.
+	s.retryWaiting()
+
+	// Hold the session for the think time before moving on to the next cell.
+	thinkTime := c.thinkTime
+	if sess.cellArrivals != nil && sess.cellIdx+1 < len(sess.cells) {
+		if thinkTime = sess.cellArrivals.Next(); thinkTime == arrival.Never {
+			s.stop(sess)
+			return
+		}
+	}
+
+	s.after(thinkTime, func() { s.nextCell(sess) })
+}
+
+// Tear the session down, destroying its kernel.
+func (s *Simulator) stop(sess *session) {
+	if sess.state == sessionStopped {
+		return
+	}
+
+	if sess.placed {
+		if err := s.cluster.DestroyKernel(sess.kernelId); err != nil {
+			panic(err)
+		}
+		sess.placed = false
+	}
+
+	wasLive := sess.state == sessionLive
+	sess.state = sessionStopped
+
+	if wasLive {
+		var drift *time.Duration
+		if sess.trace != nil {
+			drift = s.drift(sess, sess.trace.End)
+		}
+		s.record(domain.WorkloadEventSessionStopped, sess, -1, drift, nil)
+	}
+
+	s.retryPending()
+	s.retryWaiting()
+}
+
+// Place the kernels of pending sessions, in order of arrival, until one doesn't fit.
+func (s *Simulator) retryPending() {
+	for len(s.pending) > 0 && !s.aborted {
+		if !s.place(s.pending[0]) {
+			return
+		}
+
+		s.pending[0] = nil
+		s.pending = s.pending[1:]
+	}
+}
+
+// Try to execute the cells that are waiting for idle GPUs, in order of submission.
+func (s *Simulator) retryWaiting() {
+	if s.aborted {
+		return
+	}
+
+	waiting := s.waiting
+	s.waiting = nil
+
+	for _, sess := range waiting {
+		if sess.executing() {
+			s.execute(sess)
+		}
+	}
+}
+
+// Record that the session failed.
+func (s *Simulator) fail(sess *session, cellIndex int, err error) {
+	s.record(domain.WorkloadEventError, sess, cellIndex, nil, err)
+	s.errs = append(s.errs, err)
+}
@@ -0,0 +1,398 @@
+package simulator
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/arrival"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/cluster"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/export"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/metrics"
+)
+
+const (
+	// Value of WorkloadEvent.Tenant for the sessions of a trace. Matches the trace replayer.
+	traceTenant = "trace"
+)
+
+var (
+	// Virtual time starts here, so that the timestamps of a simulation are recognizably not wall-clock times.
+	epoch = time.Unix(0, 0).UTC()
+
+	ErrUnplaceable = errors.New("the kernel cannot be placed on any host, even an empty one")
+	ErrCellTooBig  = errors.New("the cell requires more GPUs than any host has")
+)
+
+// Configures the simulated cluster.
+type Options struct {
+	Hosts           cluster.HostSpec
+	NumReplicas     int                     // Number of replicas of each kernel.
+	Policy          cluster.PlacementPolicy // Decides where replicas are placed.
+	SchedulingDelay time.Duration           // Mean time taken to create a kernel's replicas. Delays are exponentially distributed.
+	MigrationDelay  time.Duration           // Mean time taken to migrate a replica. Delays are exponentially distributed.
+	SampleInterval  time.Duration           // How often the utilization of the hosts is sampled, in virtual time.
+	Seed            int64                   // Seed for the cluster and the delays. The workload's arrivals are seeded by the workload's own seed.
+}
+
+func DefaultOptions() *Options {
+	return &Options{
+		Hosts:           cluster.HostSpec{NumHosts: 16, Capacity: cluster.DefaultHostCapacity()},
+		NumReplicas:     3,
+		Policy:          cluster.PlacementLeastLoaded,
+		SchedulingDelay: time.Second * 5,
+		MigrationDelay:  time.Second * 10,
+		SampleInterval:  time.Second * 10,
+	}
+}
+
+// Something that happens at a point in virtual time.
+type event struct {
+	at   time.Duration // Offset from the start of the simulation.
+	seq  uint64        // Breaks ties, so that simultaneous events happen in the order in which they were scheduled.
+	fire func()
+}
+
+// A min-heap of events, ordered by time.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return e
+}
+
+// Runs a workload against a simulated cluster in virtual time. Sessions arrive and execute their cells exactly as
+// they would when the workload is driven for real, but kernels are placed on simulated hosts with finite capacity,
+// a cell can only execute once one of its kernel's replicas has enough idle GPUs, and a replica may have to be
+// migrated first. A run takes seconds rather than hours, and is deterministic for a given workload and options.
+// A Simulator is NOT safe for concurrent use, and can only be run once.
+type Simulator struct {
+	workload *domain.Workload
+	opts     *Options
+
+	clock    *cluster.VirtualClock
+	cluster  *cluster.FakeCluster
+	rng      *rand.Rand // Draws the simulated delays.
+	queue    eventQueue
+	seq      uint64
+	now      time.Duration
+	sessions []*session
+
+	pending []*session // Sessions waiting for room to place their kernels, in order of arrival.
+	waiting []*session // Sessions whose current cell is waiting for idle GPUs, in order of submission.
+
+	events   []*domain.WorkloadEvent
+	recorder *metrics.Recorder
+	nodes    []*export.NodeSample
+	report   *Report
+	errs     []error
+	aborted  bool // Set once the workload has run out of time.
+}
+
+func New(workload *domain.Workload, opts *Options) (*Simulator, error) {
+	if !opts.Policy.Valid() {
+		return nil, fmt.Errorf("unknown placement policy \"%s\"", opts.Policy)
+	}
+
+	if opts.Hosts.NumHosts < 1 || opts.Hosts.Capacity == nil {
+		return nil, fmt.Errorf("the simulated cluster must have at least one host")
+	}
+
+	if opts.NumReplicas < 1 {
+		return nil, fmt.Errorf("kernels must have at least one replica")
+	}
+
+	if opts.SampleInterval <= 0 {
+		return nil, fmt.Errorf("the sample interval must be positive")
+	}
+
+	clock := cluster.NewVirtualClock(epoch)
+
+	s := &Simulator{
+		workload: workload,
+		opts:     opts,
+		clock:    clock,
+		cluster:  cluster.NewSimulatedCluster(opts.Hosts, opts.Policy, clock, arrival.DeriveSeed(opts.Seed, "cluster")),
+		rng:      rand.New(rand.NewSource(arrival.DeriveSeed(opts.Seed, "delays"))),
+		queue:    make(eventQueue, 0),
+		events:   make([]*domain.WorkloadEvent, 0),
+		recorder: metrics.NewRecorder(),
+		nodes:    make([]*export.NodeSample, 0),
+		report: &Report{
+			Policy:      opts.Policy,
+			NumHosts:    opts.Hosts.NumHosts,
+			NumReplicas: opts.NumReplicas,
+		},
+	}
+
+	var err error
+	if workload.Trace != nil {
+		err = s.scheduleTrace()
+	} else {
+		err = s.scheduleTenants()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Schedule the arrival of every session of every tenant, as the workload engine would.
+func (s *Simulator) scheduleTenants() error {
+	for _, tenant := range s.workload.Tenants {
+		offsets := make([]time.Duration, tenant.NumSessions)
+		if tenant.Arrival != nil {
+			process, err := arrival.New(tenant.Arrival, arrival.DeriveSeed(s.workload.Seed, tenant.Name))
+			if err != nil {
+				return err
+			}
+			offsets = arrival.Offsets(process, tenant.NumSessions)
+		}
+
+		for i, offset := range offsets {
+			if offset == arrival.Never {
+				break
+			}
+
+			sess, err := newTenantSession(s.workload, tenant, i)
+			if err != nil {
+				return err
+			}
+
+			s.addSession(sess, offset)
+		}
+	}
+
+	return nil
+}
+
+// Schedule the arrival of every session of the trace. The trace is replayed in virtual time, so its speedup is ignored.
+func (s *Simulator) scheduleTrace() error {
+	if s.workload.Trace.Trace == nil {
+		return fmt.Errorf("the trace of workload \"%s\" has not been loaded", s.workload.Name)
+	}
+
+	for _, traceSession := range s.workload.Trace.Trace.Sessions {
+		s.addSession(newTraceSession(traceSession), traceSession.Start)
+	}
+
+	return nil
+}
+
+func (s *Simulator) addSession(sess *session, arrivesAt time.Duration) {
+	s.sessions = append(s.sessions, sess)
+	s.schedule(arrivesAt, func() { s.arrive(sess) })
+}
+
+// Schedule fire to be called at the given time, which must not be in the past.
+func (s *Simulator) schedule(at time.Duration, fire func()) {
+	s.seq++
+	heap.Push(&s.queue, &event{at: at, seq: s.seq, fire: fire})
+}
+
+// Schedule fire to be called after the given delay.
+func (s *Simulator) after(delay time.Duration, fire func()) {
+	s.schedule(s.now+delay, fire)
+}
+
+// Draw an exponentially-distributed delay with the given mean.
+func (s *Simulator) delay(mean time.Duration) time.Duration {
+	if mean <= 0 {
+		return 0
+	}
+
+	return time.Duration(s.rng.ExpFloat64() * float64(mean))
+}
+
+// Return the virtual wall-clock time corresponding to an offset from the start of the simulation.
+func (s *Simulator) timeAt(offset time.Duration) time.Time {
+	return epoch.Add(offset)
+}
+
+// Run the simulation to completion. Returns the results in the same form as a real run, along with a report on
+// the simulated cluster. The returned error joins the errors encountered by the individual sessions.
+func (s *Simulator) Run() (*export.Results, *Report, error) {
+	wallStart := time.Now()
+
+	run := &domain.WorkloadRun{
+		Id:          fmt.Sprintf("simulation-%d", s.opts.Seed),
+		Workload:    s.workload.Name,
+		State:       domain.WorkloadRunRunning,
+		NumSessions: s.workload.NumSessions(),
+		CreatedAt:   epoch,
+		StartedAt:   &epoch,
+		Valid:       true,
+	}
+
+	var nextSample time.Duration
+	for s.queue.Len() > 0 {
+		e := heap.Pop(&s.queue).(*event)
+
+		if s.workload.Duration > 0 && e.at > s.workload.Duration {
+			s.sampleUntil(&nextSample, s.workload.Duration)
+			s.now = s.workload.Duration
+			s.clock.Set(s.timeAt(s.now))
+			s.abort()
+			break
+		}
+
+		s.sampleUntil(&nextSample, e.at)
+		s.now = e.at
+		s.clock.Set(s.timeAt(s.now))
+		s.report.NumEvents++
+
+		e.fire()
+	}
+
+	// Take a final sample once everything has been torn down, unless one was just taken.
+	if s.now > nextSample-s.opts.SampleInterval {
+		s.sample()
+	}
+
+	// Sessions whose kernels could never be placed are still pending.
+	for _, sess := range s.pending {
+		s.fail(sess, -1, fmt.Errorf("%w: the workload ended while session %s was waiting for room", cluster.ErrInsufficientResources, sess.id))
+		sess.state = sessionStopped
+	}
+	s.pending = nil
+
+	finishedAt := s.timeAt(s.now)
+	run.FinishedAt = &finishedAt
+	run.State = domain.WorkloadRunFinished
+	for _, event := range s.events {
+		switch event.Type {
+		case domain.WorkloadEventSessionStopped:
+			run.SessionsCompleted++
+		case domain.WorkloadEventCellCompleted:
+			run.CellsCompleted++
+		case domain.WorkloadEventError:
+			run.NumErrors++
+		}
+	}
+
+	err := errors.Join(s.errs...)
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	s.report.finish(s.now, run)
+	s.report.WallTime = time.Since(wallStart)
+
+	results := &export.Results{
+		Run:       run,
+		Events:    s.events,
+		Samples:   s.recorder.Samples(),
+		Latencies: s.recorder.Report(),
+		Nodes:     s.nodes,
+	}
+
+	return results, s.report, err
+}
+
+// Sample the hosts at every sample time up to and including the given time.
+func (s *Simulator) sampleUntil(next *time.Duration, until time.Duration) {
+	for *next <= until {
+		s.now = *next
+		s.clock.Set(s.timeAt(s.now))
+		s.sample()
+		*next += s.opts.SampleInterval
+	}
+}
+
+// Record the utilization of every host at the current time.
+func (s *Simulator) sample() {
+	nodes := s.cluster.Nodes()
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		s.nodes = append(s.nodes, export.NewNodeSample(s.timeAt(s.now), nodes[id]))
+	}
+}
+
+// The workload has run out of time. Abort the sessions that are still running, as the workload engine would.
+// Each interrupted session records an error event, but the run's error only includes the deadline once.
+func (s *Simulator) abort() {
+	err := context.DeadlineExceeded
+	s.aborted = true
+
+	for _, sess := range s.sessions {
+		switch sess.state {
+		case sessionCreating:
+			s.record(domain.WorkloadEventError, sess, -1, nil, err)
+			s.stop(sess)
+		case sessionLive:
+			if sess.executing() {
+				s.record(domain.WorkloadEventError, sess, sess.cellIdx, nil, err)
+			}
+			s.stop(sess)
+		}
+	}
+
+	s.pending = nil
+	s.waiting = nil
+	s.errs = append(s.errs, err)
+}
+
+func (s *Simulator) record(eventType domain.WorkloadEventType, sess *session, cellIndex int, drift *time.Duration, err error) {
+	event := &domain.WorkloadEvent{
+		Timestamp: s.timeAt(s.now),
+		Type:      eventType,
+		Tenant:    sess.tenant,
+		SessionId: sess.id,
+		KernelId:  sess.kernelId,
+		CellIndex: cellIndex,
+		Drift:     drift,
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	s.events = append(s.events, event)
+}
+
+// Record the latency of an operation that started at the given time and has just completed.
+func (s *Simulator) observe(operation string, kernelId string, start time.Duration, err error) {
+	s.recorder.Record(&metrics.Sample{
+		Timestamp: s.timeAt(start),
+		Operation: operation,
+		KernelId:  kernelId,
+		Outcome:   metrics.OutcomeOf(err),
+		Latency:   s.now - start,
+	})
+}
+
+// Return true if a kernel with the given resources would fit on an empty host.
+func (s *Simulator) placeable(resources *gateway.ResourceSpec) bool {
+	capacity := s.opts.Hosts.Capacity
+	return s.opts.NumReplicas <= s.opts.Hosts.NumHosts && resources.Cpu <= capacity.Cpu && resources.Memory <= capacity.Memory
+}
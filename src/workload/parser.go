@@ -0,0 +1,145 @@
+package workload
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrEmptySpec = errors.New("workload specification is empty")
+)
+
+// Read, parse, and validate the workload specification contained in the specified file.
+// The file may be either YAML or JSON (which is a subset of YAML).
+func ParseFile(path string) (*domain.Workload, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parse(data, filepath.Dir(path))
+}
+
+// Parse and validate a YAML or JSON workload specification.
+// If the workload replays a trace, then a relative trace path is resolved against the current working directory.
+//
+// Unknown fields are rejected. Both decoding and validation errors report the line on which they occurred.
+func Parse(data []byte) (*domain.Workload, error) {
+	return parse(data, "")
+}
+
+func parse(data []byte, baseDir string) (*domain.Workload, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	if len(root.Content) == 0 {
+		return nil, ErrEmptySpec
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var workload domain.Workload
+	if err := decoder.Decode(&workload); err != nil {
+		return nil, err
+	}
+
+	// Only a single document is permitted.
+	var extra yaml.Node
+	if err := decoder.Decode(&extra); err != io.EOF {
+		// A document that cannot be decoded has no position of its own, but the decoder's error includes the line.
+		if err != nil {
+			return nil, fmt.Errorf("workload specification must contain exactly one document: %w", err)
+		}
+
+		return nil, fmt.Errorf("line %d: workload specification must contain exactly one document", extra.Line)
+	}
+
+	if err := validate(&workload, newPositions(root.Content[0])); err != nil {
+		return nil, err
+	}
+
+	if workload.Trace != nil {
+		tracePath := workload.Trace.Path
+		if !filepath.IsAbs(tracePath) {
+			tracePath = filepath.Join(baseDir, tracePath)
+		}
+
+		trace, err := ParseTraceFile(tracePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load trace \"%s\": %w", tracePath, err)
+		}
+
+		workload.Trace.Trace = trace
+	}
+
+	return &workload, nil
+}
+
+// Maps the path of each field in a specification (e.g., "tenants[0].arrival.rate") to the node at which it was defined.
+type positions map[string]*yaml.Node
+
+func newPositions(document *yaml.Node) positions {
+	p := make(positions)
+	p.index("", document)
+	return p
+}
+
+func (p positions) index(path string, node *yaml.Node) {
+	p[path] = node
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			p.index(joinPath(path, node.Content[i].Value), node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			p.index(fmt.Sprintf("%s[%d]", path, i), child)
+		}
+	}
+}
+
+// Return the line on which the field at the given path was defined.
+// If the field was omitted, then the line of the closest enclosing field is returned.
+func (p positions) line(path string) int {
+	for {
+		if node, ok := p[path]; ok {
+			return node.Line
+		}
+
+		if path == "" {
+			return 0
+		}
+
+		path = parentPath(path)
+	}
+}
+
+func joinPath(parent string, field string) string {
+	if parent == "" {
+		return field
+	}
+
+	return parent + "." + field
+}
+
+// Return the path of the field that encloses the field at the given path.
+func parentPath(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '.' || path[i] == '[' {
+			return path[:i]
+		}
+	}
+
+	return ""
+}
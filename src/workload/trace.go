@@ -0,0 +1,314 @@
+package workload
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+const (
+	TraceFormatCSV   = "csv"
+	TraceFormatJSONL = "jsonl"
+
+	// Columns (CSV) or keys (JSON Lines) of a trace. Each record describes one cell execution of a session.
+	// Records of sessions that executed no cells leave the cell columns empty.
+	// Times are either seconds relative to the start of the trace or RFC 3339 timestamps.
+	traceColumnSessionId    = "session_id"
+	traceColumnSessionStart = "session_start"
+	traceColumnSessionEnd   = "session_end"
+	traceColumnCellStart    = "cell_start"
+	traceColumnCellEnd      = "cell_end"
+	traceColumnCpu          = "cpu"    // In 1/100 core.
+	traceColumnMemory       = "memory" // In MB.
+	traceColumnGpu          = "gpu"    // In 1/100 GPU.
+)
+
+var (
+	ErrUnknownTraceFormat = errors.New("unknown trace format")
+	ErrEmptyTrace         = errors.New("trace contains no sessions")
+)
+
+// Read a trace from the specified file. The format is inferred from the file's extension.
+func ParseTraceFile(path string) (*domain.Trace, error) {
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		format = TraceFormatCSV
+	case ".jsonl", ".ndjson", ".json":
+		format = TraceFormatJSONL
+	default:
+		return nil, fmt.Errorf("%w: cannot infer the format of trace \"%s\" from its extension", ErrUnknownTraceFormat, path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseTrace(file, format)
+}
+
+// Read a trace in the specified format (TraceFormatCSV or TraceFormatJSONL).
+func ParseTrace(r io.Reader, format string) (*domain.Trace, error) {
+	var records []*traceRecord
+	var err error
+
+	switch format {
+	case TraceFormatCSV:
+		records, err = readCSVTrace(r)
+	case TraceFormatJSONL:
+		records, err = readJSONLTrace(r)
+	default:
+		return nil, fmt.Errorf("%w: \"%s\"", ErrUnknownTraceFormat, format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTrace(records)
+}
+
+// A single row of a trace, prior to having its times normalized.
+type traceRecord struct {
+	line   int
+	fields map[string]string
+}
+
+func (r *traceRecord) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", r.line, fmt.Sprintf(format, args...))
+}
+
+func readCSVTrace(r io.Reader) ([]*traceRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*traceRecord, 0)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		record := &traceRecord{line: line, fields: make(map[string]string, len(header))}
+		for i, column := range header {
+			record.fields[strings.TrimSpace(column)] = strings.TrimSpace(row[i])
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func readJSONLTrace(r io.Reader) ([]*traceRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	records := make([]*traceRecord, 0)
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var values map[string]interface{}
+		if err := decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		record := &traceRecord{line: line, fields: make(map[string]string, len(values))}
+		for key, value := range values {
+			if value != nil {
+				record.fields[key] = fmt.Sprint(value)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+// Parse a time, which is either a number of seconds or an RFC 3339 timestamp.
+// Timestamps are returned as an offset from the Unix epoch; they're normalized against the start of the trace afterwards.
+func parseTraceTime(value string) (time.Duration, bool, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if math.IsNaN(seconds) || math.IsInf(seconds, 0) || seconds < 0 {
+			return 0, false, fmt.Errorf("invalid time \"%s\"", value)
+		}
+
+		return time.Duration(seconds * float64(time.Second)), false, nil
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid time \"%s\": must be a number of seconds or an RFC 3339 timestamp", value)
+	}
+
+	return time.Duration(ts.UnixNano()), true, nil
+}
+
+func parseTraceResource(record *traceRecord, column string) (int32, error) {
+	value := record.fields[column]
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		return 0, record.errorf("invalid %s \"%s\"", column, value)
+	}
+
+	return int32(math.Round(parsed)), nil
+}
+
+func buildTrace(records []*traceRecord) (*domain.Trace, error) {
+	sessions := make(map[string]*domain.TraceSession)
+	order := make([]string, 0)
+
+	var absolute, relative bool // Whether we've seen timestamps and/or relative times. They cannot be mixed.
+	parseTime := func(record *traceRecord, column string) (time.Duration, error) {
+		t, isTimestamp, err := parseTraceTime(record.fields[column])
+		if err != nil {
+			return 0, record.errorf("%s: %v", column, err)
+		}
+
+		absolute = absolute || isTimestamp
+		relative = relative || !isTimestamp
+		if absolute && relative {
+			return 0, record.errorf("%s: cannot mix RFC 3339 timestamps with relative times", column)
+		}
+
+		return t, nil
+	}
+
+	for _, record := range records {
+		sessionId := record.fields[traceColumnSessionId]
+		if sessionId == "" {
+			return nil, record.errorf("%s is required", traceColumnSessionId)
+		}
+
+		start, err := parseTime(record, traceColumnSessionStart)
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := parseTime(record, traceColumnSessionEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		if end < start {
+			return nil, record.errorf("session %s ends before it starts", sessionId)
+		}
+
+		session, ok := sessions[sessionId]
+		if !ok {
+			session = &domain.TraceSession{Id: sessionId, Start: start, End: end, Cells: make([]*domain.TraceCell, 0)}
+			sessions[sessionId] = session
+			order = append(order, sessionId)
+		} else if session.Start != start || session.End != end {
+			return nil, record.errorf("inconsistent start or end time for session %s", sessionId)
+		}
+
+		// Records of sessions without any cells leave the cell columns empty.
+		if record.fields[traceColumnCellStart] == "" && record.fields[traceColumnCellEnd] == "" {
+			continue
+		}
+
+		cell := &domain.TraceCell{}
+		if cell.Start, err = parseTime(record, traceColumnCellStart); err != nil {
+			return nil, err
+		}
+
+		if cell.End, err = parseTime(record, traceColumnCellEnd); err != nil {
+			return nil, err
+		}
+
+		if cell.End < cell.Start {
+			return nil, record.errorf("cell of session %s ends before it starts", sessionId)
+		}
+
+		if cell.Start < session.Start || cell.End > session.End {
+			return nil, record.errorf("cell of session %s does not fall within the session", sessionId)
+		}
+
+		if cell.Cpu, err = parseTraceResource(record, traceColumnCpu); err != nil {
+			return nil, err
+		}
+
+		if cell.Memory, err = parseTraceResource(record, traceColumnMemory); err != nil {
+			return nil, err
+		}
+
+		if cell.Gpu, err = parseTraceResource(record, traceColumnGpu); err != nil {
+			return nil, err
+		}
+
+		session.Cells = append(session.Cells, cell)
+	}
+
+	if len(order) == 0 {
+		return nil, ErrEmptyTrace
+	}
+
+	trace := &domain.Trace{Sessions: make([]*domain.TraceSession, 0, len(order))}
+	for _, sessionId := range order {
+		trace.Sessions = append(trace.Sessions, sessions[sessionId])
+	}
+
+	// Make every time relative to the start of the first session.
+	origin := trace.Sessions[0].Start
+	for _, session := range trace.Sessions {
+		origin = min(origin, session.Start)
+	}
+
+	for _, session := range trace.Sessions {
+		session.Start -= origin
+		session.End -= origin
+
+		for _, cell := range session.Cells {
+			cell.Start -= origin
+			cell.End -= origin
+		}
+
+		sort.Slice(session.Cells, func(i, j int) bool {
+			return session.Cells[i].Start < session.Cells[j].Start
+		})
+	}
+
+	sort.SliceStable(trace.Sessions, func(i, j int) bool {
+		return trace.Sessions[i].Start < trace.Sessions[j].Start
+	})
+
+	return trace, nil
+}
@@ -0,0 +1,262 @@
+package workload
+
+import (
+	"fmt"
+	"strings"
+
+	gateway "github.com/scusemua/djn-workload-driver/m/v2/api/proto"
+	"github.com/scusemua/djn-workload-driver/m/v2/src/domain"
+)
+
+// A single problem found while validating a workload specification.
+type ValidationError struct {
+	Line    int    // Line on which the offending field was defined. Zero if unknown.
+	Path    string // Path of the offending field, e.g., "tenants[0].arrival.rate".
+	Message string // Description of the problem.
+}
+
+func (e *ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", e.Line, e.Path, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// All of the problems found while validating a workload specification.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("invalid workload specification:\n%s", strings.Join(messages, "\n"))
+}
+
+// Validate a workload that was constructed programmatically (and therefore has no line information).
+func Validate(workload *domain.Workload) error {
+	return validate(workload, make(positions))
+}
+
+// Accumulates validation errors.
+type validator struct {
+	positions positions
+	errors    ValidationErrors
+}
+
+func (v *validator) fail(path string, format string, args ...interface{}) {
+	v.errors = append(v.errors, &ValidationError{
+		Line:    v.positions.line(path),
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func validate(workload *domain.Workload, positions positions) error {
+	v := &validator{positions: positions}
+
+	if workload.Version == "" {
+		v.fail("version", "version is required")
+	} else if workload.Version != domain.WorkloadSpecVersion {
+		v.fail("version", "unsupported version \"%s\" (expected \"%s\")", workload.Version, domain.WorkloadSpecVersion)
+	}
+
+	if workload.Name == "" {
+		v.fail("name", "name is required")
+	}
+
+	if workload.Duration < 0 {
+		v.fail("duration", "duration cannot be negative")
+	}
+
+	if workload.Trace != nil {
+		if len(workload.Tenants) > 0 {
+			v.fail("tenants", "tenants cannot be specified when replaying a trace")
+		}
+
+		v.validateTrace("trace", workload.Trace)
+	} else if len(workload.Tenants) == 0 {
+		v.fail("tenants", "at least one tenant is required")
+	}
+
+	tenantNames := make(map[string]struct{}, len(workload.Tenants))
+	for i, tenant := range workload.Tenants {
+		path := fmt.Sprintf("tenants[%d]", i)
+
+		if tenant == nil {
+			v.fail(path, "tenant cannot be empty")
+			continue
+		}
+
+		if _, ok := tenantNames[tenant.Name]; ok {
+			v.fail(path+".name", "duplicate tenant name \"%s\"", tenant.Name)
+		}
+		tenantNames[tenant.Name] = struct{}{}
+
+		v.validateTenant(path, tenant)
+	}
+
+	if len(v.errors) > 0 {
+		return v.errors
+	}
+
+	return nil
+}
+
+func (v *validator) validateTenant(path string, tenant *domain.WorkloadTenant) {
+	if tenant.Name == "" {
+		v.fail(path+".name", "name is required")
+	}
+
+	if tenant.NumSessions <= 0 {
+		v.fail(path+".sessions", "sessions must be positive")
+	}
+
+	if tenant.KernelSpec == "" {
+		v.fail(path+".kernel-spec", "kernel-spec is required")
+	}
+
+	if tenant.Arrival != nil {
+		v.validateArrival(path+".arrival", tenant.Arrival)
+	}
+
+	if tenant.CellArrival != nil {
+		v.validateArrival(path+".cell-arrival", tenant.CellArrival)
+	}
+
+	v.validateResources(path+".resources", tenant.Resources)
+
+	if len(tenant.Cells) == 0 {
+		v.fail(path+".cells", "at least one cell is required")
+	}
+
+	for i, cell := range tenant.Cells {
+		cellPath := fmt.Sprintf("%s.cells[%d]", path, i)
+
+		if cell == nil {
+			v.fail(cellPath, "cell cannot be empty")
+			continue
+		}
+
+		if cell.Duration < 0 {
+			v.fail(cellPath+".duration", "duration cannot be negative")
+		}
+
+		if cell.ThinkTime < 0 {
+			v.fail(cellPath+".think-time", "think-time cannot be negative")
+		}
+
+		if cell.Code == "" && cell.Duration == 0 {
+			v.fail(cellPath, "either code or duration is required")
+		}
+
+		v.validateResources(cellPath+".resources", cell.Resources)
+	}
+}
+
+func (v *validator) validateTrace(path string, trace *domain.WorkloadTrace) {
+	if trace.Path == "" {
+		v.fail(path+".path", "path is required")
+	}
+
+	if trace.Speedup < 0 {
+		v.fail(path+".speedup", "speedup cannot be negative")
+	}
+
+	if trace.KernelSpec == "" {
+		v.fail(path+".kernel-spec", "kernel-spec is required")
+	}
+}
+
+func (v *validator) validateArrival(path string, arrival *domain.WorkloadArrival) {
+	switch arrival.Process {
+	case domain.ArrivalProcessConstant, domain.ArrivalProcessPoisson:
+		v.requirePositive(path+".rate", "rate", arrival.Rate)
+	case domain.ArrivalProcessBursty:
+		v.requireNonNegative(path+".rate", "rate", arrival.Rate)
+		v.requirePositive(path+".burst-rate", "burst-rate", arrival.BurstRate)
+		v.requirePositive(path+".mean-burst", "mean-burst", arrival.MeanBurst.Seconds())
+		v.requirePositive(path+".mean-idle", "mean-idle", arrival.MeanIdle.Seconds())
+	case domain.ArrivalProcessDiurnal:
+		v.requirePositive(path+".rate", "rate", arrival.Rate)
+		v.requireNonNegative(path+".amplitude", "amplitude", arrival.Amplitude)
+		v.requirePositive(path+".period", "period", arrival.Period.Seconds())
+
+		if arrival.Amplitude > arrival.Rate {
+			v.fail(path+".amplitude", "amplitude cannot exceed rate")
+		}
+	case domain.ArrivalProcessStep:
+		v.requireNonNegative(path+".rate", "rate", arrival.Rate)
+
+		if len(arrival.Steps) == 0 {
+			v.fail(path+".steps", "at least one step is required")
+		}
+
+		anyPositive := arrival.Rate > 0
+		for i, step := range arrival.Steps {
+			stepPath := fmt.Sprintf("%s.steps[%d]", path, i)
+
+			if step == nil {
+				v.fail(stepPath, "step cannot be empty")
+				continue
+			}
+
+			v.requireNonNegative(stepPath+".at", "at", step.At.Seconds())
+			v.requireNonNegative(stepPath+".rate", "rate", step.Rate)
+
+			if i > 0 && arrival.Steps[i-1] != nil && step.At <= arrival.Steps[i-1].At {
+				v.fail(stepPath+".at", "steps must be in increasing order of time")
+			}
+
+			anyPositive = anyPositive || step.Rate > 0
+		}
+
+		if !anyPositive {
+			v.fail(path, "at least one rate must be positive")
+		}
+	case domain.ArrivalProcessRamp:
+		v.requireNonNegative(path+".rate", "rate", arrival.Rate)
+		v.requireNonNegative(path+".end-rate", "end-rate", arrival.EndRate)
+		v.requirePositive(path+".ramp-duration", "ramp-duration", arrival.RampDuration.Seconds())
+
+		if arrival.Rate <= 0 && arrival.EndRate <= 0 {
+			v.fail(path, "at least one of rate and end-rate must be positive")
+		}
+	case "":
+		v.fail(path+".process", "process is required")
+	default:
+		v.fail(path+".process", "unknown arrival process \"%s\"", arrival.Process)
+	}
+}
+
+func (v *validator) requirePositive(path string, field string, value float64) {
+	if value <= 0 {
+		v.fail(path, "%s must be positive", field)
+	}
+}
+
+func (v *validator) requireNonNegative(path string, field string, value float64) {
+	if value < 0 {
+		v.fail(path, "%s cannot be negative", field)
+	}
+}
+
+func (v *validator) validateResources(path string, resources *gateway.ResourceSpec) {
+	if resources == nil {
+		return
+	}
+
+	if resources.Cpu < 0 {
+		v.fail(path+".cpu", "cpu cannot be negative")
+	}
+
+	if resources.Memory < 0 {
+		v.fail(path+".memory", "memory cannot be negative")
+	}
+
+	if resources.Gpu < 0 {
+		v.fail(path+".gpu", "gpu cannot be negative")
+	}
+}